@@ -55,7 +55,7 @@ func resolveForge(ctx context.Context, log *log.Logger, globals *globalOptions,
 		return f, nil
 	}
 
-	f, err := guessCurrentForge(ctx, log)
+	f, err := guessCurrentForge(ctx, log, globals.RepoPath)
 	if err == nil {
 		return f, nil
 	}
@@ -91,9 +91,10 @@ func resolveForge(ctx context.Context, log *log.Logger, globals *globalOptions,
 }
 
 // guessCurrentForge attempts to guess the current forge based on the
-// current directory.
-func guessCurrentForge(ctx context.Context, log *log.Logger) (forge.Forge, error) {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+// repository in the given directory.
+// If dir is empty, the current directory is used.
+func guessCurrentForge(ctx context.Context, log *log.Logger, dir string) (forge.Forge, error) {
+	repo, err := git.Open(ctx, dir, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 
+	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
 	"go.abhg.dev/gs/internal/ui"
 	"go.abhg.dev/gs/internal/ui/widget"
@@ -15,11 +18,13 @@ type branchCmd struct {
 	Track    branchTrackCmd    `cmd:"" aliases:"tr" help:"Track a branch"`
 	Untrack  branchUntrackCmd  `cmd:"" aliases:"untr" help:"Forget a tracked branch"`
 	Checkout branchCheckoutCmd `cmd:"" aliases:"co" help:"Switch to a branch"`
+	Info     branchInfoCmd     `cmd:"" aliases:"i" help:"Show information about a branch"`
 
 	// Creation and destruction
 	Create branchCreateCmd `cmd:"" aliases:"c" help:"Create a new branch"`
 	Delete branchDeleteCmd `cmd:"" aliases:"d,rm" help:"Delete a branch"`
 	Fold   branchFoldCmd   `cmd:"" aliases:"fo" help:"Merge a branch into its base"`
+	Unfold branchUnfoldCmd `cmd:"" aliases:"unfo" help:"Restore a folded branch"`
 	Split  branchSplitCmd  `cmd:"" aliases:"sp" help:"Split a branch on commits"`
 
 	// Mutation
@@ -27,6 +32,7 @@ type branchCmd struct {
 	Rename  branchRenameCmd  `cmd:"" aliases:"rn,mv" help:"Rename a branch"`
 	Restack branchRestackCmd `cmd:"" aliases:"r" help:"Restack a branch"`
 	Onto    branchOntoCmd    `cmd:"" aliases:"on" help:"Move a branch onto another branch"`
+	Squash  branchSquashCmd  `cmd:"" aliases:"sq" help:"Squash all commits in a branch into one"`
 
 	// Pull request management
 	Submit branchSubmitCmd `cmd:"" aliases:"s" help:"Submit a branch"`
@@ -118,3 +124,27 @@ func (p *branchPrompt) Run(ctx context.Context, repo *git.Repository, store *sta
 
 	return value, nil
 }
+
+// validateStack verifies that the given branches are in a good state
+// for an operation that rewrites them.
+// All problems found are logged before returning an error,
+// so that they can be fixed in one pass.
+func validateStack(ctx context.Context, log *log.Logger, svc *spice.Service, branches []string) error {
+	err := svc.ValidateStack(ctx, branches)
+	if err == nil {
+		return nil
+	}
+
+	var invalidErr *spice.InvalidStackError
+	if !errors.As(err, &invalidErr) {
+		return fmt.Errorf("validate stack: %w", err)
+	}
+
+	for _, p := range invalidErr.Problems {
+		log.Errorf("%v: %v", p.Branch, p.Err)
+	}
+	if len(invalidErr.Problems) == 1 {
+		return errors.New("found 1 problem")
+	}
+	return fmt.Errorf("found %d problems", len(invalidErr.Problems))
+}
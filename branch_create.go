@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
 	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
 )
 
 type branchCreateCmd struct {
@@ -19,8 +21,12 @@ type branchCreateCmd struct {
 	Below  bool   `help:"Place the branch below the target branch and restack its upstack"`
 	Target string `short:"t" placeholder:"BRANCH" help:"Branch to create the new branch above/below"`
 
+	TrackBase string `name:"track-base" placeholder:"BRANCH" predictor:"trackedBranches" help:"Base the new branch on this branch without checking it out"`
+
 	All     bool   `short:"a" help:"Automatically stage modified and deleted files"`
 	Message string `short:"m" placeholder:"MSG" help:"Commit message"`
+
+	Prefix string `placeholder:"PREFIX" help:"Prefix for generated branch names, e.g. 'feature/'"`
 }
 
 func (*branchCreateCmd) Help() string {
@@ -31,7 +37,11 @@ func (*branchCreateCmd) Help() string {
 		just like 'git commit -a'.
 
 		If a branch name is not provided,
-		it will be generated from the commit message.
+		it will be generated from the commit message,
+		prefixed with --prefix if provided.
+		A number is appended to the name if a branch by that name
+		already exists.
+		A prompt will allow editing the generated name.
 
 		The new branch will use the current branch as its base.
 		Use --target to specify a different base branch.
@@ -72,6 +82,14 @@ func (*branchCreateCmd) Help() string {
 			  ┌─┴ B   │    ┌─┴ B     │   ┌─┴ X
 			┌─┴ A     │  ┌─┴ A       │ ┌─┴ A
 			trunk     │  trunk       │ trunk
+
+		Use --track-base to base the new branch on the given branch
+		no matter what is checked out.
+		The branch must be trunk or a tracked branch.
+		Staged changes are committed on top of its tip,
+		and the new branch is checked out afterwards.
+		If anything fails, the original checkout is restored.
+		--track-base cannot be combined with --target, --insert, or --below.
 	`)
 }
 
@@ -82,12 +100,43 @@ func (cmd *branchCreateCmd) Run(ctx context.Context, log *log.Logger, opts *glob
 	}
 	trunk := store.Trunk()
 
+	// restoreTo is checked out if creating the branch fails.
+	var restoreTo string
+	if cmd.TrackBase != "" {
+		if cmd.Target != "" || cmd.Insert || cmd.Below {
+			return errors.New("--track-base cannot be used with --target, --insert, or --below")
+		}
+
+		if cmd.TrackBase != trunk {
+			if _, err := svc.LookupBranch(ctx, cmd.TrackBase); err != nil {
+				return fmt.Errorf("branch not tracked: %v", cmd.TrackBase)
+			}
+		}
+
+		restoreTo, err = repo.CurrentBranch(ctx)
+		if err != nil {
+			if !errors.Is(err, git.ErrDetachedHead) {
+				return fmt.Errorf("get current branch: %w", err)
+			}
+
+			head, err := repo.Head(ctx)
+			if err != nil {
+				return fmt.Errorf("get HEAD: %w", err)
+			}
+			restoreTo = head.String()
+		}
+		cmd.Target = cmd.TrackBase
+	}
+
 	if cmd.Target == "" {
 		cmd.Target, err = repo.CurrentBranch(ctx)
 		if err != nil {
 			return fmt.Errorf("get current branch: %w", err)
 		}
 	}
+	if restoreTo == "" {
+		restoreTo = cmd.Target
+	}
 
 	diff, err := repo.DiffIndex(ctx, "HEAD")
 	if err != nil {
@@ -141,7 +190,7 @@ func (cmd *branchCreateCmd) Run(ctx context.Context, log *log.Logger, opts *glob
 	// restore the original branch.
 	defer func() {
 		if err != nil {
-			err = errors.Join(err, repo.Checkout(ctx, cmd.Target))
+			err = errors.Join(err, repo.Checkout(ctx, restoreTo))
 		}
 	}()
 
@@ -161,7 +210,10 @@ func (cmd *branchCreateCmd) Run(ctx context.Context, log *log.Logger, opts *glob
 			return fmt.Errorf("get commit subject: %w", err)
 		}
 
-		cmd.Name = spice.GenerateBranchName(subject)
+		cmd.Name, err = cmd.generateName(ctx, repo, subject, opts)
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := repo.CreateBranch(ctx, git.CreateBranchRequest{
@@ -214,3 +266,48 @@ func (cmd *branchCreateCmd) Run(ctx context.Context, log *log.Logger, opts *glob
 
 	return nil
 }
+
+// generateName generates a name for the new branch
+// from the subject of its commit.
+// The name is made unique against existing local branches,
+// and the user is given a chance to edit it if prompts are allowed.
+func (cmd *branchCreateCmd) generateName(
+	ctx context.Context,
+	repo *git.Repository,
+	subject string,
+	opts *globalOptions,
+) (string, error) {
+	generated := spice.GenerateBranchName(subject)
+	if generated == "" {
+		return "", fmt.Errorf("cannot generate a branch name from %q: please provide a name", subject)
+	}
+	generated = cmd.Prefix + generated
+
+	name := generated
+	for i := 2; repo.BranchExists(ctx, name); i++ {
+		name = fmt.Sprintf("%v-%d", generated, i)
+	}
+
+	if !opts.Prompt {
+		return name, nil
+	}
+
+	prompt := ui.NewInput().
+		WithValue(&name).
+		WithTitle("Branch name").
+		WithDescription(fmt.Sprintf("Generated from: %v", subject)).
+		WithValidate(func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return errors.New("branch name cannot be empty")
+			}
+			if repo.BranchExists(ctx, s) {
+				return fmt.Errorf("branch name already taken: %v", s)
+			}
+			return nil
+		})
+	if err := ui.Run(prompt); err != nil {
+		return "", fmt.Errorf("prompt: %w", err)
+	}
+
+	return strings.TrimSpace(name), nil
+}
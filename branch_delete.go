@@ -16,6 +16,11 @@ import (
 type branchDeleteCmd struct {
 	Force  bool   `short:"f" help:"Force deletion of the branch"`
 	Branch string `arg:"" optional:"" help:"Name of the branch to delete" predictor:"branches"`
+
+	// archive keeps the branch's information in the store's archive
+	// instead of deleting it.
+	// This is used when deleting merged branches.
+	archive bool
 }
 
 func (*branchDeleteCmd) Help() string {
@@ -182,7 +187,11 @@ func (cmd *branchDeleteCmd) Run(ctx context.Context, log *log.Logger, opts *glob
 	}
 
 	if tracked {
-		if err := svc.ForgetBranch(ctx, cmd.Branch); err != nil {
+		forget := svc.ForgetBranch
+		if cmd.archive {
+			forget = svc.ArchiveBranch
+		}
+		if err := forget(ctx, cmd.Branch); err != nil {
 			return fmt.Errorf("forget branch %v: %w", cmd.Branch, err)
 		}
 	}
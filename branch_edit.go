@@ -36,7 +36,10 @@ func (*branchEditCmd) Run(ctx context.Context, log *log.Logger, opts *globalOpti
 
 	b, err := svc.LookupBranch(ctx, currentBranch)
 	if err != nil {
-		if errors.Is(err, state.ErrNotExist) {
+		switch {
+		case errors.Is(err, spice.ErrTrunk):
+			return fmt.Errorf("cannot edit trunk branch %s", currentBranch)
+		case errors.Is(err, state.ErrNotExist):
 			return fmt.Errorf("branch not tracked: %s", currentBranch)
 		}
 		return fmt.Errorf("get branch: %w", err)
@@ -2,18 +2,23 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
 	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
 )
 
 type branchFoldCmd struct {
 	Branch string `placeholder:"NAME" help:"Name of the branch" predictor:"trackedBranches"`
+	Into   string `placeholder:"NAME" help:"Branch downstack to fold into, folding the branches in between too" predictor:"trackedBranches"`
+	DryRun bool   `short:"n" help:"Print what would be folded without folding it"`
+	Yes    bool   `short:"y" help:"Don't ask for confirmation before folding"`
 }
 
 func (*branchFoldCmd) Help() string {
@@ -23,6 +28,20 @@ func (*branchFoldCmd) Help() string {
 		Branches above the folded branch will point
 		to the next branch downstack.
 		Use the --branch flag to target a different branch.
+
+		Use --into to fold into a branch further downstack.
+		The branches between the two will be folded as well,
+		and branches above the folded branch will point to
+		the --into branch.
+		Each of the branches in between must have no other branches
+		above it, and all of them must be restacked.
+
+		Use --dry-run to print what would happen
+		without changing the repository.
+
+		In interactive mode, you will be asked to confirm
+		before any branches are folded.
+		Use --yes to skip the confirmation.
 	`)
 }
 
@@ -40,16 +59,12 @@ func (cmd *branchFoldCmd) Run(ctx context.Context, log *log.Logger, opts *global
 		cmd.Branch = currentBranch
 	}
 
-	if err := svc.VerifyRestacked(ctx, cmd.Branch); err != nil {
-		var restackErr *spice.BranchNeedsRestackError
-		switch {
-		case errors.Is(err, state.ErrNotExist):
-			return fmt.Errorf("branch %v not tracked", cmd.Branch)
-		case errors.As(err, &restackErr):
-			return fmt.Errorf("branch %v needs to be restacked before it can be folded", cmd.Branch)
-		default:
-			return fmt.Errorf("verify restacked: %w", err)
-		}
+	if cmd.Branch == store.Trunk() {
+		return fmt.Errorf("cannot fold trunk branch %v", cmd.Branch)
+	}
+
+	if err := validateStack(ctx, log, svc, []string{cmd.Branch}); err != nil {
+		return fmt.Errorf("cannot fold %v: %w", cmd.Branch, err)
 	}
 
 	b, err := svc.LookupBranch(ctx, cmd.Branch)
@@ -57,59 +72,165 @@ func (cmd *branchFoldCmd) Run(ctx context.Context, log *log.Logger, opts *global
 		return fmt.Errorf("get branch: %w", err)
 	}
 
+	into := b.Base
+	folded := []string{cmd.Branch} // top to bottom
+	if cmd.Into != "" && cmd.Into != b.Base {
+		into = cmd.Into
+		folded, err = cmd.foldChain(ctx, svc, store.Trunk(), b.Base, into)
+		if err != nil {
+			return err
+		}
+		if err := validateStack(ctx, log, svc, folded[1:]); err != nil {
+			return fmt.Errorf("cannot fold %v into %v: %w", cmd.Branch, into, err)
+		}
+	}
+
 	aboves, err := svc.ListAbove(ctx, cmd.Branch)
 	if err != nil {
 		return fmt.Errorf("list above: %w", err)
 	}
 
+	if cmd.DryRun {
+		for _, name := range folded {
+			log.Infof("WOULD fold %v into %v", name, into)
+		}
+		for _, above := range aboves {
+			log.Infof("WOULD move %v onto %v", above, into)
+		}
+		for _, name := range folded {
+			log.Infof("WOULD delete %v", name)
+		}
+		return nil
+	}
+
+	if !cmd.Yes && opts.Prompt {
+		intoHash, err := repo.PeelToCommit(ctx, into)
+		if err != nil {
+			return fmt.Errorf("resolve %v: %w", into, err)
+		}
+
+		commits, err := repo.ListCommitsDetails(ctx,
+			git.CommitRangeFrom(b.Head).ExcludeFrom(intoHash))
+		if err != nil {
+			return fmt.Errorf("list commits: %w", err)
+		}
+
+		log.Infof("Commits to fold into %v:", into)
+		for _, c := range commits {
+			log.Infof("  %v %v", c.ShortHash, c.Subject)
+		}
+
+		var confirm bool
+		prompt := ui.NewConfirm().
+			WithTitlef("Fold %v into %v?", cmd.Branch, into).
+			WithDescriptionf("%d commit(s) will be merged into %v, and %v will be deleted",
+				len(commits), into, strings.Join(folded, ", ")).
+			WithValue(&confirm)
+		if err := ui.Run(prompt); err != nil {
+			return fmt.Errorf("run prompt: %w", err)
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
 	// Merge base into current branch using a fast-forward.
 	// To do this without checking out the base, we can use a local fetch
 	// and fetch the feature branch "into" the base branch.
+	//
+	// This works for --into as well:
+	// all branches in between are restacked and have nothing else above them,
+	// so the current branch contains all their commits on top of the target.
 	if err := repo.Fetch(ctx, git.FetchOptions{
 		Remote: ".", // local repository
 		Refspecs: []git.Refspec{
-			git.Refspec(cmd.Branch + ":" + b.Base),
+			git.Refspec(cmd.Branch + ":" + into),
 		},
 	}); err != nil {
 		return fmt.Errorf("update base branch: %w", err)
 	}
 
-	newBaseHash, err := repo.PeelToCommit(ctx, b.Base)
+	newBaseHash, err := repo.PeelToCommit(ctx, into)
 	if err != nil {
 		return fmt.Errorf("peel to commit: %w", err)
 	}
 
 	// Change the base of all branches above us
-	// to the base of the branch we are folding.
+	// to the branch we are folding into.
 	upserts := make([]state.UpsertRequest, len(aboves))
 	for i, above := range aboves {
 		upserts[i] = state.UpsertRequest{
 			Name:     above,
-			Base:     b.Base,
+			Base:     into,
 			BaseHash: newBaseHash,
 		}
 	}
 
 	err = store.UpdateBranch(ctx, &state.UpdateRequest{
-		Upserts: upserts,
-		Deletes: []string{cmd.Branch},
-		Message: fmt.Sprintf("folding %v into %v", cmd.Branch, b.Base),
+		Upserts:  upserts,
+		Archives: folded, // for 'branch unfold'
+		Message:  fmt.Sprintf("folding %v into %v", strings.Join(folded, ", "), into),
 	})
 	if err != nil {
 		return fmt.Errorf("upsert branches: %w", err)
 	}
 
-	// Check out base and delete the branch we are folding.
-	if err := (&branchCheckoutCmd{Branch: b.Base}).Run(ctx, log, opts); err != nil {
+	// Check out base and delete the branches we are folding.
+	if err := (&branchCheckoutCmd{Branch: into}).Run(ctx, log, opts); err != nil {
 		return fmt.Errorf("checkout base: %w", err)
 	}
 
-	if err := repo.DeleteBranch(ctx, cmd.Branch, git.BranchDeleteOptions{
-		Force: true, // we know it's merged
-	}); err != nil {
-		return fmt.Errorf("delete branch: %w", err)
+	for _, name := range folded {
+		if err := repo.DeleteBranch(ctx, name, git.BranchDeleteOptions{
+			Force: true, // we know it's merged
+		}); err != nil {
+			return fmt.Errorf("delete branch: %w", err)
+		}
 	}
 
-	log.Infof("Branch %v has been folded into %v", cmd.Branch, b.Base)
+	if len(folded) > 1 {
+		log.Infof("Branches %v have been folded into %v", strings.Join(folded, ", "), into)
+	} else {
+		log.Infof("Branch %v has been folded into %v", cmd.Branch, into)
+	}
 	return nil
 }
+
+// foldChain returns the branches that will be folded into the branch 'into'
+// starting at cmd.Branch and following bases downstack from base.
+// The returned list is ordered from top to bottom,
+// starting with cmd.Branch and not including 'into'.
+//
+// It's an error if 'into' is not downstack from cmd.Branch,
+// or if any branch in between has other branches above it.
+func (cmd *branchFoldCmd) foldChain(
+	ctx context.Context,
+	svc *spice.Service,
+	trunk, base, into string,
+) ([]string, error) {
+	chain := []string{cmd.Branch}
+	for current := base; current != into; {
+		if current == trunk || current == cmd.Branch || slices.Contains(chain, current) {
+			return nil, fmt.Errorf("%v is not downstack from %v", into, cmd.Branch)
+		}
+
+		aboves, err := svc.ListAbove(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("list above %v: %w", current, err)
+		}
+		if len(aboves) > 1 {
+			return nil, fmt.Errorf("cannot fold through %v: %w", current, &spice.NonLinearStackError{
+				Branch: current,
+				Aboves: aboves,
+			})
+		}
+		chain = append(chain, current)
+
+		b, err := svc.LookupBranch(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("get branch %v: %w", current, err)
+		}
+		current = b.Base
+	}
+	return chain, nil
+}
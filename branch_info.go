@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type branchInfoCmd struct {
+	Remote bool   `help:"Also fetch the status of the change request from the forge"`
+	Branch string `arg:"" optional:"" help:"Name of the branch" predictor:"trackedBranches"`
+}
+
+func (*branchInfoCmd) Help() string {
+	return text.Dedent(`
+		Prints everything git-spice knows about a branch:
+		its base, whether it needs to be restacked,
+		its upstream branch, the branches above it,
+		the number of commits in it,
+		and the Change Request associated with it.
+		Defaults to the current branch.
+
+		The URL of the Change Request is shown
+		as recorded when the branch was last submitted.
+		Use --remote to fetch the up-to-date URL and status
+		of the Change Request from the forge.
+	`)
+}
+
+func (cmd *branchInfoCmd) Run(
+	ctx context.Context,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	repo, store, svc, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Branch == "" {
+		currentBranch, err := repo.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("get current branch: %w", err)
+		}
+		cmd.Branch = currentBranch
+	}
+
+	if cmd.Branch == store.Trunk() {
+		return fmt.Errorf("%v is the trunk branch", cmd.Branch)
+	}
+
+	// VerifyRestacked may update the base hash of the branch,
+	// so look up the branch after it.
+	var restacked bool
+	switch err := svc.VerifyRestacked(ctx, cmd.Branch); {
+	case err == nil:
+		restacked = true
+	case errors.As(err, new(*spice.BranchNeedsRestackError)):
+		restacked = false
+	default:
+		return err
+	}
+
+	branch, err := svc.LookupBranch(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("lookup branch: %w", err)
+	}
+
+	commits, err := repo.CountCommits(ctx,
+		git.CommitRangeFrom(branch.Head).ExcludeFrom(branch.BaseHash))
+	if err != nil {
+		return fmt.Errorf("count commits: %w", err)
+	}
+
+	aboves, err := svc.ListAbove(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("list branches above %v: %w", cmd.Branch, err)
+	}
+
+	var change *forge.FindChangeItem
+	if cmd.Remote && branch.Change != nil {
+		remote, err := store.Remote()
+		if err != nil {
+			return fmt.Errorf("get remote: %w", err)
+		}
+
+		remoteRepo, err := openRemoteRepository(ctx, log, secretStash, repo, remote, "")
+		if err != nil {
+			return err
+		}
+
+		change, err = remoteRepo.FindChangeByID(ctx, branch.Change.ChangeID())
+		if err != nil {
+			return fmt.Errorf("find change %v: %w", branch.Change.ChangeID(), err)
+		}
+	}
+
+	return (&branchInfo{
+		Name:      cmd.Branch,
+		Branch:    branch,
+		Restacked: restacked,
+		Commits:   commits,
+		Aboves:    aboves,
+		Change:    change,
+	}).Write(os.Stdout)
+}
+
+// branchInfo is the information printed by 'branch info'.
+type branchInfo struct {
+	Name      string
+	Branch    *spice.LookupBranchResponse
+	Restacked bool
+	Commits   int
+	Aboves    []string
+
+	// Change is the CR associated with the branch
+	// as reported by the forge.
+	// This is nil if the forge was not queried.
+	Change *forge.FindChangeItem
+}
+
+func (b *branchInfo) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	field := func(name string, format string, args ...any) {
+		fmt.Fprintf(tw, "%s:\t"+format+"\n", append([]any{name}, args...)...)
+	}
+
+	field("branch", "%v", b.Name)
+	field("head", "%v", b.Branch.Head.Short())
+	field("base", "%v (%v)", b.Branch.Base, b.Branch.BaseHash.Short())
+	field("restacked", "%v", yesNo(b.Restacked))
+	field("commits", "%v", b.Commits)
+	if b.Branch.UpstreamBranch != "" {
+		field("upstream", "%v", b.Branch.UpstreamBranch)
+	}
+	if len(b.Aboves) > 0 {
+		field("above", "%v", strings.Join(b.Aboves, ", "))
+	}
+
+	if md := b.Branch.Change; md != nil {
+		field("change", "%v", md.ChangeID())
+	}
+	if c := b.Change; c != nil {
+		field("url", "%v", c.URL)
+	} else if url := b.Branch.ChangeURL; url != "" {
+		field("url", "%v", url)
+	}
+	if c := b.Change; c != nil {
+		field("state", "%v", c.State)
+		field("draft", "%v", yesNo(c.Draft))
+	}
+
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -14,6 +14,8 @@ import (
 
 type branchRestackCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch to restack" predictor:"trackedBranches"`
+
+	OntoRemoteBase bool `name:"onto-remote-base" help:"Fetch the base branch from the remote and restack onto that instead of the local base branch"`
 }
 
 func (*branchRestackCmd) Help() string {
@@ -21,11 +23,16 @@ func (*branchRestackCmd) Help() string {
 		The current branch will be rebased onto its base,
 		ensuring a linear history.
 		Use --branch to target a different branch.
+
+		Use --onto-remote-base to fetch the latest version
+		of the base branch from the remote,
+		and rebase onto that instead of the local base branch.
+		The local base branch is not updated.
 	`)
 }
 
 func (cmd *branchRestackCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
-	repo, _, svc, err := openRepo(ctx, log, opts)
+	repo, store, svc, err := openRepo(ctx, log, opts)
 	if err != nil {
 		return err
 	}
@@ -38,6 +45,10 @@ func (cmd *branchRestackCmd) Run(ctx context.Context, log *log.Logger, opts *glo
 		cmd.Branch = currentBranch
 	}
 
+	if cmd.OntoRemoteBase {
+		return cmd.restackOntoRemoteBase(ctx, log, repo, store, svc)
+	}
+
 	res, err := svc.Restack(ctx, cmd.Branch)
 	if err != nil {
 		var rebaseErr *git.RebaseInterruptError
@@ -51,6 +62,8 @@ func (cmd *branchRestackCmd) Run(ctx context.Context, log *log.Logger, opts *glo
 				Branch:  cmd.Branch,
 				Message: fmt.Sprintf("interrupted: restack branch %s", cmd.Branch),
 			})
+		case errors.Is(err, spice.ErrTrunk):
+			return fmt.Errorf("cannot restack trunk branch %v", cmd.Branch)
 		case errors.Is(err, state.ErrNotExist):
 			log.Errorf("%v: branch not tracked: run 'gs branch track'", cmd.Branch)
 			return errors.New("untracked branch")
@@ -64,3 +77,82 @@ func (cmd *branchRestackCmd) Run(ctx context.Context, log *log.Logger, opts *glo
 	log.Infof("%v: restacked on %v", cmd.Branch, res.Base)
 	return nil
 }
+
+// restackOntoRemoteBase fetches the base of the branch from the remote
+// and restacks the branch onto the fetched commit.
+func (cmd *branchRestackCmd) restackOntoRemoteBase(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+) error {
+	remote, err := store.Remote()
+	if err != nil {
+		if errors.Is(err, state.ErrNotExist) {
+			return errors.New("--onto-remote-base requires a remote: set one with 'gs repo init --remote'")
+		}
+		return fmt.Errorf("get remote: %w", err)
+	}
+
+	branch, err := svc.LookupBranch(ctx, cmd.Branch)
+	if err != nil {
+		switch {
+		case errors.Is(err, spice.ErrTrunk):
+			return fmt.Errorf("cannot restack trunk branch %v", cmd.Branch)
+		case errors.Is(err, state.ErrNotExist):
+			log.Errorf("%v: branch not tracked: run 'gs branch track'", cmd.Branch)
+			return errors.New("untracked branch")
+		}
+		return fmt.Errorf("lookup branch: %w", err)
+	}
+
+	// The base may have been pushed under a different name.
+	upstreamBase := branch.Base
+	if branch.Base != store.Trunk() {
+		base, err := svc.LookupBranch(ctx, branch.Base)
+		if err != nil {
+			return fmt.Errorf("lookup base %v: %w", branch.Base, err)
+		}
+		if base.UpstreamBranch != "" {
+			upstreamBase = base.UpstreamBranch
+		}
+	}
+
+	if err := repo.Fetch(ctx, git.FetchOptions{
+		Remote: remote,
+		Refspecs: []git.Refspec{
+			git.Refspec(upstreamBase + ":refs/remotes/" + remote + "/" + upstreamBase),
+		},
+	}); err != nil {
+		return fmt.Errorf("fetch %v: %w", upstreamBase, err)
+	}
+
+	remoteBase := remote + "/" + upstreamBase
+	onto, err := repo.PeelToCommit(ctx, remoteBase)
+	if err != nil {
+		return fmt.Errorf("resolve %v: %w", remoteBase, err)
+	}
+
+	if err := svc.RestackOnto(ctx, cmd.Branch, onto); err != nil {
+		var rebaseErr *git.RebaseInterruptError
+		switch {
+		case errors.As(err, &rebaseErr):
+			// If the rebase is interrupted by a conflict,
+			// we'll resume by re-running this command.
+			return svc.RebaseRescue(ctx, spice.RebaseRescueRequest{
+				Err:     rebaseErr,
+				Command: []string{"branch", "restack", "--onto-remote-base"},
+				Branch:  cmd.Branch,
+				Message: fmt.Sprintf("interrupted: restack branch %s onto %s", cmd.Branch, remoteBase),
+			})
+		case errors.Is(err, spice.ErrAlreadyRestacked):
+			log.Infof("%v: branch is already on top of %v.", cmd.Branch, remoteBase)
+			return nil
+		}
+		return fmt.Errorf("restack branch: %w", err)
+	}
+
+	log.Infof("%v: restacked on %v", cmd.Branch, remoteBase)
+	return nil
+}
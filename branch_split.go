@@ -65,6 +65,10 @@ func (cmd *branchSplitCmd) Run(ctx context.Context, log *log.Logger, opts *globa
 		return fmt.Errorf("cannot split trunk")
 	}
 
+	if err := validateStack(ctx, log, svc, []string{cmd.Branch}); err != nil {
+		return fmt.Errorf("cannot split %v: %w", cmd.Branch, err)
+	}
+
 	branch, err := svc.LookupBranch(ctx, cmd.Branch)
 	if err != nil {
 		return fmt.Errorf("lookup branch %q: %w", cmd.Branch, err)
@@ -100,7 +104,7 @@ func (cmd *branchSplitCmd) Run(ctx context.Context, log *log.Logger, opts *globa
 			commits[i] = widget.CommitSummary{
 				ShortHash:  commit.ShortHash,
 				Subject:    commit.Subject,
-				AuthorDate: commit.AuthorDate,
+				AuthorDate: commit.Author.Time,
 			}
 		}
 
@@ -131,7 +135,7 @@ func (cmd *branchSplitCmd) Run(ctx context.Context, log *log.Logger, opts *globa
 			(&widget.CommitSummary{
 				ShortHash:  commit.ShortHash,
 				Subject:    commit.Subject,
-				AuthorDate: commit.AuthorDate,
+				AuthorDate: commit.Author.Time,
 			}).Render(&desc, widget.DefaultCommitSummaryStyle)
 
 			input := ui.NewInput().
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type branchSquashCmd struct {
+	Message string `short:"m" placeholder:"MSG" help:"Use the given message for the squashed commit"`
+}
+
+func (*branchSquashCmd) Help() string {
+	return text.Dedent(`
+		Squashes all commits in the current branch into a single commit.
+		Branches upstack from this branch will be restacked.
+
+		The message of the new commit combines the messages
+		of the squashed commits from oldest to newest,
+		the same way 'branch submit --fill'
+		builds the body of a Change Request.
+		Use -m to provide a different message.
+
+		The working tree and index are not changed.
+	`)
+}
+
+func (cmd *branchSquashCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
+	repo, store, svc, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	currentBranch, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("get current branch: %w", err)
+	}
+
+	b, err := svc.LookupBranch(ctx, currentBranch)
+	if err != nil {
+		switch {
+		case errors.Is(err, spice.ErrTrunk):
+			return fmt.Errorf("cannot squash trunk branch %s", currentBranch)
+		case errors.Is(err, state.ErrNotExist):
+			return fmt.Errorf("branch not tracked: %s", currentBranch)
+		}
+		return fmt.Errorf("get branch: %w", err)
+	}
+
+	if err := validateStack(ctx, log, svc, []string{currentBranch}); err != nil {
+		return fmt.Errorf("cannot squash %v: %w", currentBranch, err)
+	}
+
+	commits, err := repo.ListCommitsDetails(ctx, fillCommitRange(b.Head, b.BaseHash))
+	if err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+	switch {
+	case len(commits) == 0:
+		return fmt.Errorf("%v: no commits to squash", currentBranch)
+	case len(commits) == 1 && cmd.Message == "":
+		log.Infof("%v: branch already has a single commit", currentBranch)
+		return nil
+	}
+
+	msg := cmd.Message
+	if msg == "" {
+		// With multiple commits, the filled body starts
+		// with the subject of the oldest commit,
+		// so it doubles as a complete commit message.
+		msg = fillBody(commits)
+	}
+
+	tree, err := repo.PeelToTree(ctx, b.Head.String())
+	if err != nil {
+		return fmt.Errorf("resolve tree: %w", err)
+	}
+
+	squashed, err := repo.CommitTree(ctx, git.CommitTreeRequest{
+		Tree:    tree,
+		Message: msg,
+		Parents: []git.Hash{b.BaseHash},
+	})
+	if err != nil {
+		return fmt.Errorf("create squashed commit: %w", err)
+	}
+
+	if err := repo.SetRef(ctx, git.SetRefRequest{
+		Ref:     "refs/heads/" + currentBranch,
+		Hash:    squashed,
+		OldHash: b.Head,
+	}); err != nil {
+		return fmt.Errorf("update branch: %w", err)
+	}
+
+	if err := store.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: []state.UpsertRequest{{
+			Name:     currentBranch,
+			BaseHash: b.BaseHash,
+		}},
+		Message: fmt.Sprintf("%v: squash %d commits", currentBranch, len(commits)),
+	}); err != nil {
+		return fmt.Errorf("update store: %w", err)
+	}
+
+	log.Infof("%v: squashed %d commits", currentBranch, len(commits))
+	return (&upstackRestackCmd{}).Run(ctx, log, opts)
+}
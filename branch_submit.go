@@ -1,9 +1,18 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,37 +30,193 @@ import (
 // submitOptions defines options that are common to all submit commands.
 type submitOptions struct {
 	DryRun bool `short:"n" help:"Don't actually submit the stack"`
+	Check  bool `help:"Like --dry-run, but fail if anything needs to be submitted"`
 	Fill   bool `help:"Fill in the change title and body from the commit messages"`
 	// TODO: Default to Fill if --no-prompt?
-	Draft     *bool `negatable:"" help:"Whether to mark change requests as drafts"`
-	NoPublish bool  `name:"no-publish" help:"Push branches but don't create change requests"`
+	Draft           *bool  `negatable:"" xor:"draft" help:"Whether to mark change requests as drafts"`
+	DraftIfFailing  bool   `name:"draft-if-failing" xor:"draft" help:"Mark open change requests as drafts if their checks are failing, and ready for review if they pass"`
+	DraftNewOnly    bool   `name:"draft-new-only" help:"Apply --[no-]draft only to new change requests, leaving the draft status of open change requests unchanged"`
+	NoPublish       bool   `name:"no-publish" help:"Push branches but don't create change requests"`
+	NoTemplate      bool   `name:"no-template" xor:"template" help:"Don't use a change template for the body of new change requests"`
+	Template        string `name:"template" placeholder:"FILE" xor:"template,template-body" help:"Use the change template with this file name for the body of new change requests"`
+	Comment         string `placeholder:"BODY" xor:"comment" help:"Post a comment on change requests when they're created"`
+	CommentFile     string `name:"comment-file" type:"existingfile" placeholder:"FILE" xor:"comment" help:"Like --comment, but read the comment from a file"`
+	BodyPrepend     string `name:"body-prepend" placeholder:"TEXT" xor:"body-prepend" help:"Add text to the start of the body of new change requests"`
+	BodyPrependFile string `name:"body-prepend-file" type:"existingfile" placeholder:"FILE" xor:"body-prepend" help:"Like --body-prepend, but read the text from a file"`
+	BodyAppend      string `name:"body-append" placeholder:"TEXT" xor:"body-append" help:"Add text to the end of the body of new change requests"`
+	BodyAppendFile  string `name:"body-append-file" type:"existingfile" placeholder:"FILE" xor:"body-append" help:"Like --body-append, but read the text from a file"`
+	LabelDraft      bool   `name:"label-draft" help:"Add a label to change requests marked as drafts, and remove it when they're marked ready for review"`
 
-	Force bool `help:"Force push, bypassing safety checks"`
+	Labels              []string `name:"label" placeholder:"NAME" help:"Add labels to change requests. May be repeated."`
+	LabelRemove         []string `name:"label-remove" placeholder:"NAME" help:"Remove labels from existing change requests"`
+	LabelReadyOnUndraft bool     `name:"label-ready-on-undraft" help:"Add a ready-for-review label and remove a work-in-progress label when change requests are marked ready for review"`
 
-	// TODO: Other creation options e.g.:
-	// - assignees
-	// - labels
-	// - milestone
-	// - reviewers
+	Assignees []string `name:"assignee" placeholder:"NAME" help:"Assign change requests to this user, or to yourself with @me. May be repeated."`
+
+	AllowEmpty        bool  `name:"allow-empty" help:"Submit branches even if they have no commits of their own"`
+	RequireClean      *bool `name:"require-clean" negatable:"" help:"Refuse to submit if there are uncommitted changes"`
+	AbortOnDirtyIndex bool  `name:"abort-on-dirty-index" help:"Refuse to submit the current branch if there are staged but uncommitted changes"`
+	SetUpstream       *bool `name:"set-upstream" negatable:"" help:"Set the pushed branch as the upstream of new branches"`
+	UpdateBaseOnly    bool  `name:"update-base-only" help:"Only retarget existing change requests past merged or deleted bases. Nothing is pushed."`
+
+	CommitMessageLint bool `name:"commit-message-lint" help:"Refuse to submit if commit messages don't match the pattern set in spice.commitLint.pattern"`
+
+	StackCommentCollapse *bool  `name:"stack-comment-collapse" negatable:"" help:"Show the stack in stack comments inside a collapsible block"`
+	StackCommentTarget   string `name:"stack-comment-target" enum:"all,top,bottom," default:"" placeholder:"TARGET" help:"Change requests to post stack comments on: all, top, or bottom"`
+
+	SinceLastSubmit bool `name:"since-last-submit" help:"Report the commits added to change requests since they were last submitted"`
+	CommentDelta    bool `name:"comment-delta" help:"Post a comment on updated change requests listing the commits added since they were last submitted"`
+
+	MergeWhenReady bool              `name:"merge-when-ready" help:"Merge change requests against the trunk once they're ready"`
+	MergeMethod    forge.MergeMethod `name:"merge-method" placeholder:"METHOD" help:"With --merge-when-ready, merge with this method: merge, squash, or rebase"`
+
+	RetriesOnConflict int `name:"retries-on-conflict" placeholder:"N" help:"Retry updating a change request up to N times if it was changed concurrently"`
+
+	Force         bool `help:"Force push, bypassing safety checks"`
+	VerifyCIGreen bool `name:"verify-ci-green" help:"Refuse to push over an existing change request whose checks are passing unless --force is used"`
+
+	Forge string `placeholder:"NAME" predictor:"forges" help:"Name of the forge hosting the repository, if it can't be detected from the remote URL"`
 }
 
 const _submitHelp = `
 Use --dry-run to print what would be submitted without submitting it.
+This includes the stack comments that would be posted or updated
+on existing CRs, along with how they would change.
+Use --check to do the same,
+but exit with status 2 if anything would be submitted.
 For new Change Requests, a prompt will allow filling metadata.
 Use --fill to populate title and body from the commit messages,
 and --[no-]draft to set the draft status.
+Commits merged into a branch from other branches are skipped,
+but merge commits themselves are included.
 Omitting the draft flag will leave the status unchanged of open CRs.
+Use --draft-new-only with --[no-]draft
+to set the draft status of new CRs only,
+leaving that of open CRs unchanged.
+Use --draft-if-failing to instead set the draft status of open CRs
+based on the current state of their checks.
+This requires the forge to report checks for CRs.
 Use --no-publish to push branches without creating CRs.
 This has no effect if a branch already has an open CR.
+Use --no-template to not use a CR template for new CRs.
+Use --template to pick a CR template by its file name
+instead of being prompted for one,
+or getting the first one with --fill.
+--template waits for the forge to list its templates,
+and can't be used with --body.
+Use --label-draft to also add or remove a label
+when the draft status of a CR changes.
+The label is "draft" unless set with 'git config spice.draftLabel'.
+Use --label-ready-on-undraft to add a "ready-for-review" label
+and remove a "wip" label when a draft CR is marked ready for review.
+Set 'git config spice.submit.readyLabel'
+and 'git config spice.submit.wipLabel' to change these labels.
+Use --label to add labels to CRs.
+It may be repeated, or given a comma-separated list of labels.
+Use --label-remove to remove labels from open CRs.
+Labels are added to new CRs when they're created,
+and merged with the labels already on existing CRs.
+Labels that don't exist in the repository are created if possible,
+and skipped with a warning otherwise.
+Removing a label that is not on a CR does nothing.
+Without --label, new CRs get the default labels
+saved with 'gs repo submit-defaults', if any.
+Use --assignee to assign CRs to a user.
+It may be repeated to assign more than one user.
+Use "@me" to assign CRs to the authenticated user.
+New CRs are assigned when they're created.
+Existing CRs are assigned only if nobody is assigned to them yet.
+Use --comment or --comment-file to post a comment on new CRs.
+The comment is posted only once per CR.
+Use --body-prepend and --body-append (or their -file variants)
+to add text to the start or end of the body of new CRs,
+after the body has been filled in or edited.
+These combine with --fill to augment the generated body
+without prompting.
+Branches without any commits of their own are not submitted
+unless --allow-empty is used.
+Use --require-clean to refuse to submit
+if there are uncommitted changes to tracked files.
+Set 'git config spice.submit.requireClean true' to do this by default.
+Staged changes that haven't been committed are not submitted,
+and a warning is logged if the current branch has any.
+Use --abort-on-dirty-index to refuse to submit instead.
+Use --no-set-upstream to not configure the pushed branch
+as the upstream of new branches.
+The name of the pushed branch is still recorded for later submissions.
+Set 'git config spice.submit.setUpstream false' to do this by default.
+Use --update-base-only after a base branch is merged
+to only retarget existing CRs without pushing anything.
+Use --commit-message-lint to refuse to submit branches
+with commit messages that don't match the regular expression
+set with 'git config spice.commitLint.pattern'.
+Use --stack-comment-collapse to show the stack in stack comments
+inside a collapsible block.
+Set 'git config spice.stackComment.collapse true' to do this by default.
+Use --stack-comment-target to post stack comments
+only on the top-most or bottom-most CRs of a stack.
+Set 'git config spice.stackComment.target' to do this by default.
+Stack comments on other CRs are no longer updated.
+Use --since-last-submit to report the commits added to open CRs
+since they were last submitted,
+and --comment-delta to post them as a comment on the CR.
+Stack comments are posted with at most 4 requests to the forge at a time.
+Change this with 'git config spice.forge.concurrency'.
+Use --merge-when-ready to have CRs merged once they're ready,
+for example, after their checks pass.
+CRs are added to the merge queue of the trunk if it has one,
+or set to merge automatically otherwise.
+Use --merge-method to pick how they're merged if there's no merge queue.
+To keep a stack merging in order, only the bottom-most CR,
+the one against the trunk, is enqueued.
+The CRs above it are not enqueued until their bases are merged:
+run 'gs repo sync' after that and submit them again.
+Use --verify-ci-green to refuse to push over
+an existing CR whose checks are currently passing,
+to avoid invalidating a passing run.
+You will be asked to confirm the push if prompts are enabled.
+Use --force to push anyway.
+Use --retries-on-conflict to retry updating a CR
+if someone else changed the CR at the same time.
+The CR is fetched again before each retry
+so that only the changes that are still needed are made.
+The forge hosting the repository is detected from the remote URL.
+Use --forge to pick one explicitly
+if the URL matches more than one forge.
 `
 
 type branchSubmitCmd struct {
 	submitOptions
 
-	Title string `help:"Title of the change request" placeholder:"TITLE"`
-	Body  string `help:"Body of the change request" placeholder:"BODY"`
+	Title string `xor:"title" help:"Title of the change request. Updates the title of existing change requests." placeholder:"TITLE"`
+	Body  string `xor:"template-body" help:"Body of the change request. Replaces the body of existing change requests." placeholder:"BODY"`
+
+	Branch    string `placeholder:"NAME" xor:"branch" help:"Branch to submit" predictor:"trackedBranches"`
+	FromStdin bool   `name:"from-stdin" xor:"branch,output" help:"Submit branches listed on stdin, one per line, and report results as JSON"`
+
+	RetryPrepared bool `name:"retry-prepared" xor:"branch,output" help:"Submit all branches that failed to submit before, reusing the information filled for them"`
+	PrintURL      bool `name:"print-url" xor:"output" help:"Print only the URL of the change request to stdout"`
+
+	AutoRestack   bool `name:"auto-restack" help:"Restack the branch and the branches below it if needed instead of refusing to submit"`
+	BaseHashCheck bool `name:"base-hash-check" help:"Refuse to submit if the branch isn't based on exactly the base commit recorded for it, even with --force"`
+
+	PushHeadRef string `name:"push-head-ref" placeholder:"NAME" help:"Push to this branch on the remote instead, and use it as the head of the change request"`
+
+	NoEdit bool `name:"no-edit" help:"Don't prompt for the change request information. Recover it from a previous failed submission if possible, or use the defaults."`
+
+	AmendCommitRefs bool `name:"amend-commit-refs" help:"Reference the change request in the message of the branch's most recent commit"`
+
+	SyncTitleFromCommit bool `name:"sync-title-from-commit" xor:"title" help:"Update the title of existing change requests to match the commit messages"`
+
+	PrefillChecklist bool `name:"prefill-checklist-from-diff" help:"Add checklist items to the body of new change requests based on the files changed in the branch"`
+	PrefillIssueLink bool `name:"prefill-issue-link" help:"Close the issue referenced in the branch name when new change requests are merged"`
 
-	Branch string `placeholder:"NAME" help:"Branch to submit" predictor:"trackedBranches"`
+	FillTemplateFrontmatter bool `name:"fill-template-frontmatter" help:"With --fill, apply the title prefix and labels declared in the frontmatter of the change template"`
+
+	Reviewers               []string `placeholder:"NAME" help:"Request reviews on new change requests from these users or teams (org/team)"`
+	ReviewersFromCodeowners bool     `name:"reviewers-from-codeowners" help:"Request reviews on new change requests from the owners of the files changed in the branch"`
+
+	Milestone string `name:"milestone" placeholder:"NAME" help:"Attach the change request to this milestone, by title or number"`
 }
 
 func (*branchSubmitCmd) Help() string {
@@ -64,12 +229,161 @@ func (*branchSubmitCmd) Help() string {
 		Use the --title and --body flags to skip the prompt,
 		or the --fill flag to use the commit message to fill them in.
 		The --draft flag marks the change request as a draft.
+		Without --draft or --no-draft, new Change Requests for branches
+		matching a pattern in 'git config spice.submit.draftPatterns'
+		are marked as drafts.
+		Patterns are globs matched against the branch name,
+		and the first matching pattern wins.
+		Prefix a pattern with "!" to mark matching branches
+		as ready for review instead.
+		For example:
+
+			git config --add spice.submit.draftPatterns '!spike/ready-*'
+			git config --add spice.submit.draftPatterns 'spike/*'
+
 		For updating Change Requests,
 		use --draft/--no-draft to change its draft status.
 		Without the flag, the draft status is not changed.
+		Use --title and --body to also change the title and body
+		of an existing Change Request.
+		Use --sync-title-from-commit to instead update the title
+		of an existing Change Request from the commit messages
+		the same way --fill does for new Change Requests.
+		Use --draft-if-failing to instead mark the Change Request
+		as a draft if its checks are failing,
+		and ready for review if they are passing.
+		This requires the forge to report checks for Change Requests.
+		Use --label-draft to also add a label to draft Change Requests,
+		and remove it when they're marked ready for review.
+		The label is "draft" unless configured with
+		'git config spice.draftLabel'.
+		Use --label-ready-on-undraft to add a "ready-for-review" label
+		and remove a "wip" label when a draft Change Request
+		is marked ready for review.
+
+		The body is edited in a file with the ".md" extension.
+		Use 'git config spice.submit.bodyExt' to change this.
+		The body is edited with the Git editor
+		unless a different one is set with
+		'git config spice.submit.editor'.
 
 		Use --no-publish to push the branch without creating a Change
 		Request.
+
+		Use --from-stdin to submit multiple branches listed on stdin,
+		one per line.
+		Branches are submitted in stack order,
+		and a JSON object is printed to stdout for each branch
+		reporting the result of submitting it.
+		Failure to submit a branch does not stop the others
+		from being submitted.
+
+		Use --retry-prepared to submit all branches
+		that failed to submit in an earlier attempt,
+		for example, after a partially failed 'gs stack submit'.
+		Information filled for them in that attempt is reused
+		without prompting, as with --no-edit.
+
+		Use --print-url to print only the URL of the Change Request
+		to stdout after it is submitted,
+		and no informational messages.
+		With --no-prompt and --fill, this allows use in scripts:
+
+			url=$(gs --no-prompt branch submit --fill --print-url)
+
+		Use --no-edit to skip the prompt for new Change Requests.
+		Information filled in a previous failed submission
+		will be used if available.
+		Otherwise, the title and body are filled from the commit messages.
+
+		Branches that need to be restacked are not submitted.
+		Use --auto-restack to restack the branch,
+		and any branches below it that need it, before submitting.
+		If restacking runs into a conflict,
+		resolve it, run 'gs rebase continue',
+		and submit again.
+
+		Use --amend-commit-refs to append a reference to the Change Request
+		to the message of the most recent commit in the branch,
+		and push the amended commit.
+		Nothing changes if the commit already references the Change Request.
+
+		Use --update-base-only after a base branch is merged
+		to only retarget an existing Change Request
+		onto the closest base that was not merged or deleted,
+		or trunk if there isn't one.
+		Nothing is pushed in this mode,
+		so it refuses to run if the branch needs to be pushed
+		unless --force is used.
+
+		Use --prefill-checklist-from-diff to start the body of new
+		Change Requests with checklist items
+		that apply to the files changed in the branch.
+		Items are defined in the .git-spice/checklist file
+		at the head of the branch,
+		or the file configured with 'git config spice.submit.checklistFile'.
+		Each line of the file is a path pattern followed by an item,
+		for example:
+
+			migrations/ Ran the migration in staging
+			*.proto Updated API documentation
+
+		Use --prefill-issue-link to close the issue referenced
+		in the branch name when a new Change Request is merged.
+		A line like "Closes #123" is added to the end of the body
+		unless the body already references the issue.
+		The issue number is extracted from the branch name
+		with the regular expression set in
+		'git config spice.submit.issueRegex',
+		using its first capturing group if it has one.
+		By default, this matches branch names like "issue-123-fix".
+
+		Use --fill-template-frontmatter with --fill to honor
+		the frontmatter of the change template, if it has one.
+		The frontmatter is a YAML block between "---" lines
+		at the top of the template.
+		Its "title" is prefixed to the title of the Change Request,
+		and its "labels" are added to it.
+		The frontmatter is not included in the body.
+		For example:
+
+			---
+			title: "[feature] "
+			labels: enhancement, needs-review
+			---
+			## Summary
+
+		Use --reviewers to request reviews on new Change Requests
+		from a comma-separated list of users or teams.
+		Teams are specified as "org/team".
+		Reviews are never requested from the current user,
+		who may be referred to as "@me".
+		Without --reviewers, reviews are requested from the default reviewers
+		saved with 'gs repo submit-defaults', if any.
+		Use --reviewers-from-codeowners to also request reviews
+		from the owners of the files changed in the branch.
+		Owners are read from the CODEOWNERS file at the head of the branch,
+		looking in .github/, the repository root, and docs/, in that order.
+
+		Use --milestone to attach the Change Request to a milestone
+		when it's created or updated.
+		The milestone is identified by its title or number.
+		If it doesn't exist, nothing is pushed.
+		Forges that don't support milestones ignore this.
+
+		Use --push-head-ref to push the branch to a different branch
+		on the remote, and use that as the head of the Change Request.
+		This is useful to submit a snapshot of the branch for review.
+		The pushed branch is recorded as the branch's upstream,
+		so later submissions update the same Change Request,
+		but the local branch does not track it.
+
+		Use --base-hash-check to refuse to submit a branch
+		unless its base is still at the commit recorded for it
+		and the branch forks from its base at that commit.
+		This applies even with --force,
+		guarding against pushing a branch that wasn't restacked
+		after its base moved.
 	`)
 }
 
@@ -84,23 +398,332 @@ func (cmd *branchSubmitCmd) Run(
 		return err
 	}
 
+	if cmd.PushHeadRef != "" && (cmd.FromStdin || cmd.RetryPrepared) {
+		return errors.New("--push-head-ref can only be used with a single branch")
+	}
+
 	var session submitSession
+	if cmd.FromStdin {
+		return cmd.runBatch(ctx, os.Stdin, os.Stdout, &session, repo, store, svc, secretStash, log, opts)
+	}
+	if cmd.RetryPrepared {
+		return cmd.runRetry(ctx, &session, repo, store, svc, secretStash, log, opts)
+	}
+
+	if cmd.PrintURL {
+		log = quietLogger(log)
+	}
+
 	if err := cmd.run(ctx, &session, repo, store, svc, secretStash, log, opts); err != nil {
 		return err
 	}
 
-	if cmd.DryRun {
-		return nil
+	commentOpts, err := cmd.stackCommentOptions(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	limit, err := session.limiter(ctx, repo)
+	if err != nil {
+		return err
 	}
 
-	return syncStackComments(
+	if err := syncStackComments(
 		ctx,
 		store,
 		svc,
 		session.remoteRepo.Require(),
+		limit,
 		log,
 		session.branches,
-	)
+		commentOpts,
+	); err != nil {
+		return err
+	}
+
+	if cmd.DryRun {
+		return cmd.checkPending(&session)
+	}
+
+	if cmd.PrintURL {
+		return cmd.printURL(ctx, os.Stdout, svc, session.remoteRepo.Require())
+	}
+	return nil
+}
+
+// printURL prints the URL of the CR for the branch to w.
+// Nothing is printed if the branch doesn't have a CR,
+// e.g. if it was submitted with --no-publish.
+func (cmd *branchSubmitCmd) printURL(
+	ctx context.Context,
+	w io.Writer,
+	svc *spice.Service,
+	remoteRepo forge.Repository,
+) error {
+	b, err := svc.LookupBranch(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("lookup branch: %w", err)
+	}
+	if b.Change == nil {
+		return nil
+	}
+
+	// The URL is cached when the branch is submitted,
+	// so the forge only needs to be queried for older branches.
+	url := b.ChangeURL
+	if url == "" {
+		change, err := remoteRepo.FindChangeByID(ctx, b.Change.ChangeID())
+		if err != nil {
+			return fmt.Errorf("find change: %w", err)
+		}
+		url = change.URL
+	}
+
+	_, err = fmt.Fprintln(w, url)
+	return err
+}
+
+// quietLogger returns a copy of the logger
+// that doesn't log informational messages.
+// Debug logging is left alone if it was requested.
+func quietLogger(logger *log.Logger) *log.Logger {
+	logger = logger.With()
+	if logger.GetLevel() == log.InfoLevel {
+		logger.SetLevel(log.WarnLevel)
+	}
+	return logger
+}
+
+// submitBatchResult is the result of submitting a single branch
+// with --from-stdin.
+type submitBatchResult struct {
+	Branch string `json:"branch"`
+
+	// Change is the ID of the CR associated with the branch
+	// if the branch was submitted successfully.
+	Change string `json:"change,omitempty"`
+
+	// Error is the reason the branch could not be submitted.
+	Error string `json:"error,omitempty"`
+}
+
+// runBatch submits the branches listed in stdin in a single session,
+// writing a submitBatchResult to stdout for each.
+func (cmd *branchSubmitCmd) runBatch(
+	ctx context.Context,
+	stdin io.Reader,
+	stdout io.Writer,
+	session *submitSession,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	var names []string // in input order
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+
+	enc := json.NewEncoder(stdout)
+	failed, err := cmd.submitBranches(ctx, names, session, repo, store, svc, secretStash, log, opts,
+		func(res submitBatchResult) error {
+			return enc.Encode(res)
+		})
+	if err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to submit %d of %d branches", len(failed), len(names))
+	}
+	return cmd.checkPending(session)
+}
+
+// runRetry submits all branches that previously failed to submit
+// in a single session.
+// Information filled for them in the failed attempt is reused.
+func (cmd *branchSubmitCmd) runRetry(
+	ctx context.Context,
+	session *submitSession,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	prepared, err := store.ListPreparedBranches(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Information saved for branches that were since deleted
+	// or untracked can't be used anymore.
+	var names []string
+	for _, name := range prepared {
+		if _, err := svc.LookupBranch(ctx, name); err != nil {
+			log.Debugf("%v: Discarding saved information: %v", name, err)
+			if err := store.ClearPreparedBranch(ctx, name); err != nil {
+				log.Warn("Could not clear prepared branch information", "error", err)
+			}
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		log.Infof("No branches to retry")
+		return nil
+	}
+
+	cmd.NoEdit = true
+	var submitted []string
+	failed, err := cmd.submitBranches(ctx, names, session, repo, store, svc, secretStash, log, opts,
+		func(res submitBatchResult) error {
+			if res.Error == "" {
+				submitted = append(submitted, res.Branch)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	if len(submitted) > 0 && !cmd.DryRun {
+		log.Infof("Submitted: %v", strings.Join(submitted, ", "))
+	}
+	if len(failed) > 0 {
+		log.Errorf("Still failing: %v", strings.Join(failed, ", "))
+		return fmt.Errorf("failed to submit %d of %d branches", len(failed), len(names))
+	}
+	return cmd.checkPending(session)
+}
+
+// submitBranches submits the given branches in a single session,
+// calling report with the result of each.
+// Branches are submitted in stack order,
+// and failure to submit one does not stop the others.
+//
+// It returns the names of branches that failed to submit.
+func (cmd *branchSubmitCmd) submitBranches(
+	ctx context.Context,
+	names []string,
+	session *submitSession,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+	report func(submitBatchResult) error,
+) (failed []string, _ error) {
+	// Submit branches in stack order
+	// so that bases are submitted before the branches above them.
+	// All tracked branches are upstack from trunk.
+	tracked, err := svc.ListUpstack(ctx, store.Trunk())
+	if err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	tracked = slices.DeleteFunc(tracked, func(name string) bool {
+		return name == store.Trunk()
+	})
+
+	reportResult := func(res submitBatchResult) error {
+		if res.Error != "" {
+			failed = append(failed, res.Branch)
+		}
+		return report(res)
+	}
+
+	// Report branches we can't submit before submitting anything.
+	for _, name := range names {
+		if slices.Contains(tracked, name) {
+			continue
+		}
+
+		res := submitBatchResult{Branch: name, Error: "branch is not tracked"}
+		if name == store.Trunk() {
+			res.Error = "cannot submit trunk"
+		}
+		if err := reportResult(res); err != nil {
+			return nil, err
+		}
+	}
+
+	// All branches are pushed to the same remote and forge.
+	// Failing to resolve them would fail every branch the same way,
+	// so stop before submitting anything.
+	if slices.ContainsFunc(names, func(name string) bool {
+		return slices.Contains(tracked, name)
+	}) {
+		if _, _, err := cmd.remoteRepository(ctx, session, repo, store, secretStash, log, opts); err != nil {
+			return failed, err
+		}
+	}
+
+	for _, name := range tracked {
+		if !slices.Contains(names, name) {
+			continue
+		}
+
+		branchCmd := *cmd
+		branchCmd.FromStdin = false
+		branchCmd.RetryPrepared = false
+		branchCmd.Branch = name
+
+		res := submitBatchResult{Branch: name}
+		if err := branchCmd.run(ctx, session, repo, store, svc, secretStash, log, opts); err != nil {
+			log.Errorf("%v: %v", name, err)
+			res.Error = err.Error()
+		} else if b, err := svc.LookupBranch(ctx, name); err == nil && b.Change != nil {
+			res.Change = b.Change.ChangeID().String()
+		}
+		if err := reportResult(res); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only successfully submitted branches
+	// get their stack comments synced.
+	submitted := slices.DeleteFunc(slices.Clone(session.branches), func(name string) bool {
+		return slices.Contains(failed, name)
+	})
+	if len(submitted) > 0 {
+		commentOpts, err := cmd.stackCommentOptions(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		limit, err := session.limiter(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		err = syncStackComments(
+			ctx,
+			store,
+			svc,
+			session.remoteRepo.Require(),
+			limit,
+			log,
+			submitted,
+			commentOpts,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return failed, nil
 }
 
 func (cmd *branchSubmitCmd) run(
@@ -113,6 +736,10 @@ func (cmd *branchSubmitCmd) run(
 	log *log.Logger,
 	opts *globalOptions,
 ) error {
+	if err := cmd.verifyClean(ctx, repo, log); err != nil {
+		return err
+	}
+
 	if cmd.Branch == "" {
 		currentBranch, err := repo.CurrentBranch(ctx)
 		if err != nil {
@@ -121,6 +748,10 @@ func (cmd *branchSubmitCmd) run(
 		cmd.Branch = currentBranch
 	}
 
+	if err := cmd.checkStagedChanges(ctx, repo, log); err != nil {
+		return err
+	}
+
 	if cmd.Branch == store.Trunk() {
 		return errors.New("cannot submit trunk")
 	}
@@ -131,8 +762,20 @@ func (cmd *branchSubmitCmd) run(
 	}
 
 	// Refuse to submit if the branch is not restacked.
-	if !cmd.Force {
-		if err := svc.VerifyRestacked(ctx, cmd.Branch); err != nil {
+	// This doesn't matter if we're only changing the base
+	// because nothing will be pushed.
+	if !cmd.Force && !cmd.UpdateBaseOnly {
+		if err := svc.VerifyRestacked(ctx, cmd.Branch); err != nil && cmd.AutoRestack {
+			if err := cmd.autoRestack(ctx, log, repo, svc); err != nil {
+				return err
+			}
+
+			// The branch and its base may have moved.
+			branch, err = svc.LookupBranch(ctx, cmd.Branch)
+			if err != nil {
+				return fmt.Errorf("lookup branch: %w", err)
+			}
+		} else if err != nil {
 			log.Errorf("Branch %s needs to be restacked.", cmd.Branch)
 			log.Errorf("Run the following command to fix this:")
 			log.Errorf("  gs branch restack %s", cmd.Branch)
@@ -142,8 +785,13 @@ func (cmd *branchSubmitCmd) run(
 		}
 	}
 
-	if !cmd.DryRun && !cmd.NoPublish {
-		session.branches = append(session.branches, cmd.Branch)
+	if err := cmd.verifyCommits(ctx, log, repo, branch); err != nil {
+		return err
+	}
+
+	// In dry-run mode, these will only have their stack comments previewed.
+	if !cmd.NoPublish {
+		session.addBranch(cmd.Branch)
 	}
 
 	commitHash, err := repo.PeelToCommit(ctx, cmd.Branch)
@@ -159,16 +807,19 @@ func (cmd *branchSubmitCmd) run(
 		upstreamBranch = branch.UpstreamBranch
 	}
 
-	remote, err := session.remote.Get(func() (string, error) {
-		return ensureRemote(ctx, repo, store, log, opts)
-	})
+	// --push-head-ref pushes to a different branch on the remote.
+	// It's recorded as the upstream branch below
+	// so that later submissions update the same CR.
+	if cmd.PushHeadRef != "" {
+		upstreamBranch = cmd.PushHeadRef
+	}
+
+	remote, remoteRepo, err := cmd.remoteRepository(ctx, session, repo, store, secretStash, log, opts)
 	if err != nil {
 		return err
 	}
 
-	remoteRepo, err := session.remoteRepo.Get(func() (forge.Repository, error) {
-		return openRemoteRepository(ctx, log, secretStash, repo, remote)
-	})
+	milestone, assignees, err := cmd.verifyRemote(ctx, session, remoteRepo)
 	if err != nil {
 		return err
 	}
@@ -214,6 +865,7 @@ func (cmd *branchSubmitCmd) run(
 						Name:           cmd.Branch,
 						ChangeForge:    md.ForgeID(),
 						ChangeMetadata: changeMeta,
+						ChangeURL:      existingChange.URL,
 					},
 				},
 				Message: fmt.Sprintf("%v: associate existing CR", cmd.Branch),
@@ -239,6 +891,27 @@ func (cmd *branchSubmitCmd) run(
 		}
 		// TODO: If the CR is closed, we should treat it as non-existent.
 		existingChange = change
+
+		// Refresh the cached URL so that other commands
+		// can show it without querying the forge.
+		if change.URL != "" && change.URL != branch.ChangeURL && !cmd.DryRun {
+			err := store.UpdateBranch(ctx, &state.UpdateRequest{
+				Upserts: []state.UpsertRequest{
+					{
+						Name:      cmd.Branch,
+						ChangeURL: change.URL,
+					},
+				},
+				Message: fmt.Sprintf("%v: update CR URL", cmd.Branch),
+			})
+			if err != nil {
+				log.Warn("Could not update state", "error", err)
+			}
+		}
+	}
+
+	if cmd.UpdateBaseOnly {
+		return cmd.updateBaseOnly(ctx, log, session, store, svc, remoteRepo, branch, existingChange)
 	}
 
 	// At this point, existingChange is nil only if we need to create a new CR.
@@ -249,6 +922,7 @@ func (cmd *branchSubmitCmd) run(
 			} else {
 				log.Infof("WOULD create a CR for %s", cmd.Branch)
 			}
+			session.addPending(cmd.Branch)
 			return nil
 		}
 
@@ -258,6 +932,7 @@ func (cmd *branchSubmitCmd) run(
 				ctx,
 				log,
 				opts,
+				session,
 				svc,
 				store,
 				repo,
@@ -269,6 +944,11 @@ func (cmd *branchSubmitCmd) run(
 			}
 		}
 
+		setUpstream, err := cmd.setUpstream(ctx, repo)
+		if err != nil {
+			return err
+		}
+
 		pushOpts := git.PushOptions{
 			Remote: remote,
 			Refspec: git.Refspec(
@@ -288,10 +968,11 @@ func (cmd *branchSubmitCmd) run(
 			}
 		}
 
-		err = repo.Push(ctx, pushOpts)
+		pushed, err := repo.Push(ctx, pushOpts)
 		if err != nil {
 			return fmt.Errorf("push branch: %w", err)
 		}
+		logPushResult(log, cmd.Branch, upstreamBranch, pushed)
 
 		// At this point, even if any other operation fails,
 		// we need to save to the state that we pushed the branch
@@ -299,6 +980,7 @@ func (cmd *branchSubmitCmd) run(
 		upsert := state.UpsertRequest{
 			Name:           cmd.Branch,
 			UpstreamBranch: upstreamBranch,
+			UpstreamHash:   commitHash,
 		}
 		defer func() {
 			err := store.UpdateBranch(ctx, &state.UpdateRequest{
@@ -310,29 +992,94 @@ func (cmd *branchSubmitCmd) run(
 			}
 		}()
 
-		upstream := remote + "/" + cmd.Branch
-		if err := repo.SetBranchUpstream(ctx, cmd.Branch, upstream); err != nil {
-			log.Warn("Could not set upstream", "branch", cmd.Branch, "remote", remote, "error", err)
+		if setUpstream && cmd.PushHeadRef == "" {
+			upstream := remote + "/" + cmd.Branch
+			session.mu.Lock()
+			err := repo.SetBranchUpstream(ctx, cmd.Branch, upstream)
+			session.mu.Unlock()
+			if err != nil {
+				log.Warn("Could not set upstream", "branch", cmd.Branch, "remote", remote, "error", err)
+			}
 		}
 
 		if prepared != nil {
-			changeID, err := prepared.Publish(ctx)
+			// Labels are applied when the CR is created.
+			labels := slices.Clone(cmd.Labels)
+			for _, label := range prepared.labels {
+				if !slices.Contains(labels, label) {
+					labels = append(labels, label)
+				}
+			}
+			if cmd.LabelDraft && prepared.draft {
+				label, err := draftLabel(ctx, repo)
+				if err != nil {
+					return err
+				}
+				labels = append(labels, label)
+			}
+			prepared.labels = labels
+			prepared.milestone = cmd.Milestone
+			prepared.assignees = assignees
+
+			if err := session.publishQueue.Wait(ctx, cmd.Branch); err != nil {
+				return err
+			}
+			result, err := prepared.Publish(ctx)
+			session.publishQueue.Done(cmd.Branch)
 			if err != nil {
 				return err
 			}
+			changeID := result.ID
+			upsert.ChangeURL = result.URL
 
+			// Record the CR before anything else can fail
+			// so that the next submit updates it
+			// instead of creating another one.
 			changeMeta, err := remoteRepo.NewChangeMetadata(ctx, changeID)
 			if err != nil {
 				return fmt.Errorf("get change metadata: %w", err)
 			}
-
 			changeIDJSON, err := remoteRepo.Forge().MarshalChangeMetadata(changeMeta)
 			if err != nil {
 				return fmt.Errorf("marshal change ID: %w", err)
 			}
-
 			upsert.ChangeForge = changeMeta.ForgeID()
 			upsert.ChangeMetadata = changeIDJSON
+
+			// The CR exists at this point,
+			// so failures to request reviews, post a comment,
+			// or enable auto-merge are not fatal.
+			if reviewers, err := cmd.changeReviewers(ctx, log, session, repo, remoteRepo, branch.Base); err != nil {
+				log.Warn("Could not determine reviewers", "change", changeID, "error", err)
+			} else if len(reviewers) > 0 {
+				if err := remoteRepo.EditChange(ctx, changeID, forge.EditChangeOptions{
+					AddReviewers: reviewers,
+				}); err != nil {
+					log.Warn("Could not request reviews", "change", changeID, "error", err)
+				}
+			}
+
+			if cmd.AmendCommitRefs {
+				pushed, err := cmd.amendCommitRef(ctx, log, svc, repo, remote, upstreamBranch, commitHash, changeID)
+				if err != nil {
+					return err
+				}
+				upsert.UpstreamHash = pushed
+			}
+
+			if ok, err := cmd.postCustomComment(ctx, log, remoteRepo, changeMeta); err != nil {
+				log.Warn("Could not post comment", "change", changeID, "error", err)
+			} else if ok {
+				changeIDJSON, err := remoteRepo.Forge().MarshalChangeMetadata(changeMeta)
+				if err != nil {
+					return fmt.Errorf("marshal change ID: %w", err)
+				}
+				upsert.ChangeMetadata = changeIDJSON
+			}
+
+			if cmd.MergeWhenReady {
+				cmd.mergeWhenReady(ctx, log, store.Trunk(), remoteRepo, branch.Base, changeID)
+			}
 		} else {
 			log.Infof("Pushed %s", cmd.Branch)
 		}
@@ -344,67 +1091,1193 @@ func (cmd *branchSubmitCmd) run(
 		// Check base and HEAD are up-to-date.
 		pull := existingChange
 		var updates []string
-		if pull.HeadHash != commitHash {
+		alreadyPushed := session.pushed[cmd.Branch] == commitHash
+		if pull.HeadHash != commitHash || alreadyPushed {
 			updates = append(updates, "push branch")
 		}
 		if pull.BaseName != branch.Base {
 			updates = append(updates, "set base to "+branch.Base)
 		}
-		if cmd.Draft != nil && pull.Draft != *cmd.Draft {
-			updates = append(updates, "set draft to "+fmt.Sprint(cmd.Draft))
+
+		wantTitle := cmd.Title
+		if cmd.SyncTitleFromCommit {
+			commits, err := repo.ListCommitsDetails(ctx,
+				fillCommitRange(branch.Head, branch.BaseHash))
+			if err != nil {
+				return fmt.Errorf("list commits: %w", err)
+			}
+			wantTitle = fillTitle(commits)
+		}
+
+		var title, body *string
+		if wantTitle != "" && wantTitle != pull.Subject {
+			title = &wantTitle
+			updates = append(updates, "set title to "+strconv.Quote(wantTitle))
+		}
+		if cmd.Body != "" && cmd.Body != pull.Body {
+			body = &cmd.Body
+			updates = append(updates, "set body")
 		}
 
-		if len(updates) == 0 {
-			log.Infof("CR %v is up-to-date: %s", pull.ID, pull.URL)
-			return nil
+		draft := cmd.Draft
+		if cmd.DraftNewOnly {
+			draft = nil
 		}
+		if cmd.DraftIfFailing {
+			checks, err := remoteRepo.ChangeChecksState(ctx, pull.ID)
+			if err != nil {
+				return fmt.Errorf("get checks for CR %v: %w", pull.ID, err)
+			}
 
-		if cmd.DryRun {
-			log.Infof("WOULD update CR %v:", pull.ID)
-			for _, update := range updates {
-				log.Infof("  - %s", update)
+			// Pending or absent checks leave the draft status as-is.
+			switch checks {
+			case forge.ChecksFailing:
+				draft = new(bool)
+				*draft = true
+			case forge.ChecksPassing:
+				draft = new(bool)
 			}
-			return nil
+			log.Debugf("%v: checks are %v", pull.ID, checks)
 		}
+		var addLabels, removeLabels []string
+		if draft != nil && pull.Draft != *draft {
+			updates = append(updates, "set draft to "+fmt.Sprint(*draft))
+
+			if cmd.LabelDraft {
+				label, err := draftLabel(ctx, repo)
+				if err != nil {
+					return err
+				}
 
-		if pull.HeadHash != commitHash {
-			pushOpts := git.PushOptions{
-				Remote: remote,
-				Refspec: git.Refspec(
-					commitHash.String() + ":refs/heads/" + upstreamBranch,
-				),
-				Force: cmd.Force,
-			}
-			if !cmd.Force {
-				// Force push, but only if the ref is exactly
-				// where we think it is.
-				existingHash, err := repo.PeelToCommit(ctx, remote+"/"+upstreamBranch)
-				if err == nil {
-					pushOpts.ForceWithLease = upstreamBranch + ":" + existingHash.String()
+				if *draft {
+					addLabels = append(addLabels, label)
+				} else {
+					removeLabels = append(removeLabels, label)
 				}
 			}
 
-			if err := repo.Push(ctx, pushOpts); err != nil {
-				log.Error("Push failed. Branch may have been updated by someone else. Try with --force.")
-				return fmt.Errorf("push branch: %w", err)
+			if cmd.LabelReadyOnUndraft && !*draft {
+				ready, wip, err := readyLabels(ctx, repo)
+				if err != nil {
+					return err
+				}
+				addLabels = append(addLabels, ready)
+				removeLabels = append(removeLabels, wip)
 			}
 		}
 
-		if len(updates) > 0 {
+		// Labels that are already on the CR don't need to be added.
+		if add := slices.DeleteFunc(slices.Clone(cmd.Labels), func(label string) bool {
+			return hasLabel(pull.Labels, label)
+		}); len(add) > 0 {
+			addLabels = append(addLabels, add...)
+			updates = append(updates, "add labels "+strings.Join(add, ", "))
+		}
+
+		// Labels that aren't on the CR don't need to be removed.
+		if remove := slices.DeleteFunc(slices.Clone(cmd.LabelRemove), func(label string) bool {
+			return !hasLabel(pull.Labels, label)
+		}); len(remove) > 0 {
+			removeLabels = append(removeLabels, remove...)
+			updates = append(updates, "remove labels "+strings.Join(remove, ", "))
+		}
+
+		var setMilestone string
+		if milestone != nil && !strings.EqualFold(pull.Milestone, milestone.Title) {
+			setMilestone = cmd.Milestone
+			updates = append(updates, "set milestone to "+strconv.Quote(milestone.Title))
+		}
+
+		// CRs that already have assignees are left alone.
+		if len(assignees) > 0 && len(pull.Assignees) == 0 {
+			updates = append(updates, "assign to "+strings.Join(assignees, ", "))
+		}
+
+		if len(updates) == 0 {
+			log.Infof("CR %v is up-to-date: %s", pull.ID, pull.URL)
+			if cmd.MergeWhenReady {
+				cmd.mergeWhenReady(ctx, log, store.Trunk(), remoteRepo, branch.Base, pull.ID)
+			}
+			if cmd.AmendCommitRefs && !cmd.DryRun {
+				pushed, err := cmd.amendCommitRef(ctx, log, svc, repo, remote, upstreamBranch, commitHash, pull.ID)
+				if err != nil {
+					return err
+				}
+				if pushed != commitHash {
+					cmd.recordUpstreamHash(ctx, log, store, upstreamBranch, pushed)
+				}
+			}
+			return nil
+		}
+
+		var delta []git.CommitDetail
+		if (cmd.SinceLastSubmit || cmd.CommentDelta) && (pull.HeadHash != commitHash || alreadyPushed) {
+			// If the branch was already pushed in this session,
+			// the CR is already at the new head.
+			// Compare against where it was before that.
+			changeHead := pull.HeadHash
+			if alreadyPushed {
+				changeHead = cmp.Or(session.pushedFrom[cmd.Branch], changeHead)
+			}
+			delta = cmd.submitDelta(ctx, log, repo, branch, changeHead, commitHash)
+		}
+
+		if cmd.DryRun {
+			log.Infof("WOULD update CR %v:", pull.ID)
+			for _, update := range updates {
+				log.Infof("  - %s", update)
+			}
+			if cmd.SinceLastSubmit && delta != nil {
+				logSubmitDelta(log, cmd.Branch, delta)
+			}
+			if cmd.MergeWhenReady {
+				cmd.mergeWhenReady(ctx, log, store.Trunk(), remoteRepo, branch.Base, pull.ID)
+			}
+			session.addPending(cmd.Branch)
+			return nil
+		}
+
+		if pull.HeadHash != commitHash && !alreadyPushed {
+			if cmd.VerifyCIGreen && !cmd.Force {
+				if err := cmd.verifyCIGreen(ctx, log, opts, remoteRepo, pull.ID); err != nil {
+					return err
+				}
+			}
+
+			pushOpts := git.PushOptions{
+				Remote: remote,
+				Refspec: git.Refspec(
+					commitHash.String() + ":refs/heads/" + upstreamBranch,
+				),
+				Force: cmd.Force,
+			}
+			if !cmd.Force {
+				// Force push, but only if the ref is exactly
+				// where we think it is.
+				existingHash, err := repo.PeelToCommit(ctx, remote+"/"+upstreamBranch)
+				if err == nil {
+					pushOpts.ForceWithLease = upstreamBranch + ":" + existingHash.String()
+				}
+			}
+
+			pushed, err := repo.Push(ctx, pushOpts)
+			if err != nil {
+				log.Error("Push failed. Branch may have been updated by someone else. Try with --force.")
+				return fmt.Errorf("push branch: %w", err)
+			}
+			logPushResult(log, cmd.Branch, upstreamBranch, pushed)
+			cmd.recordUpstreamHash(ctx, log, store, upstreamBranch, commitHash)
+		} else if alreadyPushed {
+			cmd.recordUpstreamHash(ctx, log, store, upstreamBranch, commitHash)
+		}
+
+		if len(updates) > 0 {
 			opts := forge.EditChangeOptions{
-				Base:  branch.Base,
-				Draft: cmd.Draft,
+				Base:         branch.Base,
+				Draft:        draft,
+				Title:        title,
+				Body:         body,
+				AddLabels:    addLabels,
+				RemoveLabels: removeLabels,
+				Milestone:    setMilestone,
+				AddAssignees: assignees,
+			}
+
+			if err := cmd.editChange(ctx, log, remoteRepo, pull.ID, opts); err != nil {
+				return fmt.Errorf("edit CR %v: %w", pull.ID, err)
+			}
+		}
+
+		log.Infof("Updated %v", changeLink(pull.ID, pull.URL))
+
+		if cmd.MergeWhenReady {
+			cmd.mergeWhenReady(ctx, log, store.Trunk(), remoteRepo, branch.Base, pull.ID)
+		}
+
+		if delta != nil {
+			if cmd.SinceLastSubmit {
+				logSubmitDelta(log, cmd.Branch, delta)
+			}
+			if cmd.CommentDelta && len(delta) > 0 {
+				if _, err := remoteRepo.PostChangeComment(ctx, pull.ID, submitDeltaComment(delta)); err != nil {
+					log.Warn("Could not post comment", "change", pull.ID, "error", err)
+				}
+			}
+		}
+
+		if cmd.AmendCommitRefs {
+			pushed, err := cmd.amendCommitRef(ctx, log, svc, repo, remote, upstreamBranch, commitHash, pull.ID)
+			if err != nil {
+				return err
+			}
+			if pushed != commitHash {
+				cmd.recordUpstreamHash(ctx, log, store, upstreamBranch, pushed)
 			}
+		}
+	}
+
+	return nil
+}
+
+// mergeWhenReady enqueues the CR for the branch
+// to be merged once it's ready.
+//
+// Only CRs for branches based on the trunk are enqueued.
+// In a stack, that's the bottom-most unmerged CR.
+// CRs above it must wait for their bases to merge
+// so that the stack merges in order;
+// they'll be enqueued when they're submitted again
+// after they've been moved onto the trunk.
+//
+// Failure to enqueue the CR is not fatal
+// as the CR was already submitted.
+func (cmd *branchSubmitCmd) mergeWhenReady(
+	ctx context.Context,
+	log *log.Logger,
+	trunk string,
+	remoteRepo forge.Repository,
+	base string,
+	changeID forge.ChangeID,
+) {
+	if base != trunk {
+		log.Infof("%v: Not enqueuing %v for merge until %v is merged", cmd.Branch, changeID, base)
+		return
+	}
+
+	if cmd.DryRun {
+		log.Infof("WOULD enqueue %v for merge", changeID)
+		return
+	}
+
+	res, err := remoteRepo.EnqueueMerge(ctx, changeID, cmd.MergeMethod)
+	if err != nil {
+		log.Warn("Could not enqueue CR for merge", "change", changeID, "error", err)
+		return
+	}
+
+	if res.AutoMerge {
+		log.Infof("%v: Enabled auto-merge for %v", cmd.Branch, changeID)
+	} else {
+		log.Infof("%v: Added %v to the merge queue", cmd.Branch, changeID)
+	}
+}
+
+// verifyCommits runs the checks on the commits of a branch
+// that must pass before it's pushed.
+//
+// 'stack submit' runs these for every branch
+// before pushing them all at once,
+// so they must not have side effects.
+func (cmd *branchSubmitCmd) verifyCommits(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	branch *spice.LookupBranchResponse,
+) error {
+	// Nothing is pushed with --update-base-only,
+	// so the history of the branch doesn't matter.
+	if cmd.BaseHashCheck && !cmd.UpdateBaseOnly {
+		if err := cmd.verifyBaseHash(ctx, log, repo, branch); err != nil {
+			return err
+		}
+	}
+
+	// Refuse to submit a branch that has no commits of its own.
+	// This usually means the branch was reset by accident,
+	// and the resulting CR would be empty.
+	if !cmd.AllowEmpty {
+		count, err := repo.CountCommits(ctx,
+			git.CommitRangeFrom(branch.Head).ExcludeFrom(branch.BaseHash))
+		if err != nil {
+			return fmt.Errorf("count commits: %w", err)
+		}
+		if count == 0 {
+			log.Errorf("%v: Branch has no commits on top of %v.", cmd.Branch, branch.Base)
+			log.Errorf("Try again with --allow-empty to submit it anyway.")
+			return errors.New("no commits to submit")
+		}
+	}
+
+	if cmd.CommitMessageLint && !cmd.UpdateBaseOnly {
+		commits := git.CommitRangeFrom(branch.Head).ExcludeFrom(branch.BaseHash)
+		if err := cmd.lintCommitMessages(ctx, log, repo, commits); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyRemote resolves the information about the CR
+// that must be looked up on the forge before anything is pushed
+// so that a typo doesn't leave behind a pushed branch without it.
+// It returns the milestone for the CR, if any,
+// and the users to assign the CR to.
+func (cmd *branchSubmitCmd) verifyRemote(
+	ctx context.Context,
+	session *submitSession,
+	remoteRepo forge.Repository,
+) (milestone *forge.Milestone, assignees []string, err error) {
+	if cmd.UpdateBaseOnly {
+		return nil, nil, nil
+	}
+
+	if cmd.Milestone != "" {
+		milestone, err = remoteRepo.FindMilestone(ctx, cmd.Milestone)
+		if err != nil {
+			if errors.Is(err, forge.ErrMilestoneNotFound) {
+				return nil, nil, fmt.Errorf("milestone %q does not exist", cmd.Milestone)
+			}
+			return nil, nil, fmt.Errorf("find milestone: %w", err)
+		}
+	}
+
+	if len(cmd.Assignees) > 0 {
+		assignees, err = cmd.changeAssignees(ctx, session, remoteRepo)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return milestone, assignees, nil
+}
+
+// verifyBaseHash reports an error if the branch isn't based
+// on exactly the base hash recorded for it:
+// the base must not have moved since,
+// and the branch must fork from the base at that hash.
+func (cmd *branchSubmitCmd) verifyBaseHash(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	branch *spice.LookupBranchResponse,
+) error {
+	baseHead, err := repo.PeelToCommit(ctx, branch.Base)
+	if err != nil {
+		return fmt.Errorf("resolve base %v: %w", branch.Base, err)
+	}
+
+	mergeBase, err := repo.MergeBase(ctx, branch.Base, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("merge base of %v and %v: %w", branch.Base, cmd.Branch, err)
+	}
+
+	if baseHead == branch.BaseHash && mergeBase == branch.BaseHash {
+		return nil
+	}
+
+	if baseHead != branch.BaseHash {
+		log.Errorf("%v: Base %v is at %v, but the branch was based on %v.",
+			cmd.Branch, branch.Base, baseHead.Short(), branch.BaseHash.Short())
+	}
+	if mergeBase != branch.BaseHash {
+		log.Errorf("%v: Branch forks from %v at %v, not at %v.",
+			cmd.Branch, branch.Base, mergeBase.Short(), branch.BaseHash.Short())
+	}
+	log.Errorf("Run the following command to fix this:")
+	log.Errorf("  gs branch restack %s", cmd.Branch)
+	return errors.New("branch is not based on its recorded base hash")
+}
+
+// verifyCIGreen reports an error if the checks of an existing CR
+// are passing, so that pushing over it doesn't invalidate a passing run.
+// If prompts are enabled, the user may choose to push anyway.
+func (cmd *branchSubmitCmd) verifyCIGreen(
+	ctx context.Context,
+	log *log.Logger,
+	opts *globalOptions,
+	remoteRepo forge.Repository,
+	changeID forge.ChangeID,
+) error {
+	checks, err := remoteRepo.ChangeChecksState(ctx, changeID)
+	if err != nil {
+		return fmt.Errorf("get checks for CR %v: %w", changeID, err)
+	}
+	log.Debugf("%v: checks are %v", changeID, checks)
+	if checks != forge.ChecksPassing {
+		return nil
+	}
+
+	if opts.Prompt {
+		push := false
+		prompt := ui.NewConfirm().
+			WithValue(&push).
+			WithTitle(fmt.Sprintf("Push over passing checks on %v?", changeID)).
+			WithDescription("Pushing will invalidate the passing checks.")
+		if err := ui.Run(prompt); err != nil {
+			return fmt.Errorf("prompt: %w", err)
+		}
+		if push {
+			return nil
+		}
+	} else {
+		log.Errorf("%v: Checks on %v are passing. Use --force to push anyway.", cmd.Branch, changeID)
+	}
+
+	return fmt.Errorf("checks on CR %v are passing", changeID)
+}
+
+// lintCommitMessages verifies that all commit messages in the given range
+// match the pattern configured with 'git config spice.commitLint.pattern'.
+// The subjects of commits that don't match are logged.
+func (cmd *branchSubmitCmd) lintCommitMessages(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	commits git.CommitRange,
+) error {
+	pattern, err := repo.ConfigValue(ctx, "spice.commitLint.pattern")
+	if err != nil {
+		if errors.Is(err, git.ErrNotExist) {
+			return errors.New("--commit-message-lint requires a pattern: set it with 'git config spice.commitLint.pattern'")
+		}
+		return fmt.Errorf("read spice.commitLint.pattern: %w", err)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("bad spice.commitLint.pattern: %w", err)
+	}
+
+	msgs, err := repo.CommitMessageRange(ctx, commits)
+	if err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+
+	var bad []string
+	for _, msg := range msgs {
+		if !re.MatchString(msg.String()) {
+			bad = append(bad, msg.Subject)
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+
+	log.Errorf("%v: Commit messages don't match %v:", cmd.Branch, pattern)
+	for _, subject := range bad {
+		log.Errorf("  - %v", subject)
+	}
+	return errors.New("commit messages failed lint")
+}
+
+// changeReviewers returns the reviewers to request on a new CR
+// for the branch: those passed with --reviewers,
+// and with --reviewers-from-codeowners,
+// the owners of the files changed in the branch since base.
+//
+// "@me" refers to the current user.
+// The current user is never returned
+// as authors can't review their own changes.
+func (cmd *branchSubmitCmd) changeReviewers(
+	ctx context.Context,
+	log *log.Logger,
+	session *submitSession,
+	repo *git.Repository,
+	remoteRepo forge.Repository,
+	base string,
+) ([]string, error) {
+	var (
+		reviewers []string
+		seen      = make(map[string]struct{})
+	)
+	add := func(name string) {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name == "" {
+			return
+		}
+		if _, ok := seen[name]; !ok {
+			reviewers = append(reviewers, name)
+			seen[name] = struct{}{}
+		}
+	}
+
+	var self bool // whether "@me" was requested
+	for _, name := range cmd.Reviewers {
+		if strings.TrimSpace(name) == "@me" {
+			self = true
+			continue
+		}
+		add(name)
+	}
+
+	if cmd.ReviewersFromCodeowners {
+		owners, err := diffCodeowners(ctx, repo, base, cmd.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("find code owners: %w", err)
+		}
+		for _, owner := range owners {
+			add(owner)
+		}
+	}
+
+	if len(reviewers) == 0 && !self {
+		return nil, nil
+	}
+
+	user, err := session.currentUser(ctx, remoteRepo)
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+
+	// Logins are case-insensitive.
+	isSelf := func(name string) bool {
+		return strings.EqualFold(name, user.Login)
+	}
+	if slices.ContainsFunc(reviewers, isSelf) {
+		reviewers = slices.DeleteFunc(reviewers, isSelf)
+		self = true
+	}
+	if self {
+		log.Infof("%v: Not requesting a review from %v: authors can't review their own changes", cmd.Branch, user.Login)
+	}
+	return reviewers, nil
+}
+
+// remoteRepository returns the remote to push branches to
+// and the forge repository to submit CRs against.
+// These are resolved once per session.
+func (cmd *branchSubmitCmd) remoteRepository(
+	ctx context.Context,
+	session *submitSession,
+	repo *git.Repository,
+	store *state.Store,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+) (string, forge.Repository, error) {
+	remote, err := session.remote.Get(func() (string, error) {
+		return ensureRemote(ctx, repo, store, log, opts)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	remoteRepo, err := session.remoteRepo.Get(func() (forge.Repository, error) {
+		return openRemoteRepository(ctx, log, secretStash, repo, remote, cmd.Forge)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return remote, remoteRepo, nil
+}
+
+// changeAssignees returns the logins of the users to assign CRs to.
+// "@me" is replaced with the user authenticated against the forge.
+func (cmd *branchSubmitCmd) changeAssignees(
+	ctx context.Context,
+	session *submitSession,
+	remoteRepo forge.Repository,
+) ([]string, error) {
+	var assignees []string
+	for _, name := range cmd.Assignees {
+		name = strings.TrimSpace(name)
+		if name == "@me" {
+			user, err := session.currentUser(ctx, remoteRepo)
+			if err != nil {
+				return nil, fmt.Errorf("get current user: %w", err)
+			}
+			name = user.Login
+		}
+
+		name = strings.TrimPrefix(name, "@")
+		switch {
+		case name == "":
+			continue
+		case strings.Contains(name, "/"):
+			return nil, fmt.Errorf("cannot assign %q: only users can be assigned", name)
+		}
+		if !slices.Contains(assignees, name) {
+			assignees = append(assignees, name)
+		}
+	}
+	return assignees, nil
+}
+
+// updateBaseOnly retargets an existing CR for the branch
+// onto the closest base that has not been merged or deleted.
+// The base of the branch is updated in the store to match.
+func (cmd *branchSubmitCmd) updateBaseOnly(
+	ctx context.Context,
+	log *log.Logger,
+	session *submitSession,
+	store *state.Store,
+	svc *spice.Service,
+	remoteRepo forge.Repository,
+	branch *spice.LookupBranchResponse,
+	change *forge.FindChangeItem,
+) error {
+	if change == nil {
+		return fmt.Errorf("%v: no CR to update", cmd.Branch)
+	}
+
+	if change.HeadHash != branch.Head {
+		if !cmd.Force {
+			log.Errorf("%v: Branch has changes that haven't been pushed to %v.", cmd.Branch, change.ID)
+			log.Errorf("Try again without --update-base-only to push them,")
+			log.Errorf("or with --force to update only the base.")
+			return errors.New("refusing to update base only: branch needs to be pushed")
+		}
+		log.Warnf("%v: Not pushing changes to %v", cmd.Branch, change.ID)
+	}
+
+	base, err := svc.ResolveLiveBase(ctx, remoteRepo, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("find base: %w", err)
+	}
+
+	if change.BaseName == base && branch.Base == base {
+		log.Infof("CR %v is up-to-date: %s", change.ID, change.URL)
+		return nil
+	}
+
+	if cmd.DryRun {
+		log.Infof("WOULD update CR %v:", change.ID)
+		log.Infof("  - set base to %v", base)
+		session.addPending(cmd.Branch)
+		return nil
+	}
+
+	if branch.Base != base {
+		// The base hash is left as-is so that a future restack
+		// can tell which commits belong to this branch.
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name: cmd.Branch,
+				Base: base,
+			}},
+			Message: fmt.Sprintf("%v: set base to %v", cmd.Branch, base),
+		})
+		if err != nil {
+			return fmt.Errorf("update state: %w", err)
+		}
+	}
+
+	if change.BaseName != base {
+		if err := cmd.editChange(ctx, log, remoteRepo, change.ID, forge.EditChangeOptions{
+			Base: base,
+		}); err != nil {
+			return fmt.Errorf("edit CR %v: %w", change.ID, err)
+		}
+	}
+
+	log.Infof("Updated %v", changeLink(change.ID, change.URL))
+	return nil
+}
+
+// amendCommitRef rewrites the message of the branch's tip commit
+// to reference the given change, and pushes the amended commit.
+//
+// commitHash is the tip of the branch that was last pushed.
+// This is a no-op if the commit already references the change.
+func (cmd *branchSubmitCmd) amendCommitRef(
+	ctx context.Context,
+	log *log.Logger,
+	svc *spice.Service,
+	repo *git.Repository,
+	remote, upstreamBranch string,
+	commitHash git.Hash,
+	changeID forge.ChangeID,
+) (git.Hash, error) {
+	commit, err := repo.ReadCommitDetail(ctx, commitHash.String())
+	if err != nil {
+		return "", fmt.Errorf("read commit: %w", err)
+	}
+
+	ref := changeID.String()
+	msg := commit.Message().String()
+	if hasChangeRef(msg, ref) {
+		log.Debugf("%v: commit already references %v", cmd.Branch, ref)
+		return commitHash, nil
+	}
+
+	tree, err := repo.PeelToTree(ctx, commitHash.String())
+	if err != nil {
+		return "", fmt.Errorf("peel to tree: %w", err)
+	}
+
+	newHash, err := repo.CommitTree(ctx, git.CommitTreeRequest{
+		Tree:    tree,
+		Message: msg + "\n\n" + ref,
+		Parents: commit.Parents,
+		Author:  &commit.Author,
+		// Like 'git commit --amend',
+		// keep the committer but update the time.
+		Committer: &git.Signature{
+			Name:  commit.Committer.Name,
+			Email: commit.Committer.Email,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("amend commit: %w", err)
+	}
+
+	// The tree is unchanged so this is safe to do
+	// even if the branch is checked out.
+	if err := repo.SetRef(ctx, git.SetRefRequest{
+		Ref:     "refs/heads/" + cmd.Branch,
+		Hash:    newHash,
+		OldHash: commitHash,
+	}); err != nil {
+		return "", fmt.Errorf("update branch: %w", err)
+	}
+
+	_, err = repo.Push(ctx, git.PushOptions{
+		Remote:         remote,
+		Refspec:        git.Refspec(newHash.String() + ":refs/heads/" + upstreamBranch),
+		ForceWithLease: upstreamBranch + ":" + commitHash.String(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("push amended commit: %w", err)
+	}
+	log.Infof("%v: Referenced %v in commit %v", cmd.Branch, ref, newHash.Short())
+
+	// Branches above this one are now based on the old commit.
+	if aboves, err := svc.ListAbove(ctx, cmd.Branch); err == nil && len(aboves) > 0 {
+		log.Warnf("%v: Branches above it need to be restacked.", cmd.Branch)
+		log.Warnf("Run 'gs upstack restack' from %v to fix this.", cmd.Branch)
+	}
+
+	return newHash, nil
+}
+
+// autoRestack restacks the branch being submitted
+// and the branches below it that need to be restacked.
+//
+// If a restack is interrupted by a conflict,
+// the rebase is left in progress for the user to resolve.
+func (cmd *branchSubmitCmd) autoRestack(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	svc *spice.Service,
+) error {
+	downstacks, err := svc.ListDownstack(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("list downstack of %v: %w", cmd.Branch, err)
+	}
+	// ListDownstack reports the branch first, and trunk is never included.
+	slices.Reverse(downstacks)
+
+	// Restacking checks out the branches being rebased.
+	// Go back to where we were when we're done.
+	restore, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		head, err := repo.PeelToCommit(ctx, "HEAD")
+		if err != nil {
+			return fmt.Errorf("get HEAD: %w", err)
+		}
+		restore = head.String()
+	}
+
+	restacked := make(map[string]struct{}, len(downstacks))
+	inList := func(branch string) bool {
+		return slices.Contains(downstacks, branch)
+	}
+	for _, name := range downstacks {
+		if _, ok := restacked[name]; ok {
+			continue
+		}
+
+		res, err := svc.RestackChain(ctx, name, inList)
+		if err != nil {
+			var rebaseErr *git.RebaseInterruptError
+			switch {
+			case errors.As(err, &rebaseErr):
+				log.Errorf("%v: Could not restack automatically.", name)
+				log.Errorf("Submit %v again after resolving the conflict.", cmd.Branch)
+				return svc.RebaseRescue(ctx, spice.RebaseRescueRequest{
+					Err:     rebaseErr,
+					Command: []string{"branch", "restack", "--branch", name},
+					Branch:  name,
+					Message: fmt.Sprintf("interrupted: restack branch %s", name),
+				})
+			case errors.Is(err, spice.ErrAlreadyRestacked):
+				continue
+			default:
+				return fmt.Errorf("restack %v: %w", name, err)
+			}
+		}
+
+		base := res.Base
+		for _, name := range res.Branches {
+			restacked[name] = struct{}{}
+			log.Infof("%v: restacked on %v", name, base)
+			base = name
+		}
+	}
+
+	if err := repo.Checkout(ctx, restore); err != nil {
+		return fmt.Errorf("checkout %v: %w", restore, err)
+	}
+
+	return nil
+}
+
+// recordUpstreamHash records in the store
+// that hash was pushed to the upstream branch.
+// Failures are logged but not returned.
+func (cmd *branchSubmitCmd) recordUpstreamHash(
+	ctx context.Context,
+	log *log.Logger,
+	store *state.Store,
+	upstreamBranch string,
+	hash git.Hash,
+) {
+	err := store.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{
+				Name:           cmd.Branch,
+				UpstreamBranch: upstreamBranch,
+				UpstreamHash:   hash,
+			},
+		},
+		Message: fmt.Sprintf("branch submit %s", cmd.Branch),
+	})
+	if err != nil {
+		log.Warn("Could not update state", "error", err)
+	}
+}
+
+// submitDelta returns the commits in the branch
+// that were added since it was last submitted,
+// newest first.
+//
+// The last submitted commit is the one recorded in the store,
+// or the head of the CR if none was recorded.
+// Failures are logged and reported as nil.
+func (cmd *branchSubmitCmd) submitDelta(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	branch *spice.LookupBranchResponse,
+	changeHead, head git.Hash,
+) []git.CommitDetail {
+	last := cmp.Or(branch.UpstreamHash, changeHead)
+	commits, err := repo.ListCommitsDetails(ctx,
+		git.CommitRangeFrom(head).ExcludeFrom(last, branch.BaseHash))
+	if err != nil {
+		log.Warn("Could not list commits since last submit", "branch", cmd.Branch, "error", err)
+		return nil
+	}
+	if commits == nil {
+		commits = []git.CommitDetail{}
+	}
+	return commits
+}
+
+// logSubmitDelta logs the commits added to a branch since it was last submitted.
+func logSubmitDelta(log *log.Logger, branch string, delta []git.CommitDetail) {
+	if len(delta) == 0 {
+		log.Infof("%v: No new commits since last submit", branch)
+		return
+	}
+
+	log.Infof("%v: %d new commit(s) since last submit:", branch, len(delta))
+	for i := len(delta) - 1; i >= 0; i-- {
+		log.Infof("  - %v %v", delta[i].ShortHash, delta[i].Subject)
+	}
+}
+
+// submitDeltaComment returns the body of a comment
+// listing the commits added to a branch since it was last submitted.
+func submitDeltaComment(delta []git.CommitDetail) string {
+	var sb strings.Builder
+	sb.WriteString("New commits since last submit:\n\n")
+	for i := len(delta) - 1; i >= 0; i-- {
+		fmt.Fprintf(&sb, "- %v %v\n", delta[i].Hash, delta[i].Subject)
+	}
+	return sb.String()
+}
+
+// postCustomComment posts the comment specified with --comment
+// or --comment-file on a CR if it hasn't already been posted,
+// recording its ID in the change metadata.
+//
+// It reports whether the metadata was changed.
+// Failures to post the comment are logged but not returned.
+func (cmd *branchSubmitCmd) postCustomComment(
+	ctx context.Context,
+	log *log.Logger,
+	remoteRepo forge.Repository,
+	meta forge.ChangeMetadata,
+) (bool, error) {
+	body := cmd.Comment
+	if cmd.CommentFile != "" {
+		bs, err := os.ReadFile(cmd.CommentFile)
+		if err != nil {
+			return false, fmt.Errorf("read comment: %w", err)
+		}
+		body = string(bs)
+	}
+	if strings.TrimSpace(body) == "" || meta.CustomCommentID() != nil {
+		return false, nil
+	}
+
+	commentID, err := remoteRepo.PostChangeComment(ctx, meta.ChangeID(), body)
+	if err != nil {
+		log.Warn("Could not post comment", "change", meta.ChangeID(), "error", err)
+		return false, nil
+	}
 
-			if err := remoteRepo.EditChange(ctx, pull.ID, opts); err != nil {
-				return fmt.Errorf("edit CR %v: %w", pull.ID, err)
-			}
+	meta.SetCustomCommentID(commentID)
+	return true, nil
+}
+
+// bodyAugments returns the text to add to the start and end
+// of the body of new CRs with --body-prepend and --body-append,
+// or their -file variants.
+func (cmd *submitOptions) bodyAugments() (prepend, appendix string, err error) {
+	prepend, appendix = cmd.BodyPrepend, cmd.BodyAppend
+	if cmd.BodyPrependFile != "" {
+		bs, err := os.ReadFile(cmd.BodyPrependFile)
+		if err != nil {
+			return "", "", fmt.Errorf("read body prefix: %w", err)
+		}
+		prepend = string(bs)
+	}
+	if cmd.BodyAppendFile != "" {
+		bs, err := os.ReadFile(cmd.BodyAppendFile)
+		if err != nil {
+			return "", "", fmt.Errorf("read body suffix: %w", err)
 		}
+		appendix = string(bs)
+	}
+	return strings.TrimSpace(prepend), strings.TrimSpace(appendix), nil
+}
 
-		log.Infof("Updated %v: %s", pull.ID, pull.URL)
+// augmentBody adds prepend and appendix to the start and end of body,
+// separated from it by blank lines.
+// Text that the body already starts or ends with is not added again,
+// so a body recovered from a prior submission attempt is left as is.
+func augmentBody(body, prepend, appendix string) string {
+	if prepend == "" && appendix == "" {
+		return body
 	}
 
-	return nil
+	body = strings.TrimSpace(body)
+	if prepend != "" && !strings.HasPrefix(body, prepend) {
+		if body != "" {
+			body = prepend + "\n\n" + body
+		} else {
+			body = prepend
+		}
+	}
+	if appendix != "" && !strings.HasSuffix(body, appendix) {
+		if body != "" {
+			body += "\n\n" + appendix
+		} else {
+			body = appendix
+		}
+	}
+	return body
+}
+
+// draftLabel reports the label to apply to draft CRs with --label-draft.
+func draftLabel(ctx context.Context, repo *git.Repository) (string, error) {
+	label, err := repo.ConfigValue(ctx, "spice.draftLabel")
+	switch {
+	case errors.Is(err, git.ErrNotExist):
+		return "draft", nil
+	case err != nil:
+		return "", fmt.Errorf("read draft label: %w", err)
+	default:
+		return label, nil
+	}
+}
+
+// hasLabel reports whether labels includes the given label.
+// Forges match label names case-insensitively.
+func hasLabel(labels []string, label string) bool {
+	return slices.ContainsFunc(labels, func(l string) bool {
+		return strings.EqualFold(l, label)
+	})
+}
+
+// readyLabels reports the labels to add and remove
+// when CRs are marked ready for review with --label-ready-on-undraft.
+func readyLabels(ctx context.Context, repo *git.Repository) (ready, wip string, err error) {
+	ready, err = repo.ConfigValue(ctx, "spice.submit.readyLabel")
+	switch {
+	case errors.Is(err, git.ErrNotExist):
+		ready = "ready-for-review"
+	case err != nil:
+		return "", "", fmt.Errorf("read ready label: %w", err)
+	}
+
+	wip, err = repo.ConfigValue(ctx, "spice.submit.wipLabel")
+	switch {
+	case errors.Is(err, git.ErrNotExist):
+		wip = "wip"
+	case err != nil:
+		return "", "", fmt.Errorf("read wip label: %w", err)
+	}
+
+	return ready, wip, nil
+}
+
+// fillCommitRange selects the commits between head and base
+// that fill in the title and body of a CR.
+// Only the first-parent history of the branch is used
+// so that commits merged into the branch from elsewhere
+// don't end up in its CR.
+func fillCommitRange(head, base git.Hash) git.CommitRange {
+	return git.CommitRangeFrom(head).ExcludeFrom(base).FirstParent()
+}
+
+// fillTitle returns the title to use for a change
+// made up of the given commits, which are in reverse order.
+// This is the subject of the oldest commit.
+func fillTitle(commits []git.CommitDetail) string {
+	if len(commits) == 0 {
+		return ""
+	}
+	return commits[len(commits)-1].Subject
+}
+
+// fillBody returns the body to use for a change
+// made up of the given commits, which are in reverse order.
+//
+// For a single commit, this is the body of that commit.
+// Otherwise, it's the subjects and bodies of all commits
+// concatenated from oldest to newest.
+func fillBody(commits []git.CommitDetail) string {
+	if len(commits) == 1 {
+		return commits[0].Body
+	}
+
+	var body strings.Builder
+	for i := len(commits) - 1; i >= 0; i-- {
+		msg := commits[i]
+		if body.Len() > 0 {
+			body.WriteString("\n\n")
+		}
+		body.WriteString(msg.Subject)
+		if msg.Body != "" {
+			body.WriteString("\n\n")
+			body.WriteString(msg.Body)
+		}
+	}
+	return body.String()
+}
+
+// verifyClean returns an error if --require-clean is in effect
+// and there are uncommitted changes in the repository.
+func (cmd *submitOptions) verifyClean(ctx context.Context, repo *git.Repository, log *log.Logger) error {
+	requireClean := cmd.RequireClean
+	if requireClean == nil {
+		v, err := repo.ConfigBool(ctx, "spice.submit.requireClean")
+		switch {
+		case errors.Is(err, git.ErrNotExist):
+			v = false
+		case err != nil:
+			return fmt.Errorf("read spice.submit.requireClean: %w", err)
+		}
+		requireClean = &v
+	}
+	if !*requireClean {
+		return nil
+	}
+
+	dirty, err := repo.DirtyFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("check for uncommitted changes: %w", err)
+	}
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	log.Errorf("There are uncommitted changes in:")
+	for _, path := range dirty {
+		log.Errorf("  - %s", path)
+	}
+	log.Errorf("Commit or stash them, or try again with --no-require-clean.")
+	return errors.New("refusing to submit with uncommitted changes")
+}
+
+// verifyWorktree refuses to submit if --require-clean
+// or --abort-on-dirty-index find uncommitted changes
+// that affect any of the given branches.
+//
+// Commands that submit multiple branches run this
+// before pushing any of them.
+func (cmd *submitOptions) verifyWorktree(
+	ctx context.Context,
+	repo *git.Repository,
+	log *log.Logger,
+	branches []string,
+) error {
+	if err := cmd.verifyClean(ctx, repo, log); err != nil {
+		return err
+	}
+	if !cmd.AbortOnDirtyIndex {
+		return nil // branch submit warns about staged changes
+	}
+
+	current, err := repo.CurrentBranch(ctx)
+	if err != nil || !slices.Contains(branches, current) {
+		return nil
+	}
+	return (&branchSubmitCmd{
+		submitOptions: *cmd,
+		Branch:        current,
+	}).checkStagedChanges(ctx, repo, log)
+}
+
+// checkStagedChanges warns about changes staged in the index
+// that won't be submitted because they haven't been committed.
+// With --abort-on-dirty-index, it returns an error instead.
+//
+// Only the current branch is checked
+// because staged changes are relative to it.
+func (cmd *branchSubmitCmd) checkStagedChanges(ctx context.Context, repo *git.Repository, log *log.Logger) error {
+	if current, err := repo.CurrentBranch(ctx); err != nil || current != cmd.Branch {
+		return nil
+	}
+
+	staged, err := repo.DiffIndex(ctx, "HEAD")
+	if err != nil {
+		return fmt.Errorf("diff index: %w", err)
+	}
+	if len(staged) == 0 {
+		return nil
+	}
+
+	if !cmd.AbortOnDirtyIndex {
+		log.Warnf("%v: Staged changes will not be submitted until they're committed:", cmd.Branch)
+		for _, f := range staged {
+			log.Warnf("  - %s", f.Path)
+		}
+		return nil
+	}
+
+	log.Errorf("%v: There are staged changes that would not be submitted:", cmd.Branch)
+	for _, f := range staged {
+		log.Errorf("  - %s", f.Path)
+	}
+	log.Errorf("Commit them first, or try again without --abort-on-dirty-index.")
+	return errors.New("refusing to submit with staged changes")
+}
+
+// bodyEditorExt returns the file extension to use
+// when opening the change body in an editor.
+func bodyEditorExt(ctx context.Context, repo *git.Repository, log *log.Logger) string {
+	ext, err := repo.ConfigValue(ctx, "spice.submit.bodyExt")
+	if err != nil {
+		if !errors.Is(err, git.ErrNotExist) {
+			log.Warn("Could not read body extension", "error", err)
+		}
+		return "md"
+	}
+	return cmp.Or(strings.TrimPrefix(ext, "."), "md")
+}
+
+// hasChangeRef reports whether msg references a change
+// with the given reference, e.g. "#123".
+func hasChangeRef(msg, ref string) bool {
+	pattern := `(^|\W)` + regexp.QuoteMeta(ref) + `($|\W)`
+	return regexp.MustCompile(pattern).MatchString(msg)
 }
 
 type branchSubmitForm struct {
@@ -475,10 +2348,28 @@ func (f *branchSubmitForm) templateField(changeTemplatesCh <-chan []*forge.Chang
 	})
 }
 
+// findChangeTemplate returns the template with the given file name.
+// The error lists the available templates if there isn't one.
+func findChangeTemplate(templates []*forge.ChangeTemplate, filename string) (*forge.ChangeTemplate, error) {
+	names := make([]string, len(templates))
+	for i, tmpl := range templates {
+		if tmpl.Filename == filename {
+			return tmpl, nil
+		}
+		names[i] = tmpl.Filename
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("change template %q not found: no templates available", filename)
+	}
+	return nil, fmt.Errorf("change template %q not found: available templates: %v",
+		filename, strings.Join(names, ", "))
+}
+
 func (f *branchSubmitForm) bodyField(body *string) ui.Field {
 	editor := ui.Editor{
-		Command: gitEditor(f.ctx, f.repo),
-		Ext:     "md",
+		Command: bodyEditor(f.ctx, f.repo, f.log),
+		Ext:     bodyEditorExt(f.ctx, f.repo, f.log),
 	}
 
 	return ui.Defer(func() ui.Field {
@@ -505,11 +2396,57 @@ func (f *branchSubmitForm) draftField(draft *bool) ui.Field {
 		WithDescription("Mark the change as a draft?")
 }
 
+// loadPrepared loads information filled in a prior attempt
+// to submit the branch, if any.
+// It warns if the information was filled in
+// for a different version of the branch.
+func (cmd *branchSubmitCmd) loadPrepared(
+	ctx context.Context,
+	log *log.Logger,
+	store *state.Store,
+	key string,
+) *state.PreparedBranch {
+	prepared, err := store.LoadPreparedBranch(ctx, cmd.Branch, key)
+	if err != nil {
+		log.Warn("Could not load previously filled information", "error", err)
+		return nil
+	}
+	if prepared != nil && prepared.Key != "" && prepared.Key != key {
+		log.Warnf("%v: Branch has changed since the information was filled", cmd.Branch)
+	}
+	return prepared
+}
+
+// templatesWithTimeout returns a channel that reports
+// the change templates sent on ch
+// if they're sent within the given timeout.
+//
+// The listing continues in the background regardless
+// so that it's remembered for other branches in the session.
+func templatesWithTimeout(
+	log *log.Logger,
+	ch <-chan []*forge.ChangeTemplate,
+	timeout time.Duration,
+) <-chan []*forge.ChangeTemplate {
+	out := make(chan []*forge.ChangeTemplate, 1)
+	go func() {
+		defer close(out)
+		select {
+		case templates := <-ch:
+			out <- templates
+		case <-time.After(timeout):
+			log.Warn("Timed out listing change templates")
+		}
+	}()
+	return out
+}
+
 // Fills change information in the branch submit command.
 func (cmd *branchSubmitCmd) preparePublish(
 	ctx context.Context,
 	log *log.Logger,
 	opts *globalOptions,
+	session *submitSession,
 	svc *spice.Service,
 	store *state.Store,
 	repo *git.Repository,
@@ -520,55 +2457,95 @@ func (cmd *branchSubmitCmd) preparePublish(
 	changeTemplatesCh := make(chan []*forge.ChangeTemplate, 1)
 	go func() {
 		defer close(changeTemplatesCh)
+		if cmd.NoTemplate || cmd.Template != "" {
+			// --template waits for the full list below.
+			return
+		}
 
-		ctx, cancel := context.WithTimeout(ctx, time.Second)
-		defer cancel()
-
-		templates, err := svc.ListChangeTemplates(ctx, remoteRepo)
+		templates, err := session.changeTemplates(ctx, svc, remoteRepo)
 		if err != nil {
 			log.Warn("Could not list change templates", "error", err)
-			templates = nil
 		}
-
 		changeTemplatesCh <- templates
 	}()
 
-	msgs, err := repo.CommitMessageRange(ctx, cmd.Branch, baseBranch)
+	// Defaults saved with 'repo submit-defaults'
+	// apply only if the flags weren't used.
+	defaults, err := store.LoadSubmitDefaults(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("list commits: %w", err)
+		log.Warn("Could not load submit defaults", "error", err)
+	} else {
+		if len(cmd.Reviewers) == 0 {
+			cmd.Reviewers = defaults.Reviewers
+		}
+		if len(cmd.Labels) == 0 {
+			cmd.Labels = defaults.Labels
+		}
+	}
+
+	head, err := repo.PeelToCommit(ctx, cmd.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %v: %w", cmd.Branch, err)
 	}
-	if len(msgs) == 0 {
-		return nil, errors.New("no commits to submit")
+	baseHash, err := repo.PeelToCommit(ctx, baseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %v: %w", baseBranch, err)
+	}
+
+	commits, err := repo.ListCommitsDetails(ctx, fillCommitRange(head, baseHash))
+	if err != nil {
+		return nil, fmt.Errorf("list commits: %w", err)
 	}
 
+	// Information about prior submission attempts is keyed
+	// by the commit being submitted,
+	// so that we can tell if it was filled in
+	// for a different version of the branch.
+	preparedKey := head.String()
+
 	var (
 		defaultTitle string
 		defaultBody  strings.Builder
 	)
-	if len(msgs) == 1 {
-		// If there's only one commit,
-		// just the body will be the default body.
-		defaultTitle = msgs[0].Subject
-		defaultBody.WriteString(msgs[0].Body)
-	} else {
-		// Otherwise, we'll concatenate all the messages.
-		// The revisions are in reverse order,
-		// so we'll want to iterate in reverse.
-		defaultTitle = msgs[len(msgs)-1].Subject
-		for i := len(msgs) - 1; i >= 0; i-- {
-			msg := msgs[i]
-			if defaultBody.Len() > 0 {
-				defaultBody.WriteString("\n\n")
+	switch {
+	case len(commits) == 0:
+		// This is possible only with --allow-empty or --force.
+		// There's no commit message to use, so use the branch name.
+		defaultTitle = cmd.Branch
+	default:
+		defaultTitle = fillTitle(commits)
+		defaultBody.WriteString(fillBody(commits))
+	}
+
+	if cmd.PrefillChecklist {
+		items, err := diffChecklist(ctx, repo, baseBranch, cmd.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("build checklist: %w", err)
+		}
+
+		if len(items) > 0 {
+			body := defaultBody.String()
+			defaultBody.Reset()
+			for _, item := range items {
+				fmt.Fprintf(&defaultBody, "- [ ] %s\n", item)
 			}
-			defaultBody.WriteString(msg.Subject)
-			if msg.Body != "" {
-				defaultBody.WriteString("\n\n")
-				defaultBody.WriteString(msg.Body)
+			if body != "" {
+				defaultBody.WriteString("\n")
+				defaultBody.WriteString(body)
 			}
 		}
 	}
 
-	var fields []ui.Field
+	// Only values that the user didn't provide may be recovered
+	// from a prior submission attempt.
+	recoverTitle, recoverBody := cmd.Title == "", cmd.Body == ""
+
+	var (
+		fields []ui.Field
+		// labels are additional labels for the CR
+		// declared by the template.
+		labels []string
+	)
 	form := newBranchSubmitForm(ctx, svc, repo, remoteRepo, log)
 	if cmd.Title == "" {
 		cmd.Title = defaultTitle
@@ -577,27 +2554,100 @@ func (cmd *branchSubmitCmd) preparePublish(
 
 	if cmd.Body == "" {
 		cmd.Body = defaultBody.String()
-		if cmd.Fill {
+
+		// With --template, the rest of this behaves
+		// as if that was the only template.
+		if cmd.Template != "" {
+			templates, err := session.changeTemplates(ctx, svc, remoteRepo)
+			if err != nil {
+				return nil, fmt.Errorf("list change templates: %w", err)
+			}
+
+			tmpl, err := findChangeTemplate(templates, cmd.Template)
+			if err != nil {
+				return nil, err
+			}
+
+			selected := make(chan []*forge.ChangeTemplate, 1)
+			selected <- []*forge.ChangeTemplate{tmpl}
+			close(selected)
+			changeTemplatesCh = selected
+		}
+
+		switch {
+		case cmd.NoTemplate:
+			// With --no-template, only prompt for the body.
+			if !cmd.Fill {
+				fields = append(fields, form.bodyField(&cmd.Body))
+			}
+
+		case cmd.Fill:
 			// If the user selected --fill,
 			// and there are templates to choose from,
 			// just pick the first template in the body.
 			tmpls := <-changeTemplatesCh
 			if len(tmpls) > 0 {
-				cmd.Body += "\n\n" + tmpls[0].Body
+				tmplBody := tmpls[0].Body
+				if cmd.FillTemplateFrontmatter {
+					fm, body, err := splitTemplateFrontmatter(tmplBody)
+					if err != nil {
+						return nil, fmt.Errorf("template %v: %w", tmpls[0].Filename, err)
+					}
+					tmplBody = body
+					if recoverTitle {
+						cmd.Title = fm.ApplyTitle(cmd.Title)
+					}
+					labels = fm.Labels
+				}
+				cmd.Body += "\n\n" + tmplBody
 			}
-		} else {
+
+		default:
 			// Otherwise, we'll prompt for the template (if needed)
 			// and the body.
-			fields = append(fields, form.templateField(changeTemplatesCh))
+			// Don't hold up the prompt for long waiting for templates.
+			templatesCh := templatesWithTimeout(log, changeTemplatesCh, time.Second)
+			fields = append(fields, form.templateField(templatesCh))
 			fields = append(fields, form.bodyField(&cmd.Body))
 		}
 	}
 
-	// Don't mess with draft setting if we're not prompting
-	// and the user didn't explicitly set it.
-	if opts.Prompt && cmd.Draft == nil {
-		cmd.Draft = new(bool)
-		fields = append(fields, form.draftField(cmd.Draft))
+	// Without --draft or --no-draft,
+	// patterns matching the branch name pick the default draft status.
+	// Don't mess with draft setting if we're not prompting,
+	// and the user didn't explicitly set it or configure a pattern.
+	if cmd.Draft == nil {
+		draft, ok, err := draftFromPatterns(ctx, repo, cmd.Branch)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			log.Debugf("%v: Using draft status from spice.submit.draftPatterns: %v", cmd.Branch, draft)
+			cmd.Draft = &draft
+		}
+		if opts.Prompt {
+			if cmd.Draft == nil {
+				cmd.Draft = new(bool)
+			}
+			fields = append(fields, form.draftField(cmd.Draft))
+		}
+	}
+
+	// With --no-edit, submit without prompting,
+	// using information from a prior submission attempt if any.
+	if cmd.NoEdit && len(fields) > 0 {
+		fields = nil
+
+		prePrepared := cmd.loadPrepared(ctx, log, store, preparedKey)
+		if prePrepared != nil {
+			log.Infof("%v: Using previously filled information", cmd.Branch)
+			if recoverTitle {
+				cmd.Title = prePrepared.Subject
+			}
+			if recoverBody {
+				cmd.Body = prePrepared.Body
+			}
+		}
 	}
 
 	// TODO: should we assume --fill if --no-prompt?
@@ -608,8 +2658,8 @@ func (cmd *branchSubmitCmd) preparePublish(
 
 		// If we're prompting and there's a prior submission attempt,
 		// change the title and body to the saved values.
-		prePrepared, err := store.LoadPreparedBranch(ctx, cmd.Branch)
-		if err == nil && prePrepared != nil {
+		prePrepared := cmd.loadPrepared(ctx, log, store, preparedKey)
+		if prePrepared != nil {
 			usePrepared := true
 			f := ui.NewConfirm().
 				WithValue(&usePrepared).
@@ -641,8 +2691,27 @@ func (cmd *branchSubmitCmd) preparePublish(
 	}
 	must.NotBeBlankf(cmd.Title, "CR title must have been set")
 
+	prepend, appendix, err := cmd.bodyAugments()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Body = augmentBody(cmd.Body, prepend, appendix)
+
+	if cmd.PrefillIssueLink {
+		issue, err := branchIssue(ctx, repo, cmd.Branch)
+		if err != nil {
+			return nil, err
+		}
+		if issue != "" {
+			cmd.Body = appendIssueLink(cmd.Body, issue)
+		} else {
+			log.Debugf("%v: branch name does not reference an issue", cmd.Branch)
+		}
+	}
+
 	storePrepared := state.PreparedBranch{
 		Name:    cmd.Branch,
+		Key:     preparedKey,
 		Subject: cmd.Title,
 		Body:    cmd.Body,
 	}
@@ -656,10 +2725,16 @@ func (cmd *branchSubmitCmd) preparePublish(
 		log.Warn("Could not save prepared branch. Will be unable to recover CR metadata if the push fails.", "error", err)
 	}
 
+	headRef := cmd.Branch
+	if cmd.PushHeadRef != "" {
+		headRef = cmd.PushHeadRef
+	}
+
 	return &preparedBranch{
 		PreparedBranch: storePrepared,
 		draft:          draft,
-		head:           cmd.Branch,
+		labels:         labels,
+		head:           headRef,
 		base:           baseBranch,
 		remoteRepo:     remoteRepo,
 		store:          store,
@@ -676,18 +2751,33 @@ type preparedBranch struct {
 	base  string
 	draft bool
 
+	// labels are labels to add to the CR
+	// in addition to those requested with --label.
+	labels []string
+
+	// milestone is the title or number of the milestone
+	// to attach the CR to.
+	milestone string
+
+	// assignees are the logins of users to assign the CR to.
+	assignees []string
+
 	remoteRepo forge.Repository
 	store      *state.Store
 	log        *log.Logger
 }
 
-func (b *preparedBranch) Publish(ctx context.Context) (forge.ChangeID, error) {
+func (b *preparedBranch) Publish(ctx context.Context) (*forge.SubmitChangeResult, error) {
 	result, err := b.remoteRepo.SubmitChange(ctx, forge.SubmitChangeRequest{
 		Subject: b.Subject,
 		Body:    b.Body,
 		Head:    b.head,
 		Base:    b.base,
 		Draft:   b.draft,
+		Labels:  b.labels,
+
+		Milestone: b.milestone,
+		Assignees: b.assignees,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create change: %w", err)
@@ -697,6 +2787,222 @@ func (b *preparedBranch) Publish(ctx context.Context) (forge.ChangeID, error) {
 		b.log.Warn("Could not clear prepared branch", "error", err)
 	}
 
-	b.log.Infof("Created %v: %s", result.ID, result.URL)
-	return result.ID, nil
+	b.log.Infof("Created %v", changeLink(result.ID, result.URL))
+	return &result, nil
+}
+
+// stackCommentOptions reports how stack comments should be posted.
+// Flags take precedence over the spice.stackComment.* configuration.
+func (cmd *submitOptions) stackCommentOptions(ctx context.Context, repo *git.Repository) (stackCommentOptions, error) {
+	collapse, err := cmd.stackCommentCollapse(ctx, repo)
+	if err != nil {
+		return stackCommentOptions{}, err
+	}
+
+	target, err := cmd.stackCommentTarget(ctx, repo)
+	if err != nil {
+		return stackCommentOptions{}, err
+	}
+
+	return stackCommentOptions{
+		Collapse: collapse,
+		Target:   target,
+		DryRun:   cmd.DryRun,
+	}, nil
+}
+
+// stackCommentCollapse reports whether stack comments should be collapsed.
+// The --[no-]stack-comment-collapse flag takes precedence
+// over the spice.stackComment.collapse configuration.
+func (cmd *submitOptions) stackCommentCollapse(ctx context.Context, repo *git.Repository) (bool, error) {
+	if cmd.StackCommentCollapse != nil {
+		return *cmd.StackCommentCollapse, nil
+	}
+
+	collapse, err := repo.ConfigBool(ctx, "spice.stackComment.collapse")
+	switch {
+	case errors.Is(err, git.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("read spice.stackComment.collapse: %w", err)
+	}
+	return collapse, nil
+}
+
+// stackCommentTarget reports which CRs should get a stack comment.
+// The --stack-comment-target flag takes precedence
+// over the spice.stackComment.target configuration.
+func (cmd *submitOptions) stackCommentTarget(ctx context.Context, repo *git.Repository) (stackCommentTarget, error) {
+	if cmd.StackCommentTarget != "" {
+		return parseStackCommentTarget(cmd.StackCommentTarget)
+	}
+
+	value, err := repo.ConfigValue(ctx, "spice.stackComment.target")
+	switch {
+	case errors.Is(err, git.ErrNotExist):
+		return stackCommentAll, nil
+	case err != nil:
+		return "", fmt.Errorf("read spice.stackComment.target: %w", err)
+	}
+
+	target, err := parseStackCommentTarget(value)
+	if err != nil {
+		return "", fmt.Errorf("bad spice.stackComment.target: %w", err)
+	}
+	return target, nil
+}
+
+// setUpstream reports whether the upstream of a branch
+// should be set after it's pushed for the first time.
+func (cmd *submitOptions) setUpstream(ctx context.Context, repo *git.Repository) (bool, error) {
+	if cmd.SetUpstream != nil {
+		return *cmd.SetUpstream, nil
+	}
+
+	setUpstream, err := repo.ConfigBool(ctx, "spice.submit.setUpstream")
+	switch {
+	case errors.Is(err, git.ErrNotExist):
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("read spice.submit.setUpstream: %w", err)
+	}
+	return setUpstream, nil
+}
+
+// editChange edits a CR with the given options.
+//
+// If the forge reports that the CR was modified concurrently,
+// the edit is retried up to --retries-on-conflict times.
+// Before each retry, the CR is fetched again
+// and parts of the edit that are already in effect are dropped.
+func (cmd *submitOptions) editChange(
+	ctx context.Context,
+	log *log.Logger,
+	remoteRepo forge.Repository,
+	id forge.ChangeID,
+	opts forge.EditChangeOptions,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := remoteRepo.EditChange(ctx, id, opts)
+		if err == nil || !errors.Is(err, forge.ErrChangeConflict) || attempt > cmd.RetriesOnConflict {
+			return err
+		}
+
+		log.Warnf("CR %v was changed concurrently. Retrying (%d/%d)", id, attempt, cmd.RetriesOnConflict)
+		change, err := remoteRepo.FindChangeByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("find CR %v: %w", id, err)
+		}
+
+		if opts.Base == change.BaseName {
+			opts.Base = ""
+		}
+		if opts.Draft != nil && *opts.Draft == change.Draft {
+			opts.Draft = nil
+		}
+		if opts.Title != nil && *opts.Title == change.Subject {
+			opts.Title = nil
+		}
+		if opts.Base == "" && opts.Draft == nil && opts.Title == nil && opts.Body == nil &&
+			len(opts.AddLabels) == 0 && len(opts.RemoveLabels) == 0 && len(opts.AddReviewers) == 0 &&
+			opts.Milestone == "" && len(opts.AddAssignees) == 0 {
+			log.Debugf("CR %v: No changes left to make", id)
+			return nil
+		}
+	}
+}
+
+// AfterApply is called by Kong after parsing flags.
+func (cmd *submitOptions) AfterApply() error {
+	// --check is a --dry-run that reports the outcome.
+	if cmd.Check {
+		cmd.DryRun = true
+	}
+
+	if cmd.DraftNewOnly && cmd.Draft == nil {
+		return errors.New("--draft-new-only requires --draft or --no-draft")
+	}
+
+	if cmd.MergeMethod != forge.MergeMethodDefault && !cmd.MergeWhenReady {
+		return errors.New("--merge-method requires --merge-when-ready")
+	}
+
+	if cmd.RetriesOnConflict < 0 {
+		return errors.New("--retries-on-conflict must not be negative")
+	}
+
+	for _, label := range cmd.Labels {
+		if slices.Contains(cmd.LabelRemove, label) {
+			return fmt.Errorf("label %q cannot be both added and removed", label)
+		}
+	}
+	return nil
+}
+
+// _exitCodeNeedsSubmit is the exit code for --check
+// if any branches need to be submitted.
+const _exitCodeNeedsSubmit = 2
+
+// checkPending reports an error if --check was used
+// and branches in the session need to be submitted.
+func (cmd *submitOptions) checkPending(session *submitSession) error {
+	if !cmd.Check || len(session.pending) == 0 {
+		return nil
+	}
+
+	return &exitError{
+		Code: _exitCodeNeedsSubmit,
+		Err: fmt.Errorf("branches need to be submitted: %v",
+			strings.Join(session.pending, ", ")),
+	}
+}
+
+// draftFromPatterns reports the draft status for new CRs for a branch
+// from the branch name patterns in 'spice.submit.draftPatterns'.
+//
+// Each value of the configuration is a glob pattern
+// matched against the branch name with path.Match.
+// Branches matching the pattern are submitted as drafts.
+// Patterns starting with '!' instead submit matching branches
+// as ready for review.
+// The first matching pattern wins.
+//
+// ok is false if no pattern matched the branch.
+func draftFromPatterns(ctx context.Context, repo *git.Repository, branch string) (draft, ok bool, err error) {
+	patterns, err := repo.ConfigValues(ctx, "spice.submit.draftPatterns")
+	if err != nil {
+		if errors.Is(err, git.ErrNotExist) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("read spice.submit.draftPatterns: %w", err)
+	}
+
+	for _, pattern := range patterns {
+		pattern, ready := strings.CutPrefix(pattern, "!")
+		matched, err := path.Match(pattern, branch)
+		if err != nil {
+			return false, false, fmt.Errorf("bad pattern %q in spice.submit.draftPatterns: %w", pattern, err)
+		}
+		if matched {
+			return !ready, true, nil
+		}
+	}
+
+	return false, false, nil
+}
+
+// logPushResult reports whether pushing a branch
+// changed the upstream branch.
+func logPushResult(log *log.Logger, branch, upstreamBranch string, result *git.PushResult) {
+	ref := result.Ref(upstreamBranch)
+	switch {
+	case ref == nil:
+		// Git didn't report the ref. Nothing to say.
+	case !ref.Updated():
+		log.Debugf("%v: Pushed (no change)", branch)
+	case ref.OldHash != "":
+		log.Debugf("%v: Pushed %v→%v", branch, ref.OldHash.Short(), ref.NewHash.Short())
+	default:
+		log.Debugf("%v: Pushed %v", branch, ref.Summary)
+	}
 }
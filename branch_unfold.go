@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type branchUnfoldCmd struct {
+	Branch string `arg:"" placeholder:"NAME" help:"Name of the folded branch"`
+}
+
+func (*branchUnfoldCmd) Help() string {
+	return text.Dedent(`
+		Restores a branch that was deleted by 'branch fold'.
+		The branch is recreated at the commit it pointed to
+		before it was folded, and tracked again
+		with the base and Change Request it had.
+		Branches that were moved onto the base of the folded branch
+		are moved back onto it.
+		If the branch was folded together with other branches
+		with 'branch fold --into', all of them are restored.
+
+		The branch it was folded into is moved back
+		to where it was before the fold
+		unless it has changed since then.
+
+		The commit to restore is found in the reflog
+		of the branch it was folded into.
+		This fails if that reflog entry has expired
+		or the commits have been garbage collected.
+	`)
+}
+
+func (cmd *branchUnfoldCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
+	repo, store, _, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	if repo.BranchExists(ctx, cmd.Branch) {
+		return fmt.Errorf("branch %v already exists", cmd.Branch)
+	}
+
+	// Branches that were folded have their state archived.
+	archived := make(map[string]*state.LookupResponse)
+	lookupArchived := func(name string) (*state.LookupResponse, error) {
+		if b, ok := archived[name]; ok {
+			return b, nil
+		}
+		b, err := store.LookupArchived(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		archived[name] = b
+		return b, nil
+	}
+
+	folded, err := lookupArchived(cmd.Branch)
+	if err != nil {
+		if errors.Is(err, state.ErrNotExist) {
+			return fmt.Errorf("%v: no record of a folded branch with this name", cmd.Branch)
+		}
+		return fmt.Errorf("lookup archived branch: %w", err)
+	}
+
+	// Follow the recorded bases down to the branch
+	// that the folded branches were merged into.
+	// With --into, this skips past the other folded branches.
+	into := folded.Base
+	seen := map[string]struct{}{cmd.Branch: {}}
+	for !repo.BranchExists(ctx, into) {
+		if _, ok := seen[into]; ok {
+			return fmt.Errorf("%v: cycle in recorded bases at %v", cmd.Branch, into)
+		}
+		seen[into] = struct{}{}
+
+		b, err := lookupArchived(into)
+		if err != nil {
+			if errors.Is(err, state.ErrNotExist) {
+				return fmt.Errorf("%v: cannot find the branch it was folded into: %v does not exist", cmd.Branch, into)
+			}
+			return fmt.Errorf("lookup archived branch: %w", err)
+		}
+		into = b.Base
+	}
+
+	fold, err := cmd.findFold(ctx, repo, into, lookupArchived)
+	if err != nil {
+		return err
+	}
+
+	// The head of the top-most folded branch is recorded in the reflog.
+	// The branches below it were restacked when they were folded,
+	// so their heads are the base hashes of the branches above them.
+	heads := make([]git.Hash, len(fold.Branches))
+	heads[0] = fold.Hash
+	for i := 1; i < len(fold.Branches); i++ {
+		heads[i] = archived[fold.Branches[i-1]].BaseHash
+	}
+	for i, name := range fold.Branches {
+		if _, err := repo.PeelToCommit(ctx, heads[i].String()); err != nil {
+			return fmt.Errorf("%v: commit %v is no longer available: it may have been garbage collected", name, heads[i].Short())
+		}
+		if repo.BranchExists(ctx, name) {
+			return fmt.Errorf("cannot restore %v: branch %v already exists", cmd.Branch, name)
+		}
+	}
+
+	// Branches above the top-most folded branch were moved onto 'into'
+	// with the folded commit as their base hash.
+	// Move those that haven't been changed since back.
+	top := fold.Branches[0]
+	var upserts []state.UpsertRequest
+	tracked, err := store.ListBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	for _, name := range tracked {
+		b, err := store.LookupBranch(ctx, name)
+		if err != nil {
+			return fmt.Errorf("lookup %v: %w", name, err)
+		}
+		if b.Base == into && b.BaseHash == fold.Hash {
+			upserts = append(upserts, state.UpsertRequest{
+				Name: name,
+				Base: top,
+			})
+		}
+	}
+
+	for i, name := range fold.Branches {
+		if err := repo.CreateBranch(ctx, git.CreateBranchRequest{
+			Name: name,
+			Head: heads[i].String(),
+		}); err != nil {
+			return fmt.Errorf("create branch %v: %w", name, err)
+		}
+	}
+
+	if err := store.UpdateBranch(ctx, &state.UpdateRequest{
+		Restores: fold.Branches,
+		Upserts:  upserts,
+		Message:  fmt.Sprintf("unfolding %v from %v", strings.Join(fold.Branches, ", "), into),
+	}); err != nil {
+		return fmt.Errorf("update store: %w", err)
+	}
+
+	for i, name := range fold.Branches {
+		log.Infof("%v: restored at %v", name, heads[i].Short())
+	}
+	for _, u := range upserts {
+		log.Infof("%v: moved back onto %v", u.Name, top)
+	}
+
+	return cmd.resetInto(ctx, log, repo, into, top, fold.Hash, archived[fold.Branches[len(fold.Branches)-1]].BaseHash)
+}
+
+// branchFold is a fold found in the reflog of the branch folded into.
+type branchFold struct {
+	// Branches that were folded, from top to bottom.
+	Branches []string
+
+	// Hash is the head of the top-most folded branch
+	// that the target branch was fast-forwarded to.
+	Hash git.Hash
+}
+
+// findFold searches the reflog of 'into' for the most recent fold
+// that included cmd.Branch.
+func (cmd *branchUnfoldCmd) findFold(
+	ctx context.Context,
+	repo *git.Repository,
+	into string,
+	lookupArchived func(string) (*state.LookupResponse, error),
+) (*branchFold, error) {
+	entries, err := repo.Reflog(ctx, "refs/heads/"+into)
+	if err != nil {
+		return nil, fmt.Errorf("read reflog of %v: %w", into, err)
+	}
+
+	for _, entry := range entries {
+		// 'branch fold' fast-forwards the target with a local fetch.
+		// These are recorded as "fetch [...] . src:dst: fast-forward".
+		refspec, ok := strings.CutSuffix(entry.Message, ": fast-forward")
+		if !ok || !strings.HasPrefix(entry.Message, "fetch ") {
+			continue
+		}
+		refspec = refspec[strings.LastIndexByte(refspec, ' ')+1:]
+		src, dst, ok := strings.Cut(refspec, ":")
+		if !ok || dst != into {
+			continue
+		}
+
+		// src must lead down to 'into' through archived branches.
+		var branches []string
+		for name := src; name != into; {
+			b, err := lookupArchived(name)
+			if err != nil || slices.Contains(branches, name) {
+				branches = nil
+				break
+			}
+			branches = append(branches, name)
+			name = b.Base
+		}
+		if slices.Contains(branches, cmd.Branch) {
+			return &branchFold{
+				Branches: branches,
+				Hash:     entry.Hash,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%v: cannot find where it was folded into %v in the reflog", cmd.Branch, into)
+}
+
+// resetInto moves the branch that was folded into
+// back to where it was before the fold,
+// unless it has changed since then.
+func (*branchUnfoldCmd) resetInto(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	into, top string,
+	foldHash, oldHash git.Hash,
+) error {
+	intoHash, err := repo.PeelToCommit(ctx, into)
+	if err != nil {
+		return fmt.Errorf("resolve %v: %w", into, err)
+	}
+	if intoHash != foldHash {
+		log.Warnf("%v: has changed since the fold. Folded commits were left in it.", into)
+		return nil
+	}
+
+	localBranches, err := repo.LocalBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	currentBranch, err := repo.CurrentBranch(ctx)
+	if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+		return fmt.Errorf("get current branch: %w", err)
+	}
+
+	if currentBranch == into {
+		// The top-most folded branch points to the same commit,
+		// so this doesn't change the working tree.
+		if err := repo.Checkout(ctx, top); err != nil {
+			return fmt.Errorf("checkout %v: %w", top, err)
+		}
+	} else if slices.ContainsFunc(localBranches, func(b git.LocalBranch) bool {
+		return b.Name == into && b.CheckedOut
+	}) {
+		log.Warnf("%v: checked out in another worktree. Folded commits were left in it.", into)
+		return nil
+	}
+
+	if err := repo.SetRef(ctx, git.SetRefRequest{
+		Ref:     "refs/heads/" + into,
+		Hash:    oldHash,
+		OldHash: foldHash,
+	}); err != nil {
+		return fmt.Errorf("reset %v: %w", into, err)
+	}
+	log.Infof("%v: reset to %v", into, oldHash.Short())
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"go.abhg.dev/gs/internal/git"
+)
+
+// _defaultChecklistFile is the path, relative to the repository root,
+// of the file that maps paths to checklist items.
+// This may be overridden with 'git config spice.submit.checklistFile'.
+const _defaultChecklistFile = ".git-spice/checklist"
+
+// checklistRule adds a checklist item to a change
+// if any of the files changed in it match a pattern.
+type checklistRule struct {
+	// Pattern to match paths against.
+	//
+	// Patterns ending with "/" match all files under that directory.
+	// Other patterns are matched against the full path
+	// with path.Match.
+	Pattern string
+
+	// Item is the text of the checklist item.
+	Item string
+}
+
+func (r *checklistRule) Match(file string) bool {
+	if dir, ok := strings.CutSuffix(r.Pattern, "/"); ok {
+		return strings.HasPrefix(file, dir+"/")
+	}
+
+	ok, err := path.Match(r.Pattern, file)
+	return err == nil && ok
+}
+
+// parseChecklistRules parses a checklist file.
+//
+// Each non-empty line in the file is a rule in the form:
+//
+//	<pattern> <item>
+//
+// Lines starting with '#' are ignored.
+func parseChecklistRules(r io.Reader) ([]checklistRule, error) {
+	var (
+		rules  []checklistRule
+		lineNo int
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern, item, ok := strings.Cut(line, " ")
+		item = strings.TrimSpace(item)
+		if !ok || item == "" {
+			return nil, fmt.Errorf("line %d: expected '<pattern> <item>'", lineNo)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("line %d: bad pattern %q: %w", lineNo, pattern, err)
+		}
+
+		rules = append(rules, checklistRule{
+			Pattern: pattern,
+			Item:    item,
+		})
+	}
+
+	return rules, scanner.Err()
+}
+
+// matchChecklist returns the checklist items that apply
+// to the given list of changed files.
+// Items are returned in the order they were defined, without duplicates.
+func matchChecklist(rules []checklistRule, files []string) []string {
+	var (
+		items []string
+		seen  = make(map[string]struct{})
+	)
+	for _, rule := range rules {
+		if _, ok := seen[rule.Item]; ok {
+			continue
+		}
+
+		for _, f := range files {
+			if rule.Match(f) {
+				items = append(items, rule.Item)
+				seen[rule.Item] = struct{}{}
+				break
+			}
+		}
+	}
+	return items
+}
+
+// diffChecklist returns the checklist items that apply
+// to the files changed in branch since base.
+//
+// The rules are read from the checklist file at the head of the branch.
+// If there's no checklist file, no items are returned.
+func diffChecklist(ctx context.Context, repo *git.Repository, base, branch string) ([]string, error) {
+	checklistFile, err := repo.ConfigValue(ctx, "spice.submit.checklistFile")
+	if err != nil {
+		if !errors.Is(err, git.ErrNotExist) {
+			return nil, fmt.Errorf("read checklist file path: %w", err)
+		}
+		checklistFile = _defaultChecklistFile
+	}
+
+	blob, err := repo.HashAt(ctx, branch, checklistFile)
+	if err != nil {
+		if errors.Is(err, git.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("look up %v: %w", checklistFile, err)
+	}
+
+	var buf bytes.Buffer
+	if err := repo.ReadObject(ctx, git.BlobType, blob, &buf); err != nil {
+		return nil, fmt.Errorf("read %v: %w", checklistFile, err)
+	}
+
+	rules, err := parseChecklistRules(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("parse %v: %w", checklistFile, err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	files, err := repo.ChangedFiles(ctx, base, branch)
+	if err != nil {
+		return nil, fmt.Errorf("list changed files: %w", err)
+	}
+
+	return matchChecklist(rules, files), nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecklistRules(t *testing.T) {
+	rules, err := parseChecklistRules(strings.NewReader(`
+# Database changes
+migrations/ Ran the migration in staging
+
+*.proto   Updated API documentation
+docs/*.md Spell-checked the docs
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []checklistRule{
+		{Pattern: "migrations/", Item: "Ran the migration in staging"},
+		{Pattern: "*.proto", Item: "Updated API documentation"},
+		{Pattern: "docs/*.md", Item: "Spell-checked the docs"},
+	}, rules)
+}
+
+func TestParseChecklistRules_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		give string
+		want string
+	}{
+		{name: "NoItem", give: "migrations/", want: "line 1: expected"},
+		{name: "BlankItem", give: "\nmigrations/   ", want: "line 2: expected"},
+		{name: "BadPattern", give: "[ item", want: `line 1: bad pattern "["`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseChecklistRules(strings.NewReader(tt.give))
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestMatchChecklist(t *testing.T) {
+	rules := []checklistRule{
+		{Pattern: "migrations/", Item: "Ran the migration in staging"},
+		{Pattern: "*.proto", Item: "Updated API documentation"},
+		{Pattern: "api/*.proto", Item: "Updated API documentation"},
+		{Pattern: "docs/*.md", Item: "Spell-checked the docs"},
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{name: "Empty"},
+		{
+			name:  "NoMatch",
+			files: []string{"main.go", "docs/img/logo.png"},
+		},
+		{
+			name:  "Directory",
+			files: []string{"migrations/0001_init.sql"},
+			want:  []string{"Ran the migration in staging"},
+		},
+		{
+			name:  "DirectoryPrefixOnly",
+			files: []string{"migrations.go"},
+		},
+		{
+			name:  "Deduplicated",
+			files: []string{"api/foo.proto", "foo.proto", "docs/index.md"},
+			want:  []string{"Updated API documentation", "Spell-checked the docs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchChecklist(rules, tt.files))
+		})
+	}
+}
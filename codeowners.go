@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"go.abhg.dev/gs/internal/git"
+)
+
+// _codeownersFiles lists the paths, relative to the repository root,
+// at which a CODEOWNERS file is looked for.
+// The first one that exists is used.
+var _codeownersFiles = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// codeownersRule assigns owners to files matching a pattern.
+type codeownersRule struct {
+	// Pattern is the pattern as written in the CODEOWNERS file.
+	Pattern string
+
+	// Owners are the owners of matching files
+	// without the leading "@".
+	// Email addresses are omitted.
+	//
+	// This may be empty if the rule removes ownership.
+	Owners []string
+
+	re *regexp.Regexp
+}
+
+// Match reports whether the rule applies to the given file.
+func (r *codeownersRule) Match(file string) bool {
+	return r.re.MatchString(file)
+}
+
+// parseCodeowners parses a CODEOWNERS file.
+//
+// Each non-empty line in the file is a rule in the form:
+//
+//	<pattern> [<owner> ...]
+//
+// Lines starting with '#' are ignored,
+// as is anything following a '#' on a line.
+func parseCodeowners(r io.Reader) ([]codeownersRule, error) {
+	var (
+		rules  []codeownersRule
+		lineNo int
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := fields[0]
+		re, err := codeownersPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad pattern %q: %w", lineNo, pattern, err)
+		}
+
+		var owners []string
+		for _, owner := range fields[1:] {
+			// Email addresses can't be requested as reviewers.
+			if name, ok := strings.CutPrefix(owner, "@"); ok {
+				owners = append(owners, name)
+			}
+		}
+
+		rules = append(rules, codeownersRule{
+			Pattern: pattern,
+			Owners:  owners,
+			re:      re,
+		})
+	}
+
+	return rules, scanner.Err()
+}
+
+// codeownersPattern compiles a CODEOWNERS pattern into a regular expression
+// that matches the paths of files it applies to.
+//
+// Patterns follow gitignore syntax:
+//
+//   - patterns containing a "/" other than at the end
+//     are relative to the repository root;
+//     others match at any depth
+//   - patterns ending with "/" match only files inside that directory
+//   - "*" matches anything except "/", and "?" a single character
+//   - "**" matches any number of directories
+//   - a pattern that matches a directory applies to all files inside it
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	p, dirOnly := strings.CutSuffix(pattern, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, errors.New("empty pattern")
+	}
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case strings.HasPrefix(p[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "/**") && i+3 == len(p):
+			re.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if dirOnly {
+		re.WriteString("/.*$")
+	} else {
+		re.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(re.String())
+}
+
+// matchCodeowners returns the owners of the given list of files.
+//
+// Each file is owned by the owners of the last rule that matches it.
+// Owners are returned in the order they are first seen,
+// without duplicates.
+func matchCodeowners(rules []codeownersRule, files []string) []string {
+	var (
+		owners []string
+		seen   = make(map[string]struct{})
+	)
+	for _, f := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			rule := &rules[i]
+			if !rule.Match(f) {
+				continue
+			}
+
+			for _, owner := range rule.Owners {
+				if _, ok := seen[owner]; !ok {
+					owners = append(owners, owner)
+					seen[owner] = struct{}{}
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// diffCodeowners returns the owners of the files changed
+// in branch since base.
+//
+// The CODEOWNERS file is read from the head of the branch.
+// If there's no CODEOWNERS file, no owners are returned.
+func diffCodeowners(ctx context.Context, repo *git.Repository, base, branch string) ([]string, error) {
+	var (
+		file string
+		blob git.Hash
+	)
+	for _, f := range _codeownersFiles {
+		h, err := repo.HashAt(ctx, branch, f)
+		if err != nil {
+			if errors.Is(err, git.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("look up %v: %w", f, err)
+		}
+		file, blob = f, h
+		break
+	}
+	if file == "" {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := repo.ReadObject(ctx, git.BlobType, blob, &buf); err != nil {
+		return nil, fmt.Errorf("read %v: %w", file, err)
+	}
+
+	rules, err := parseCodeowners(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("parse %v: %w", file, err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	files, err := repo.ChangedFiles(ctx, base, branch)
+	if err != nil {
+		return nil, fmt.Errorf("list changed files: %w", err)
+	}
+
+	return matchCodeowners(rules, files), nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	rules, err := parseCodeowners(strings.NewReader(`
+# Default owners
+*       @alice
+
+/docs/  @acme/docs-team docs@example.com # trailing comment
+*.go    @bob @carol
+vendor/
+`))
+	require.NoError(t, err)
+
+	type rule struct {
+		Pattern string
+		Owners  []string
+	}
+	got := make([]rule, len(rules))
+	for i, r := range rules {
+		got[i] = rule{Pattern: r.Pattern, Owners: r.Owners}
+	}
+	assert.Equal(t, []rule{
+		{Pattern: "*", Owners: []string{"alice"}},
+		{Pattern: "/docs/", Owners: []string{"acme/docs-team"}},
+		{Pattern: "*.go", Owners: []string{"bob", "carol"}},
+		{Pattern: "vendor/"},
+	}, got)
+}
+
+func TestCodeownersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "*",
+			match:   []string{"README.md", "a/b/c.go"},
+		},
+		{
+			pattern: "*.js",
+			match:   []string{"app.js", "web/src/app.js"},
+			noMatch: []string{"app.jsx", "app.js.map"},
+		},
+		{
+			pattern: "/build/logs/",
+			match:   []string{"build/logs/a.log", "build/logs/deep/b.log"},
+			noMatch: []string{"src/build/logs/a.log", "build/logs"},
+		},
+		{
+			pattern: "apps/",
+			match:   []string{"apps/a.go", "src/apps/b.go"},
+			noMatch: []string{"apps", "myapps/a.go"},
+		},
+		{
+			pattern: "docs/*",
+			match:   []string{"docs/index.md"},
+			noMatch: []string{"src/docs/index.md"},
+		},
+		{
+			pattern: "docs",
+			match:   []string{"docs", "docs/index.md", "src/docs/x/y.md"},
+			noMatch: []string{"docs.md"},
+		},
+		{
+			pattern: "**/logs",
+			match:   []string{"logs/a.log", "build/logs/a.log"},
+			noMatch: []string{"catalogs/a"},
+		},
+		{
+			pattern: "/scripts/**",
+			match:   []string{"scripts/a.sh", "scripts/x/b.sh"},
+			noMatch: []string{"src/scripts/a.sh"},
+		},
+		{
+			pattern: "a/**/b.txt",
+			match:   []string{"a/b.txt", "a/x/b.txt", "a/x/y/b.txt"},
+			noMatch: []string{"ab.txt", "x/a/b.txt"},
+		},
+		{
+			pattern: "file?.txt",
+			match:   []string{"file1.txt"},
+			noMatch: []string{"file10.txt", "file/.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := codeownersPattern(tt.pattern)
+			require.NoError(t, err)
+
+			for _, f := range tt.match {
+				assert.True(t, re.MatchString(f), "should match %q", f)
+			}
+			for _, f := range tt.noMatch {
+				assert.False(t, re.MatchString(f), "should not match %q", f)
+			}
+		})
+	}
+}
+
+func TestMatchCodeowners(t *testing.T) {
+	rules, err := parseCodeowners(strings.NewReader(`
+*           @alice
+*.go        @bob
+/api/       @acme/api @bob
+/api/gen/
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{name: "Empty"},
+		{
+			name:  "Default",
+			files: []string{"README.md"},
+			want:  []string{"alice"},
+		},
+		{
+			name:  "LastMatchWins",
+			files: []string{"main.go"},
+			want:  []string{"bob"},
+		},
+		{
+			name:  "Deduplicated",
+			files: []string{"api/server.go", "cmd/main.go", "README.md"},
+			want:  []string{"acme/api", "bob", "alice"},
+		},
+		{
+			name:  "Unowned",
+			files: []string{"api/gen/server.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchCodeowners(rules, tt.files))
+		})
+	}
+}
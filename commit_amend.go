@@ -26,7 +26,7 @@ func (*commitAmendCmd) Help() string {
 }
 
 func (cmd *commitAmendCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, opts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
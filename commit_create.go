@@ -25,7 +25,7 @@ func (*commitCreateCmd) Help() string {
 }
 
 func (cmd *commitCreateCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, opts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
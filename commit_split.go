@@ -23,7 +23,7 @@ func (*commitSplitCmd) Help() string {
 }
 
 func (cmd *commitSplitCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) (err error) {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, opts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
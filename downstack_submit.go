@@ -56,6 +56,12 @@ func (cmd *downstackSubmitCmd) Run(
 	must.NotBeEmptyf(downstacks, "downstack cannot be empty")
 	slices.Reverse(downstacks)
 
+	// Uncommitted changes stop the whole submit,
+	// so check for them before pushing anything.
+	if err := cmd.verifyWorktree(ctx, repo, log, downstacks); err != nil {
+		return err
+	}
+
 	// TODO: generalize into a service-level method
 	// TODO: separate preparation of the stack from submission
 	// TODO: submits should be done in parallel
@@ -70,16 +76,28 @@ func (cmd *downstackSubmitCmd) Run(
 		}
 	}
 
-	if cmd.DryRun {
-		return nil
+	commentOpts, err := cmd.stackCommentOptions(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	limit, err := session.limiter(ctx, repo)
+	if err != nil {
+		return err
 	}
 
-	return syncStackComments(
+	if err := syncStackComments(
 		ctx,
 		store,
 		svc,
 		session.remoteRepo.Require(),
+		limit,
 		log,
 		session.branches,
-	)
+		commentOpts,
+	); err != nil {
+		return err
+	}
+
+	return cmd.checkPending(&session)
 }
@@ -3,8 +3,10 @@ package main
 import (
 	"cmp"
 	"context"
+	"errors"
 	"os"
 
+	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
 )
 
@@ -19,3 +21,19 @@ func gitEditor(ctx context.Context, repo *git.Repository) string {
 	}
 	return gitEditor
 }
+
+// bodyEditor returns the editor to use
+// to write the body of a change request.
+//
+// This is the editor set in 'git config spice.submit.editor',
+// or the Git editor if that isn't set.
+func bodyEditor(ctx context.Context, repo *git.Repository, log *log.Logger) string {
+	editor, err := repo.ConfigValue(ctx, "spice.submit.editor")
+	if err != nil {
+		if !errors.Is(err, git.ErrNotExist) {
+			log.Warn("Could not read body editor", "error", err)
+		}
+		return gitEditor(ctx, repo)
+	}
+	return cmp.Or(editor, gitEditor(ctx, repo))
+}
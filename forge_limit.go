@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/git"
+)
+
+// _defaultForgeConcurrency is the maximum number of forge API calls
+// that are made at the same time
+// unless overridden with 'git config spice.forge.concurrency'.
+const _defaultForgeConcurrency = 4
+
+// forgeLimiter bounds the number of forge API calls
+// that may be in flight at the same time.
+//
+// A single forgeLimiter should be shared between
+// all operations that talk to the forge concurrently
+// so that they respect the same limit.
+type forgeLimiter struct {
+	sem chan struct{}
+}
+
+func newForgeLimiter(n int) *forgeLimiter {
+	return &forgeLimiter{sem: make(chan struct{}, n)}
+}
+
+// loadForgeLimiter builds a forgeLimiter
+// with the limit configured for the repository.
+func loadForgeLimiter(ctx context.Context, repo *git.Repository) (*forgeLimiter, error) {
+	value, err := repo.ConfigValue(ctx, "spice.forge.concurrency")
+	if err != nil {
+		if errors.Is(err, git.ErrNotExist) {
+			return newForgeLimiter(_defaultForgeConcurrency), nil
+		}
+		return nil, fmt.Errorf("read spice.forge.concurrency: %w", err)
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("spice.forge.concurrency must be a positive integer: %q", value)
+	}
+	return newForgeLimiter(n), nil
+}
+
+// Size reports the maximum number of concurrent calls.
+func (l *forgeLimiter) Size() int {
+	return cap(l.sem)
+}
+
+// Do runs f once a slot is available, blocking until then,
+// and returns its error.
+// It returns the context's error without running f
+// if the context is cancelled while waiting.
+func (l *forgeLimiter) Do(ctx context.Context, f func() error) error {
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-l.sem }()
+
+	return f()
+}
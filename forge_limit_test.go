@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgeLimiter(t *testing.T) {
+	ctx := context.Background()
+	limit := newForgeLimiter(2)
+	assert.Equal(t, 2, limit.Size())
+
+	var (
+		wg            sync.WaitGroup
+		running, peak atomic.Int32
+		release       = make(chan struct{})
+		started       = make(chan struct{}, 5)
+	)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, limit.Do(ctx, func() error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				started <- struct{}{}
+				<-release
+				running.Add(-1)
+				return nil
+			}))
+		}()
+	}
+
+	// Two calls are allowed in at once.
+	<-started
+	<-started
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestForgeLimiter_error(t *testing.T) {
+	limit := newForgeLimiter(1)
+	giveErr := errors.New("great sadness")
+	err := limit.Do(context.Background(), func() error {
+		return giveErr
+	})
+	assert.ErrorIs(t, err, giveErr)
+
+	// The slot is released after an error.
+	require.NoError(t, limit.Do(context.Background(), func() error {
+		return nil
+	}))
+}
+
+func TestForgeLimiter_canceled(t *testing.T) {
+	limit := newForgeLimiter(1)
+	hold := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = limit.Do(context.Background(), func() error {
+			<-hold
+			return nil
+		})
+	}()
+
+	// Wait for the slot to be taken.
+	for len(limit.sem) == 0 {
+		runtime.Gosched()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := limit.Do(ctx, func() error {
+		t.Error("must not run")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(hold)
+	<-done
+}
@@ -2,8 +2,11 @@
 package execedit
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 // Command constructs a command to open the editor
@@ -26,3 +29,83 @@ func Command(edit string, args ...string) *exec.Cmd {
 	cmd.Stderr = os.Stderr
 	return cmd
 }
+
+// Resolve reports an error if the executable for the given editor command
+// cannot be found.
+//
+// The editor command may be a binary name,
+// or a shell command optionally preceded by environment variables:
+//
+//	FOO=bar gvim --nofork
+func Resolve(edit string) error {
+	if _, err := exec.LookPath(edit); err == nil {
+		return nil
+	}
+
+	name, err := commandName(edit)
+	if err != nil {
+		return fmt.Errorf("editor %q: %w", edit, err)
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("editor %q: %w", edit, err)
+	}
+	return nil
+}
+
+// commandName returns the name of the program run by a shell command,
+// skipping over leading environment variable assignments.
+// Single and double quotes in the command are respected.
+func commandName(command string) (string, error) {
+	for command != "" {
+		var word string
+		word, command = nextWord(command)
+		if word == "" {
+			continue
+		}
+
+		if name, _, ok := strings.Cut(word, "="); ok && isEnvName(name) {
+			continue
+		}
+		return word, nil
+	}
+	return "", errors.New("no command")
+}
+
+// nextWord splits the first shell word off s,
+// removing any quotes around it.
+func nextWord(s string) (word, rest string) {
+	s = strings.TrimLeft(s, " \t")
+
+	var (
+		sb    strings.Builder
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			sb.WriteByte(c)
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ' ' || c == '\t':
+			return sb.String(), s[i+1:]
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), ""
+}
+
+func isEnvName(s string) bool {
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, c := range s {
+		if c != '_' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package execedit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		give string
+		want string
+	}{
+		{give: "vim", want: "vim"},
+		{give: "code --wait", want: "code"},
+		{give: "  nvim -f", want: "nvim"},
+		{give: "FOO=bar gvim --nofork", want: "gvim"},
+		{give: "A=1 B='x y' emacs", want: "emacs"},
+		{give: `"/opt/My Editor/bin/edit" --wait`, want: "/opt/My Editor/bin/edit"},
+		{give: "'my editor'", want: "my editor"},
+		{give: "./edit=1", want: "./edit=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := commandName(tt.give)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommandName_empty(t *testing.T) {
+	for _, give := range []string{"", "   ", "FOO=bar"} {
+		_, err := commandName(give)
+		assert.Error(t, err, "%q", give)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		assert.NoError(t, Resolve("sh"))
+		assert.NoError(t, Resolve("FOO=bar sh -c true"))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := Resolve("EDITOR_MODE=1 git-spice-no-such-editor --wait")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "git-spice-no-such-editor")
+	})
+}
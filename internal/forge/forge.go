@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"go.abhg.dev/gs/internal/git"
@@ -19,11 +20,23 @@ import (
 
 var _forgeRegistry sync.Map
 
-// All is an iterator that yields all registered forges.
+// All is an iterator that yields all registered forges
+// in order of their IDs.
 func All(yield func(Forge) bool) {
+	var forges []Forge
 	_forgeRegistry.Range(func(_, value any) bool {
-		return yield(value.(Forge))
+		forges = append(forges, value.(Forge))
+		return true
+	})
+	sort.Slice(forges, func(i, j int) bool {
+		return forges[i].ID() < forges[j].ID()
 	})
+
+	for _, f := range forges {
+		if !yield(f) {
+			return
+		}
+	}
 }
 
 // IDs returns a sorted list of all registered forge IDs.
@@ -33,7 +46,6 @@ func IDs() []string {
 		names = append(names, f.ID())
 		return true
 	})
-	sort.Strings(names)
 	return names
 }
 
@@ -58,9 +70,12 @@ func Lookup(id string) (Forge, bool) {
 
 // MatchForgeURL attempts to match the given remote URL with a registered forge.
 // Returns the matched forge and true if a match was found.
+//
+// If multiple forges match the URL,
+// the one with the lexically smallest ID is picked.
+// Callers should allow overriding this for ambiguous setups.
 func MatchForgeURL(remoteURL string) (forge Forge, ok bool) {
-	_forgeRegistry.Range(func(_, value any) (keepGoing bool) {
-		f := value.(Forge)
+	All(func(f Forge) (keepGoing bool) {
 		if f.MatchURL(remoteURL) {
 			forge = f
 			ok = true
@@ -71,6 +86,15 @@ func MatchForgeURL(remoteURL string) (forge Forge, ok bool) {
 	return forge, ok
 }
 
+// MatchHost reports whether the host of a remote URL
+// belongs to a forge hosted at baseHost.
+// This is the case if the host is baseHost or any subdomain of it,
+// so a forge configured with "example.com"
+// also matches remotes on "ssh.example.com".
+func MatchHost(host, baseHost string) bool {
+	return host == baseHost || strings.HasSuffix(host, "."+baseHost)
+}
+
 // ErrUnsupportedURL indicates that the given remote URL
 // does not match any registered forge.
 var ErrUnsupportedURL = errors.New("unsupported URL")
@@ -135,19 +159,45 @@ type AuthenticationToken interface {
 	secret() // marker method
 }
 
+// ErrChangeConflict indicates that a change could not be edited
+// because it was modified concurrently.
+// The edit may succeed if it's retried after fetching the change again.
+var ErrChangeConflict = errors.New("change was modified concurrently")
+
 // Repository is a Git repository hosted on a forge.
 type Repository interface {
 	Forge() Forge
 
 	SubmitChange(ctx context.Context, req SubmitChangeRequest) (SubmitChangeResult, error)
+
+	// EditChange edits an existing change.
+	//
+	// If the change was modified concurrently in a way
+	// that conflicts with the edit, this returns an error
+	// matching ErrChangeConflict.
 	EditChange(ctx context.Context, id ChangeID, opts EditChangeOptions) error
+
+	// CloseChange closes an open change without merging it.
+	CloseChange(ctx context.Context, id ChangeID) error
+
 	FindChangesByBranch(ctx context.Context, branch string, opts FindChangesOptions) ([]*FindChangeItem, error)
 	FindChangeByID(ctx context.Context, id ChangeID) (*FindChangeItem, error)
 	ChangeIsMerged(ctx context.Context, id ChangeID) (bool, error)
 
-	// Post and update comments on changes.
+	// ChangeChecksState reports the combined state of the checks
+	// (e.g. CI jobs) that ran against the head of a change.
+	ChangeChecksState(ctx context.Context, id ChangeID) (ChecksState, error)
+
+	// EnqueueMerge arranges for a change to be merged
+	// once it's ready, e.g. after its checks pass.
+	// The change is added to the merge queue of its base branch
+	// if the forge supports one, and set to merge automatically otherwise.
+	EnqueueMerge(ctx context.Context, id ChangeID, method MergeMethod) (EnqueueMergeResult, error)
+
+	// Post, update, and read comments on changes.
 	PostChangeComment(context.Context, ChangeID, string) (ChangeCommentID, error)
 	UpdateChangeComment(context.Context, ChangeCommentID, string) error
+	ChangeComment(context.Context, ChangeCommentID) (string, error)
 
 	// NewChangeMetadata builds a ChangeMetadata for the given change ID.
 	//
@@ -160,6 +210,47 @@ type Repository interface {
 	//
 	// Returns an empty list if no templates are found.
 	ListChangeTemplates(context.Context) ([]*ChangeTemplate, error)
+
+	// CurrentUser reports the user that is authenticated
+	// against the forge.
+	CurrentUser(context.Context) (User, error)
+
+	// FindMilestone looks up a milestone in the repository
+	// by its title or number.
+	//
+	// Returns an error matching ErrMilestoneNotFound
+	// if there's no such milestone.
+	FindMilestone(ctx context.Context, name string) (*Milestone, error)
+}
+
+// ErrMilestoneNotFound indicates that a milestone
+// does not exist in a repository.
+var ErrMilestoneNotFound = errors.New("milestone not found")
+
+// Milestone is a milestone in a repository
+// that changes can be attached to.
+type Milestone struct {
+	// Title is the name of the milestone.
+	Title string
+
+	// Number identifies the milestone within the repository.
+	Number int
+}
+
+// User is a user of a forge.
+type User struct {
+	// Login is the username of the user.
+	// This is the name used to refer to the user,
+	// for example, when requesting reviews.
+	Login string
+
+	// ID is a unique identifier for the user.
+	// Its format is forge-specific.
+	ID string
+
+	// Name is the display name of the user.
+	// This may be empty.
+	Name string
 }
 
 // ChangeID is a unique identifier for a change in a repository.
@@ -197,6 +288,16 @@ type ChangeMetadata interface {
 	//
 	// The ID may be nil to indicate that there is no stack comment.
 	SetStackCommentID(ChangeCommentID)
+
+	// CustomCommentID is a user-provided comment
+	// posted on the Change when it was submitted.
+	CustomCommentID() ChangeCommentID
+
+	// SetCustomCommentID sets the ID of the custom comment
+	// on the change metadata to persist it later.
+	//
+	// The ID may be nil to indicate that there is no custom comment.
+	SetCustomCommentID(ChangeCommentID)
 }
 
 // FindChangesOptions specifies filtering options
@@ -230,6 +331,20 @@ type SubmitChangeRequest struct {
 
 	// Draft specifies whether the change should be marked as a draft.
 	Draft bool
+
+	// Labels are the names of labels to add to the change.
+	//
+	// Forges may create labels that don't exist in the repository,
+	// or skip them with a warning.
+	Labels []string
+
+	// Milestone is the title or number of a milestone
+	// to attach to the change.
+	// Forges without support for milestones may ignore this.
+	Milestone string
+
+	// Assignees are the logins of users to assign the change to.
+	Assignees []string
 }
 
 // SubmitChangeResult is the result of creating a new change in a repository.
@@ -249,6 +364,31 @@ type EditChangeOptions struct {
 	// Draft specifies whether the change should be marked as a draft.
 	// If unset, the draft status is not changed.
 	Draft *bool
+
+	// Title and Body specify the new title and body of the change.
+	// If unset, the corresponding field is not changed.
+	Title, Body *string
+
+	// AddLabels and RemoveLabels specify the names of labels
+	// to add to and remove from the change.
+	// Labels that are already in the desired state are ignored.
+	AddLabels, RemoveLabels []string
+
+	// AddReviewers specifies users or teams to request reviews from.
+	// Teams are specified as "org/team".
+	// Reviewers that were already requested are ignored.
+	AddReviewers []string
+
+	// Milestone is the title or number of a milestone
+	// to attach to the change.
+	// If unset, the milestone is not changed.
+	// Forges without support for milestones may ignore this.
+	Milestone string
+
+	// AddAssignees specifies the logins of users to assign the change to
+	// if it isn't assigned to anyone yet.
+	// Changes that already have assignees are left unchanged.
+	AddAssignees []string
 }
 
 // FindChangeItem is a single result from searching for changes in the
@@ -266,6 +406,9 @@ type FindChangeItem struct {
 	// Subject is the title of the change.
 	Subject string
 
+	// Body is the description of the change.
+	Body string
+
 	// HeadHash is the hash of the commit at the top of the change.
 	HeadHash git.Hash
 
@@ -275,6 +418,17 @@ type FindChangeItem struct {
 
 	// Draft is true if the change is not yet ready to be reviewed.
 	Draft bool
+
+	// Labels are the names of the labels on the change.
+	Labels []string
+
+	// Assignees are the usernames of the users
+	// that the change is assigned to.
+	Assignees []string
+
+	// Milestone is the title of the milestone
+	// that the change is part of, if any.
+	Milestone string
 }
 
 // ChangeTemplate is a template for a new change proposal.
@@ -340,3 +494,95 @@ func (s *ChangeState) UnmarshalText(b []byte) error {
 	}
 	return nil
 }
+
+// ChecksState is the combined state of all checks
+// reported against a change.
+type ChecksState int
+
+const (
+	// ChecksNone specifies that no checks were reported for a change.
+	ChecksNone ChecksState = iota
+
+	// ChecksPending specifies that some checks are still running,
+	// and none of the completed checks have failed.
+	ChecksPending
+
+	// ChecksPassing specifies that all checks have passed.
+	ChecksPassing
+
+	// ChecksFailing specifies that at least one check has failed.
+	ChecksFailing
+)
+
+func (s ChecksState) String() string {
+	switch s {
+	case ChecksNone:
+		return "none"
+	case ChecksPending:
+		return "pending"
+	case ChecksPassing:
+		return "passing"
+	case ChecksFailing:
+		return "failing"
+	default:
+		return "unknown"
+	}
+}
+
+// MergeMethod is the method used to merge a change.
+type MergeMethod int
+
+const (
+	// MergeMethodDefault uses the forge's default for the repository.
+	MergeMethodDefault MergeMethod = iota
+
+	// MergeMethodMerge merges the change with a merge commit.
+	MergeMethodMerge
+
+	// MergeMethodSquash squashes the change into a single commit.
+	MergeMethodSquash
+
+	// MergeMethodRebase rebases the commits of the change
+	// onto the base branch.
+	MergeMethodRebase
+)
+
+func (m MergeMethod) String() string {
+	switch m {
+	case MergeMethodDefault:
+		return "default"
+	case MergeMethodMerge:
+		return "merge"
+	case MergeMethodSquash:
+		return "squash"
+	case MergeMethodRebase:
+		return "rebase"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalText decodes a MergeMethod from text.
+// An empty string is the default method.
+func (m *MergeMethod) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "", "default":
+		*m = MergeMethodDefault
+	case "merge":
+		*m = MergeMethodMerge
+	case "squash":
+		*m = MergeMethodSquash
+	case "rebase":
+		*m = MergeMethodRebase
+	default:
+		return fmt.Errorf("unknown merge method: %q", bs)
+	}
+	return nil
+}
+
+// EnqueueMergeResult is the result of enqueuing a change for merge.
+type EnqueueMergeResult struct {
+	// AutoMerge reports whether the change was set to merge automatically
+	// instead of being added to a merge queue.
+	AutoMerge bool
+}
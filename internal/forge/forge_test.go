@@ -53,6 +53,53 @@ func TestRegister(t *testing.T) {
 	})
 }
 
+func TestMatchForgeURL_multipleMatches(t *testing.T) {
+	// Register in reverse order to ensure that
+	// registration order doesn't matter.
+	for _, id := range []string{"z", "y", "x"} {
+		defer forge.Register(stubForge{
+			id:      id,
+			baseURL: "https://multi.example.com",
+		})()
+	}
+
+	for range 10 {
+		f, ok := forge.MatchForgeURL("https://multi.example.com/foo")
+		require.True(t, ok, "forge not found")
+		assert.Equal(t, "x", f.ID(), "forge ID mismatch")
+	}
+
+	t.Run("All", func(t *testing.T) {
+		var ids []string
+		forge.All(func(f forge.Forge) bool {
+			ids = append(ids, f.ID())
+			return true
+		})
+		assert.IsIncreasing(t, ids)
+	})
+}
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		host, baseHost string
+		want           bool
+	}{
+		{"example.com", "example.com", true},
+		{"ssh.example.com", "example.com", true},
+		{"a.b.example.com", "example.com", true},
+		{"example.com:8080", "example.com:8080", true},
+		{"notexample.com", "example.com", false},
+		{"example.com.evil.org", "example.com", false},
+		{"example.com", "ssh.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host+"/"+tt.baseHost, func(t *testing.T) {
+			assert.Equal(t, tt.want, forge.MatchHost(tt.host, tt.baseHost))
+		})
+	}
+}
+
 type stubForge struct {
 	forge.Forge
 
@@ -114,3 +161,36 @@ func TestChangeState(t *testing.T) {
 		})
 	})
 }
+
+func TestMergeMethod(t *testing.T) {
+	tests := []struct {
+		method forge.MergeMethod
+		str    string
+	}{
+		{forge.MergeMethodDefault, "default"},
+		{forge.MergeMethodMerge, "merge"},
+		{forge.MergeMethodSquash, "squash"},
+		{forge.MergeMethodRebase, "rebase"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			assert.Equal(t, tt.str, tt.method.String())
+
+			var m forge.MergeMethod
+			require.NoError(t, m.UnmarshalText([]byte(tt.str)))
+			assert.Equal(t, tt.method, m)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		m := forge.MergeMethodSquash
+		require.NoError(t, m.UnmarshalText(nil))
+		assert.Equal(t, forge.MergeMethodDefault, m)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		var m forge.MergeMethod
+		assert.Error(t, m.UnmarshalText([]byte("fast-forward")))
+	})
+}
@@ -0,0 +1,125 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// AuthenticationToken defines the token returned by the Gitea forge.
+type AuthenticationToken struct {
+	forge.AuthenticationToken
+
+	// AccessToken is the Gitea access token.
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+var _ forge.AuthenticationToken = (*AuthenticationToken)(nil)
+
+// AuthenticationFlow prompts the user to authenticate with Gitea.
+// This rejects the request if the user is already authenticated
+// with a GITEA_TOKEN environment variable.
+func (f *Forge) AuthenticationFlow(ctx context.Context) (forge.AuthenticationToken, error) {
+	// Already authenticated with GITEA_TOKEN.
+	// If the user tries to authenticate again, we should error.
+	if f.Options.Token != "" {
+		f.Log.Error("Already authenticated with GITEA_TOKEN.")
+		f.Log.Error("Unset GITEA_TOKEN to login with a different method.")
+		return nil, errors.New("already authenticated")
+	}
+
+	auth := &PATAuthenticator{
+		URL:    f.URL(),
+		Stdin:  os.Stdin,
+		Stderr: os.Stderr,
+	}
+	return auth.Authenticate(ctx)
+}
+
+// SaveAuthenticationToken saves the given authentication token to the stash.
+func (f *Forge) SaveAuthenticationToken(stash secret.Stash, t forge.AuthenticationToken) error {
+	gt := t.(*AuthenticationToken)
+	if f.Options.Token != "" && f.Options.Token == gt.AccessToken {
+		// If the user has set GITEA_TOKEN,
+		// we should not save it to the stash.
+		return nil
+	}
+
+	bs, err := json.Marshal(gt)
+	if err != nil {
+		return fmt.Errorf("marshal token: %w", err)
+	}
+
+	return stash.SaveSecret(f.URL(), "token", string(bs))
+}
+
+// LoadAuthenticationToken loads the authentication token from the stash.
+// If the user has set GITEA_TOKEN, it will be used instead.
+func (f *Forge) LoadAuthenticationToken(stash secret.Stash) (forge.AuthenticationToken, error) {
+	if f.Options.Token != "" {
+		// If the user has set GITEA_TOKEN, we should use that
+		// regardless of what's in the stash.
+		return &AuthenticationToken{AccessToken: f.Options.Token}, nil
+	}
+
+	tokstr, err := stash.LoadSecret(f.URL(), "token")
+	if err != nil {
+		return nil, fmt.Errorf("load token: %w", err)
+	}
+
+	var tok AuthenticationToken
+	if err := json.Unmarshal([]byte(tokstr), &tok); err != nil {
+		return nil, fmt.Errorf("unmarshal token: %w", err)
+	}
+
+	return &tok, nil
+}
+
+// ClearAuthenticationToken removes the authentication token from the stash.
+func (f *Forge) ClearAuthenticationToken(stash secret.Stash) error {
+	return stash.DeleteSecret(f.URL(), "token")
+}
+
+// PATAuthenticator implements access token authentication for Gitea.
+type PATAuthenticator struct {
+	// URL is the base URL of the Gitea instance.
+	URL string // required
+
+	Stdin  io.Reader // required
+	Stderr io.Writer // required
+}
+
+// Authenticate prompts the user for an Access Token,
+// and returns the token if successful.
+func (a *PATAuthenticator) Authenticate(ctx context.Context) (*AuthenticationToken, error) {
+	desc := text.Dedentf(`
+	Generate an Access Token with read and write access
+	to repositories and issues
+	from %s/user/settings/applications.
+	`, strings.TrimSuffix(a.URL, "/"))
+
+	var token string
+	err := ui.Run(ui.NewInput().
+		WithTitle("Enter Access Token").
+		WithDescription(desc).
+		WithValidate(func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return errors.New("token is required")
+			}
+			return nil
+		}).WithValue(&token),
+		ui.WithInput(a.Stdin),
+		ui.WithOutput(a.Stderr),
+	)
+
+	return &AuthenticationToken{AccessToken: strings.TrimSpace(token)}, err
+}
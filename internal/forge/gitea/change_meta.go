@@ -0,0 +1,107 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// PRMetadata is the metadata for a pull request.
+type PRMetadata struct {
+	PR *PR `json:"pr,omitempty"`
+
+	StackComment  *PRComment `json:"comment,omitempty"`
+	CustomComment *PRComment `json:"customComment,omitempty"`
+}
+
+var _ forge.ChangeMetadata = (*PRMetadata)(nil)
+
+// ForgeID reports the forge ID that owns this metadata.
+func (*PRMetadata) ForgeID() string {
+	return "gitea"
+}
+
+// ChangeID reports the change ID of the pull request.
+func (m *PRMetadata) ChangeID() forge.ChangeID {
+	return m.PR
+}
+
+// StackCommentID reports the comment ID of the stack comment
+// left on the pull request.
+func (m *PRMetadata) StackCommentID() forge.ChangeCommentID {
+	if m.StackComment == nil {
+		return nil
+	}
+	return m.StackComment
+}
+
+// SetStackCommentID sets the comment ID of the stack comment
+// left on the pull request.
+//
+// id may be nil.
+func (m *PRMetadata) SetStackCommentID(id forge.ChangeCommentID) {
+	m.StackComment = mustPRComment(id)
+}
+
+// CustomCommentID reports the comment ID of the custom comment
+// posted on the pull request with 'submit --comment'.
+func (m *PRMetadata) CustomCommentID() forge.ChangeCommentID {
+	if m.CustomComment == nil {
+		return nil
+	}
+	return m.CustomComment
+}
+
+// SetCustomCommentID sets the comment ID of the custom comment
+// posted on the pull request.
+//
+// id may be nil.
+func (m *PRMetadata) SetCustomCommentID(id forge.ChangeCommentID) {
+	m.CustomComment = mustPRComment(id)
+}
+
+// NewChangeMetadata returns the metadata for a pull request.
+func (r *Repository) NewChangeMetadata(
+	ctx context.Context,
+	id forge.ChangeID,
+) (forge.ChangeMetadata, error) {
+	return &PRMetadata{PR: mustPR(id)}, nil
+}
+
+// MarshalChangeMetadata serializes a PRMetadata into JSON.
+func (*Forge) MarshalChangeMetadata(md forge.ChangeMetadata) (json.RawMessage, error) {
+	return json.Marshal(md)
+}
+
+// UnmarshalChangeMetadata deserializes a PRMetadata from JSON.
+func (*Forge) UnmarshalChangeMetadata(data json.RawMessage) (forge.ChangeMetadata, error) {
+	var md PRMetadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("unmarshal PR metadata: %w", err)
+	}
+	return &md, nil
+}
+
+// PR uniquely identifies a pull request in a Gitea repository.
+// It's a valid forge.ChangeID.
+type PR struct {
+	// Number is the pull request number.
+	// This is the same as the number of the issue backing it.
+	Number int `json:"number"`
+}
+
+var _ forge.ChangeID = (*PR)(nil)
+
+func mustPR(cid forge.ChangeID) *PR {
+	pr, ok := cid.(*PR)
+	if !ok {
+		panic(fmt.Sprintf("unexpected change ID type: %T", cid))
+	}
+	return pr
+}
+
+func (id *PR) String() string {
+	return fmt.Sprintf("#%d", id.Number)
+}
@@ -0,0 +1,48 @@
+package gitea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestMustPR(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.Equal(t, &PR{Number: 42}, mustPR(&PR{Number: 42}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var x struct{ forge.ChangeID }
+
+		assert.Panics(t, func() {
+			mustPR(&x)
+		})
+	})
+}
+
+func TestPRString(t *testing.T) {
+	assert.Equal(t, "#42", (&PR{Number: 42}).String())
+}
+
+func TestPRMetadata_roundTrip(t *testing.T) {
+	var f Forge
+
+	md := &PRMetadata{PR: &PR{Number: 42}}
+	md.SetStackCommentID(&PRComment{ID: 100, URL: "https://codeberg.org/a/b/pulls/42#issuecomment-100"})
+
+	bs, err := f.MarshalChangeMetadata(md)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"pr": {"number": 42},
+		"comment": {"id": 100, "url": "https://codeberg.org/a/b/pulls/42#issuecomment-100"}
+	}`, string(bs))
+
+	got, err := f.UnmarshalChangeMetadata(bs)
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+	assert.Equal(t, "gitea", got.ForgeID())
+	assert.Equal(t, &PR{Number: 42}, got.ChangeID())
+	assert.Nil(t, got.CustomCommentID())
+}
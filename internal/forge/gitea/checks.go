@@ -0,0 +1,39 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeChecksState reports the combined state of the commit statuses
+// reported against the head commit of a PR.
+func (r *Repository) ChangeChecksState(ctx context.Context, id forge.ChangeID) (forge.ChecksState, error) {
+	pr, err := r.pullRequest(ctx, mustPR(id))
+	if err != nil {
+		return forge.ChecksNone, err
+	}
+
+	var res struct {
+		State string `json:"state"`
+	}
+	if err := r.client.Get(ctx, r.apiPath("commits", pr.Head.SHA, "status"), nil, &res); err != nil {
+		return forge.ChecksNone, fmt.Errorf("get combined status: %w", err)
+	}
+
+	return forgeChecksState(res.State), nil
+}
+
+func forgeChecksState(s string) forge.ChecksState {
+	switch s {
+	case "success", "warning":
+		return forge.ChecksPassing
+	case "failure", "error":
+		return forge.ChecksFailing
+	case "pending":
+		return forge.ChecksPending
+	default:
+		return forge.ChecksNone
+	}
+}
@@ -0,0 +1,18 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// CloseChange closes an open PR without merging it.
+func (r *Repository) CloseChange(ctx context.Context, fid forge.ChangeID) error {
+	input := editPullRequestRequest{State: "closed"}
+	if err := r.client.Patch(ctx, r.pullPath(mustPR(fid)), input, nil); err != nil {
+		return fmt.Errorf("close pull request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// PRComment is a ChangeCommentID for a Gitea PR comment.
+type PRComment struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url,omitempty"`
+}
+
+var _ forge.ChangeCommentID = (*PRComment)(nil)
+
+func mustPRComment(id forge.ChangeCommentID) *PRComment {
+	if id == nil {
+		return nil
+	}
+
+	prc, ok := id.(*PRComment)
+	if !ok {
+		panic(fmt.Sprintf("unexpected PR comment type: %T", id))
+	}
+	return prc
+}
+
+func (c *PRComment) String() string {
+	return c.URL
+}
+
+type commentRequest struct {
+	Body string `json:"body"`
+}
+
+type commentResponse struct {
+	ID      int64  `json:"id"`
+	HTMLURL string `json:"html_url"`
+	Body    string `json:"body"`
+}
+
+func (r *Repository) commentPath(id *PRComment) string {
+	return r.apiPath("issues", "comments", strconv.FormatInt(id.ID, 10))
+}
+
+// PostChangeComment posts a new comment on a PR.
+func (r *Repository) PostChangeComment(
+	ctx context.Context,
+	id forge.ChangeID,
+	markdown string,
+) (forge.ChangeCommentID, error) {
+	var res commentResponse
+	if err := r.client.Post(ctx, r.issuePath(mustPR(id), "comments"), commentRequest{Body: markdown}, &res); err != nil {
+		return nil, fmt.Errorf("post comment: %w", err)
+	}
+
+	r.log.Debug("Posted comment", "url", res.HTMLURL)
+	return &PRComment{
+		ID:  res.ID,
+		URL: res.HTMLURL,
+	}, nil
+}
+
+// UpdateChangeComment updates the contents of an existing comment on a PR.
+func (r *Repository) UpdateChangeComment(
+	ctx context.Context,
+	id forge.ChangeCommentID,
+	markdown string,
+) error {
+	cid := mustPRComment(id)
+	if err := r.client.Patch(ctx, r.commentPath(cid), commentRequest{Body: markdown}, nil); err != nil {
+		return fmt.Errorf("update comment: %w", err)
+	}
+
+	r.log.Debug("Updated comment", "url", cid.URL)
+	return nil
+}
+
+// ChangeComment reports the contents of an existing comment on a PR.
+func (r *Repository) ChangeComment(
+	ctx context.Context,
+	id forge.ChangeCommentID,
+) (string, error) {
+	var res commentResponse
+	if err := r.client.Get(ctx, r.commentPath(mustPRComment(id)), nil, &res); err != nil {
+		return "", fmt.Errorf("get comment: %w", err)
+	}
+
+	return res.Body, nil
+}
@@ -0,0 +1,41 @@
+package gitea
+
+import "strings"
+
+// _draftPrefix is the prefix added to the titles of draft pull requests.
+const _draftPrefix = "WIP: "
+
+// _draftPrefixes are the title prefixes that Gitea recognizes
+// as marking a pull request as a draft (work in progress)
+// in its default configuration.
+// These are matched case-insensitively.
+var _draftPrefixes = []string{
+	"wip:",
+	"[wip]",
+}
+
+// trimDraftPrefix removes the prefix marking a pull request as a draft
+// from the given title, if it has one.
+// It reports whether a prefix was removed.
+func trimDraftPrefix(title string) (string, bool) {
+	lower := strings.ToLower(title)
+	for _, prefix := range _draftPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(title[len(prefix):]), true
+		}
+	}
+	return title, false
+}
+
+// draftTitle returns the title to use for a pull request
+// with the given draft status.
+//
+// Gitea has no separate draft flag:
+// pull requests are drafts if their title has a WIP prefix.
+func draftTitle(title string, draft bool) string {
+	title, _ = trimDraftPrefix(title)
+	if draft {
+		return _draftPrefix + title
+	}
+	return title
+}
@@ -0,0 +1,37 @@
+package gitea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimDraftPrefix(t *testing.T) {
+	tests := []struct {
+		give string
+
+		want      string
+		wantDraft bool
+	}{
+		{give: "Add feature", want: "Add feature"},
+		{give: "WIP: Add feature", want: "Add feature", wantDraft: true},
+		{give: "wip:Add feature", want: "Add feature", wantDraft: true},
+		{give: "[WIP] Add feature", want: "Add feature", wantDraft: true},
+		{give: "Wipe: Add feature", want: "Wipe: Add feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, draft := trimDraftPrefix(tt.give)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantDraft, draft)
+		})
+	}
+}
+
+func TestDraftTitle(t *testing.T) {
+	assert.Equal(t, "WIP: Add feature", draftTitle("Add feature", true))
+	assert.Equal(t, "WIP: Add feature", draftTitle("[WIP] Add feature", true))
+	assert.Equal(t, "Add feature", draftTitle("WIP: Add feature", false))
+	assert.Equal(t, "Add feature", draftTitle("Add feature", false))
+}
@@ -0,0 +1,120 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type editPullRequestRequest struct {
+	Title     *string   `json:"title,omitempty"`
+	Body      *string   `json:"body,omitempty"`
+	Base      *string   `json:"base,omitempty"`
+	Milestone *int64    `json:"milestone,omitempty"`
+	Assignees *[]string `json:"assignees,omitempty"`
+	State     string    `json:"state,omitempty"`
+}
+
+// EditChange edits an existing pull request.
+func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts forge.EditChangeOptions) error {
+	if opts.Base == "" && opts.Draft == nil &&
+		opts.Title == nil && opts.Body == nil &&
+		len(opts.AddLabels) == 0 && len(opts.RemoveLabels) == 0 &&
+		len(opts.AddReviewers) == 0 && opts.Milestone == "" &&
+		len(opts.AddAssignees) == 0 {
+		return nil // nothing to do
+	}
+
+	pr := mustPR(fid)
+	input := editPullRequestRequest{Body: opts.Body}
+	if opts.Base != "" {
+		input.Base = &opts.Base
+	}
+
+	// The draft status is part of the title,
+	// so changing either requires the current title.
+	// Assignees are only added if there aren't any yet.
+	if opts.Draft != nil || opts.Title != nil || len(opts.AddAssignees) > 0 {
+		current, err := r.pullRequest(ctx, pr)
+		if err != nil {
+			return err
+		}
+
+		if opts.Draft != nil || opts.Title != nil {
+			title, draft := trimDraftPrefix(current.Title)
+			if opts.Title != nil {
+				title = *opts.Title
+			}
+			if opts.Draft != nil {
+				draft = *opts.Draft
+			}
+			if title = draftTitle(title, draft); title != current.Title {
+				input.Title = &title
+			}
+		}
+
+		if len(opts.AddAssignees) > 0 && len(current.Assignees) == 0 {
+			input.Assignees = &opts.AddAssignees
+		}
+	}
+
+	if opts.Milestone != "" {
+		m, err := r.findMilestone(ctx, opts.Milestone)
+		if err != nil {
+			return fmt.Errorf("set milestone: %w", err)
+		}
+		input.Milestone = &m.ID
+	}
+
+	if input != (editPullRequestRequest{}) {
+		if err := r.client.Patch(ctx, r.pullPath(pr), input, nil); err != nil {
+			if errors.Is(err, errConflict) {
+				return fmt.Errorf("edit pull request: %w: %w", forge.ErrChangeConflict, err)
+			}
+			return fmt.Errorf("edit pull request: %w", err)
+		}
+	}
+
+	if len(opts.AddLabels) > 0 {
+		if err := r.addLabels(ctx, pr, opts.AddLabels); err != nil {
+			return fmt.Errorf("add labels: %w", err)
+		}
+	}
+
+	if len(opts.RemoveLabels) > 0 {
+		if err := r.removeLabels(ctx, pr, opts.RemoveLabels); err != nil {
+			return fmt.Errorf("remove labels: %w", err)
+		}
+	}
+
+	if len(opts.AddReviewers) > 0 {
+		if err := r.addReviewers(ctx, pr, opts.AddReviewers); err != nil {
+			return fmt.Errorf("request reviews: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// addReviewers requests reviews on a pull request.
+// Reviewers in the form "org/team" are teams,
+// and all others are users.
+func (r *Repository) addReviewers(ctx context.Context, pr *PR, names []string) error {
+	req := struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{}
+	for _, name := range names {
+		// Teams are identified by name within the repository's organization.
+		if _, team, ok := strings.Cut(name, "/"); ok {
+			req.TeamReviewers = append(req.TeamReviewers, team)
+		} else {
+			req.Reviewers = append(req.Reviewers, name)
+		}
+	}
+
+	return r.client.Post(ctx, r.pullPath(pr, "requested_reviewers"), req, nil)
+}
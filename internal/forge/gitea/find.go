@@ -0,0 +1,168 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+)
+
+// pullRequest is a pull request as reported by the Gitea API.
+// Only the fields we need are included.
+type pullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+
+	Head struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+func (pr *pullRequest) changeState() forge.ChangeState {
+	switch {
+	case pr.Merged:
+		return forge.ChangeMerged
+	case pr.State == "open":
+		return forge.ChangeOpen
+	case pr.State == "closed":
+		return forge.ChangeClosed
+	default:
+		return 0
+	}
+}
+
+func (pr *pullRequest) toFindChangeItem() *forge.FindChangeItem {
+	// The draft status is part of the title on Gitea.
+	// Report the title without it so that it can be compared
+	// with titles generated from commit messages.
+	subject, draft := trimDraftPrefix(pr.Title)
+
+	var labels []string
+	for _, label := range pr.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	var assignees []string
+	for _, user := range pr.Assignees {
+		assignees = append(assignees, user.Login)
+	}
+
+	var milestone string
+	if pr.Milestone != nil {
+		milestone = pr.Milestone.Title
+	}
+
+	return &forge.FindChangeItem{
+		ID:       &PR{Number: pr.Number},
+		URL:      pr.HTMLURL,
+		State:    pr.changeState(),
+		Subject:  subject,
+		Body:     pr.Body,
+		BaseName: pr.Base.Ref,
+		HeadHash: git.Hash(pr.Head.SHA),
+		Draft:    draft,
+		Labels:   labels,
+
+		Assignees: assignees,
+		Milestone: milestone,
+	}
+}
+
+// Gitea can't filter pull requests by their head branch,
+// so FindChangesByBranch pages through recently updated pull requests.
+// These bound how far back it looks.
+const (
+	_findPageSize = 50
+	_findMaxPages = 10
+)
+
+// FindChangesByBranch searches for changes with the given branch name.
+// Only recent changes are returned, limited by the given limit.
+func (r *Repository) FindChangesByBranch(ctx context.Context, branch string, opts forge.FindChangesOptions) ([]*forge.FindChangeItem, error) {
+	if opts.Limit == 0 {
+		opts.Limit = 10
+	}
+
+	state := "all"
+	if opts.State == forge.ChangeOpen {
+		state = "open"
+	}
+
+	var changes []*forge.FindChangeItem
+	for page := 1; page <= _findMaxPages && len(changes) < opts.Limit; page++ {
+		query := url.Values{
+			"state": {state},
+			"sort":  {"recentupdate"},
+			"limit": {strconv.Itoa(_findPageSize)},
+			"page":  {strconv.Itoa(page)},
+		}
+
+		var res []*pullRequest
+		if err := r.client.Get(ctx, r.apiPath("pulls"), query, &res); err != nil {
+			return nil, fmt.Errorf("find changes by branch: %w", err)
+		}
+
+		for _, pr := range res {
+			if pr.Head.Ref != branch {
+				continue
+			}
+			if opts.State != 0 && pr.changeState() != opts.State {
+				continue
+			}
+
+			changes = append(changes, pr.toFindChangeItem())
+			if len(changes) == opts.Limit {
+				break
+			}
+		}
+
+		if len(res) < _findPageSize {
+			break // last page
+		}
+	}
+
+	return changes, nil
+}
+
+// FindChangeByID searches for a change with the given ID.
+func (r *Repository) FindChangeByID(ctx context.Context, id forge.ChangeID) (*forge.FindChangeItem, error) {
+	pr, err := r.pullRequest(ctx, mustPR(id))
+	if err != nil {
+		return nil, fmt.Errorf("find change by ID: %w", err)
+	}
+
+	return pr.toFindChangeItem(), nil
+}
+
+// pullRequest fetches information about a pull request.
+func (r *Repository) pullRequest(ctx context.Context, id *PR) (*pullRequest, error) {
+	var pr pullRequest
+	if err := r.client.Get(ctx, r.pullPath(id), nil, &pr); err != nil {
+		return nil, fmt.Errorf("get pull request %v: %w", id, err)
+	}
+	return &pr, nil
+}
@@ -0,0 +1,168 @@
+// Package gitea provides a wrapper around the APIs of Gitea and Forgejo
+// in a manner compliant with the [forge.Forge] interface.
+package gitea
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// Options defines command line options for the Gitea Forge.
+// These are all hidden in the CLI,
+// and are expected to be set only via environment variables.
+type Options struct {
+	// URL is the URL for the Gitea instance.
+	// Gitea is usually self-hosted, so this will often be set.
+	URL string `name:"gitea-url" hidden:"" env:"GITEA_URL" help:"Base URL for Gitea web requests"`
+
+	// APIURL is the URL for the Gitea API.
+	// Defaults to the /api/v1 path under URL.
+	APIURL string `name:"gitea-api-url" hidden:"" env:"GITEA_API_URL" help:"Base URL for Gitea API requests"`
+
+	// Token is a fixed token used to authenticate with Gitea.
+	// This may be used to skip the login flow.
+	Token string `name:"gitea-token" hidden:"" env:"GITEA_TOKEN" help:"Gitea API token"`
+}
+
+// Forge builds a Gitea Forge.
+// This also works with Forgejo, which provides a compatible API.
+type Forge struct {
+	Options Options
+
+	// Log specifies the logger to use.
+	Log *log.Logger
+}
+
+var _ forge.Forge = (*Forge)(nil)
+
+// URL returns the base URL configured for the Gitea Forge
+// or the default URL if none is set.
+//
+// The default is Codeberg, the largest public Forgejo instance.
+func (f *Forge) URL() string {
+	return cmp.Or(f.Options.URL, "https://codeberg.org")
+}
+
+// APIURL returns the base API URL configured for the Gitea Forge
+// or the default URL if none is set.
+func (f *Forge) APIURL() string {
+	if f.Options.APIURL != "" {
+		return f.Options.APIURL
+	}
+	return strings.TrimSuffix(f.URL(), "/") + "/api/v1"
+}
+
+// ID reports a unique key for this forge.
+func (*Forge) ID() string { return "gitea" }
+
+// CLIPlugin returns the CLI plugin for the Gitea Forge.
+func (f *Forge) CLIPlugin() any { return &f.Options }
+
+// MatchURL reports whether the given URL is hosted on the Gitea instance.
+func (f *Forge) MatchURL(remoteURL string) bool {
+	_, _, err := extractRepoInfo(f.URL(), remoteURL)
+	return err == nil
+}
+
+// OpenURL opens a Gitea repository from a remote URL.
+// Returns [forge.ErrUnsupportedURL] if the URL is not a valid Gitea URL.
+func (f *Forge) OpenURL(ctx context.Context, tok forge.AuthenticationToken, remoteURL string) (forge.Repository, error) {
+	if f.Log == nil {
+		f.Log = log.New(io.Discard)
+	}
+
+	owner, repo, err := extractRepoInfo(f.URL(), remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", forge.ErrUnsupportedURL, err)
+	}
+
+	client, err := newClient(f.APIURL(), tok.(*AuthenticationToken).AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("create Gitea client: %w", err)
+	}
+
+	return newRepository(ctx, f, owner, repo, f.Log, client)
+}
+
+func extractRepoInfo(giteaURL, remoteURL string) (owner, repo string, err error) {
+	baseURL, err := url.Parse(giteaURL)
+	if err != nil {
+		return "", "", fmt.Errorf("bad base URL: %w", err)
+	}
+
+	// We recognize the following Gitea remote URL formats:
+	//
+	//	http(s)://gitea.example.com/OWNER/REPO.git
+	//	git@gitea.example.com:OWNER/REPO.git
+	//
+	// We can parse these all with url.Parse
+	// if we normalize the latter to:
+	//
+	//	ssh://git@gitea.example.com/OWNER/REPO.git
+	if !hasGitProtocol(remoteURL) && strings.Contains(remoteURL, ":") {
+		// $user@$host:$path => ssh://$user@$host/$path
+		remoteURL = "ssh://" + strings.Replace(remoteURL, ":", "/", 1)
+	}
+
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", "", fmt.Errorf("parse remote URL: %w", err)
+	}
+
+	if !forge.MatchHost(u.Host, baseURL.Host) {
+		return "", "", fmt.Errorf("%v is not a Gitea URL: expected host %q", u, baseURL.Host)
+	}
+
+	s := u.Path                       // /OWNER/REPO.git/
+	s = strings.TrimPrefix(s, "/")    // OWNER/REPO.git/
+	s = strings.TrimSuffix(s, "/")    // OWNER/REPO.git
+	s = strings.TrimSuffix(s, ".git") // OWNER/REPO
+
+	// Instances hosted under a path prefix
+	// (e.g. https://example.com/gitea)
+	// include that prefix in HTTP remote URLs.
+	if prefix := strings.Trim(baseURL.Path, "/"); prefix != "" && u.Scheme != "ssh" {
+		s = strings.TrimPrefix(s, prefix+"/")
+	}
+
+	owner, repo, ok := strings.Cut(s, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("path %q does not contain a Gitea repository", s)
+	}
+
+	return owner, repo, nil
+}
+
+// _gitProtocols is a list of known git protocols
+// including the :// suffix.
+var _gitProtocols = []string{
+	"ssh",
+	"git",
+	"git+ssh",
+	"git+https",
+	"git+http",
+	"https",
+	"http",
+}
+
+func init() {
+	for i, proto := range _gitProtocols {
+		_gitProtocols[i] = proto + "://"
+	}
+}
+
+func hasGitProtocol(url string) bool {
+	for _, proto := range _gitProtocols {
+		if strings.HasPrefix(url, proto) {
+			return true
+		}
+	}
+	return false
+}
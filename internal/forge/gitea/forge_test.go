@@ -0,0 +1,136 @@
+package gitea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractRepoInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		give     string
+		giteaURL string
+
+		wantOwner string
+		wantRepo  string
+	}{
+		{
+			name:      "https",
+			give:      "https://codeberg.org/example/repo",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "ssh",
+			give:      "git@codeberg.org:example/repo",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "ssh with git protocol",
+			give:      "ssh://git@codeberg.org/example/repo",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "https/.git/trailing slash",
+			give:      "https://codeberg.org/example/repo.git/",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "https/custom URL",
+			give:      "https://git.example.com/example/repo",
+			giteaURL:  "https://git.example.com",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "https/custom URL with path",
+			give:      "https://example.com/gitea/example/repo.git",
+			giteaURL:  "https://example.com/gitea",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "ssh/custom URL with path",
+			give:      "git@example.com:example/repo.git",
+			giteaURL:  "https://example.com/gitea",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Forge{Options: Options{URL: tt.giteaURL}}
+			owner, repo, err := extractRepoInfo(f.URL(), tt.give)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOwner, owner)
+			assert.Equal(t, tt.wantRepo, repo)
+		})
+	}
+}
+
+func TestExtractRepoInfoErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		give     string
+		giteaURL string
+
+		wantErr string
+	}{
+		{
+			name:    "not a Gitea URL",
+			give:    "https://github.com/example/repo",
+			wantErr: "not a Gitea URL",
+		},
+		{
+			name:    "no repository",
+			give:    "https://codeberg.org/example",
+			wantErr: "does not contain a Gitea repository",
+		},
+		{
+			name:    "nested path",
+			give:    "https://codeberg.org/example/sub/repo",
+			wantErr: "does not contain a Gitea repository",
+		},
+		{
+			name:     "bad base URL",
+			give:     "https://codeberg.org/example/repo",
+			giteaURL: "://",
+			wantErr:  "bad base URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Forge{Options: Options{URL: tt.giteaURL}}
+			_, _, err := extractRepoInfo(f.URL(), tt.give)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestForgeAPIURL(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		var f Forge
+		assert.Equal(t, "https://codeberg.org/api/v1", f.APIURL())
+	})
+
+	t.Run("custom URL", func(t *testing.T) {
+		f := Forge{Options: Options{URL: "https://example.com/gitea/"}}
+		assert.Equal(t, "https://example.com/gitea/api/v1", f.APIURL())
+	})
+
+	t.Run("custom API URL", func(t *testing.T) {
+		f := Forge{Options: Options{
+			URL:    "https://example.com",
+			APIURL: "https://api.example.com",
+		}}
+		assert.Equal(t, "https://api.example.com", f.APIURL())
+	})
+}
@@ -0,0 +1,111 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type label struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// labelIDs resolves the IDs of the labels with the given names.
+//
+// Labels that don't exist in the repository are reported
+// in the missing slice.
+func (r *Repository) labelIDs(ctx context.Context, names []string) (ids []int64, missing []string, err error) {
+	// Gitea can't look up labels by name,
+	// so list all of them.
+	byName := make(map[string]int64)
+	for page := 1; ; page++ {
+		var res []*label
+		query := url.Values{
+			"limit": {"50"},
+			"page":  {strconv.Itoa(page)},
+		}
+		if err := r.client.Get(ctx, r.apiPath("labels"), query, &res); err != nil {
+			return nil, nil, fmt.Errorf("list labels: %w", err)
+		}
+		for _, l := range res {
+			byName[l.Name] = l.ID
+		}
+		if len(res) < 50 {
+			break
+		}
+	}
+
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, missing, nil
+}
+
+// _newLabelColor is the color of labels created by resolveLabels.
+const _newLabelColor = "#ededed"
+
+// resolveLabels resolves the IDs of the labels with the given names.
+// Labels that don't exist in the repository are created.
+// If a label can't be created, it's skipped with a warning.
+func (r *Repository) resolveLabels(ctx context.Context, names []string) ([]int64, error) {
+	ids, missing, err := r.labelIDs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range missing {
+		var res label
+		req := struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		}{Name: name, Color: _newLabelColor}
+		if err := r.client.Post(ctx, r.apiPath("labels"), req, &res); err != nil {
+			r.log.Warn("Label does not exist and could not be created. Skipping.", "label", name, "error", err)
+			continue
+		}
+		ids = append(ids, res.ID)
+	}
+
+	return ids, nil
+}
+
+// addLabels adds the labels with the given names to a pull request.
+func (r *Repository) addLabels(ctx context.Context, pr *PR, names []string) error {
+	ids, err := r.resolveLabels(ctx, names)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	req := struct {
+		Labels []int64 `json:"labels"`
+	}{Labels: ids}
+	return r.client.Post(ctx, r.issuePath(pr, "labels"), req, nil)
+}
+
+// removeLabels removes the labels with the given names from a pull request.
+func (r *Repository) removeLabels(ctx context.Context, pr *PR, names []string) error {
+	// Labels that don't exist can't be on the change,
+	// so there's nothing to remove.
+	ids, _, err := r.labelIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := r.client.Delete(ctx, r.issuePath(pr, "labels", strconv.FormatInt(id, 10))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type mergePullRequestRequest struct {
+	Do                     string `json:"Do"`
+	MergeWhenChecksSucceed bool   `json:"merge_when_checks_succeed"`
+}
+
+// EnqueueMerge schedules a PR to be merged
+// once its checks succeed.
+//
+// If the PR's checks have already succeeded,
+// it's merged immediately.
+func (r *Repository) EnqueueMerge(ctx context.Context, fid forge.ChangeID, method forge.MergeMethod) (forge.EnqueueMergeResult, error) {
+	input := mergePullRequestRequest{
+		Do:                     mergeStyle(method),
+		MergeWhenChecksSucceed: true,
+	}
+	if err := r.client.Post(ctx, r.pullPath(mustPR(fid), "merge"), input, nil); err != nil {
+		return forge.EnqueueMergeResult{}, fmt.Errorf("schedule merge: %w", err)
+	}
+
+	return forge.EnqueueMergeResult{AutoMerge: true}, nil
+}
+
+// mergeStyle returns the Gitea merge style
+// for the given forge merge method.
+func mergeStyle(m forge.MergeMethod) string {
+	switch m {
+	case forge.MergeMethodSquash:
+		return "squash"
+	case forge.MergeMethodRebase:
+		return "rebase"
+	default:
+		// Gitea requires a merge style.
+		return "merge"
+	}
+}
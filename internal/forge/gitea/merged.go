@@ -0,0 +1,17 @@
+package gitea
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeIsMerged reports whether a change has been merged.
+func (r *Repository) ChangeIsMerged(ctx context.Context, id forge.ChangeID) (bool, error) {
+	pr, err := r.pullRequest(ctx, mustPR(id))
+	if err != nil {
+		return false, err
+	}
+
+	return pr.Merged, nil
+}
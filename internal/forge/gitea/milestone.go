@@ -0,0 +1,41 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type milestone struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// FindMilestone looks up a milestone in the repository
+// by its title or ID.
+func (r *Repository) FindMilestone(ctx context.Context, name string) (*forge.Milestone, error) {
+	m, err := r.findMilestone(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &forge.Milestone{
+		Title:  m.Title,
+		Number: int(m.ID),
+	}, nil
+}
+
+func (r *Repository) findMilestone(ctx context.Context, name string) (*milestone, error) {
+	// Gitea resolves milestones by ID or title at the same endpoint.
+	var m milestone
+	if err := r.client.Get(ctx, r.apiPath("milestones", name), nil, &m); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("%q: %w", name, forge.ErrMilestoneNotFound)
+		}
+		return nil, fmt.Errorf("get milestone: %w", err)
+	}
+
+	return &m, nil
+}
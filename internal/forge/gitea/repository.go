@@ -0,0 +1,71 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// Repository is a Gitea repository.
+type Repository struct {
+	owner, repo string
+	log         *log.Logger
+	client      *client
+	forge       *Forge
+}
+
+var _ forge.Repository = (*Repository)(nil)
+
+func newRepository(
+	ctx context.Context,
+	forge *Forge,
+	owner, repo string,
+	log *log.Logger,
+	client *client,
+) (*Repository, error) {
+	// Verify that the repository exists and is accessible
+	// so that problems are reported early.
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	if err := client.Get(ctx, "repos/"+owner+"/"+repo, nil, &res); err != nil {
+		return nil, fmt.Errorf("get repository: %w", err)
+	}
+
+	return &Repository{
+		owner:  owner,
+		repo:   repo,
+		log:    log,
+		client: client,
+		forge:  forge,
+	}, nil
+}
+
+// Forge returns the forge this repository belongs to.
+func (r *Repository) Forge() forge.Forge { return r.forge }
+
+// apiPath returns the path to an API endpoint
+// scoped to this repository.
+func (r *Repository) apiPath(elems ...string) string {
+	p := "repos/" + r.owner + "/" + r.repo
+	for _, e := range elems {
+		p += "/" + e
+	}
+	return p
+}
+
+// pullPath returns the path to an API endpoint
+// scoped to a pull request in this repository.
+func (r *Repository) pullPath(pr *PR, elems ...string) string {
+	return r.apiPath(append([]string{"pulls", strconv.Itoa(pr.Number)}, elems...)...)
+}
+
+// issuePath returns the path to an API endpoint
+// scoped to the issue backing a pull request.
+// Comments and labels on pull requests are managed through their issue.
+func (r *Repository) issuePath(pr *PR, elems ...string) string {
+	return r.apiPath(append([]string{"issues", strconv.Itoa(pr.Number)}, elems...)...)
+}
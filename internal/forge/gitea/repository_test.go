@@ -0,0 +1,284 @@
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// fakeGitea is a fake Gitea API server that serves canned responses
+// and records the requests made to it.
+type fakeGitea struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	responses map[string]string // "METHOD path" -> JSON response
+	requests  map[string]string // "METHOD path" -> JSON request body
+	queries   map[string]string // "METHOD path" -> raw query
+	failures  map[string]int    // "METHOD path" -> HTTP status code
+}
+
+func newFakeGitea(t *testing.T) (*fakeGitea, *Forge) {
+	fake := &fakeGitea{
+		t:         t,
+		responses: make(map[string]string),
+		requests:  make(map[string]string),
+		queries:   make(map[string]string),
+		failures:  make(map[string]int),
+	}
+	fake.respond("GET repos/example/repo", `{"id": 7}`)
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	return fake, &Forge{
+		Options: Options{
+			URL:    "https://gitea.example.com",
+			APIURL: srv.URL + "/api/v1",
+		},
+		Log: log.New(io.Discard),
+	}
+}
+
+func (f *fakeGitea) respond(key, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[key] = body
+}
+
+// fail makes requests to key fail with the given HTTP status code.
+func (f *fakeGitea) fail(key string, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[key] = status
+}
+
+func (f *fakeGitea) request(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[key]
+}
+
+func (f *fakeGitea) query(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries[key]
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(f.t, "token token", r.Header.Get("Authorization"))
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1/")
+	key := r.Method + " " + path
+
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(f.t, err)
+
+	f.mu.Lock()
+	f.requests[key] = string(body)
+	f.queries[key] = r.URL.RawQuery
+	res, ok := f.responses[key]
+	status, failed := f.failures[key]
+	f.mu.Unlock()
+
+	if failed {
+		http.Error(w, `{"message": "failed"}`, status)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	_, _ = io.WriteString(w, res)
+}
+
+func openTestRepository(t *testing.T, f *Forge) *Repository {
+	repo, err := f.OpenURL(context.Background(),
+		&AuthenticationToken{AccessToken: "token"},
+		"git@gitea.example.com:example/repo.git")
+	require.NoError(t, err)
+	return repo.(*Repository)
+}
+
+func TestRepository_SubmitChange(t *testing.T) {
+	fake, f := newFakeGitea(t)
+	fake.respond("GET repos/example/repo/labels", `[{"id": 1, "name": "a"}]`)
+	fake.respond("POST repos/example/repo/labels", `{"id": 2, "name": "b"}`)
+	fake.respond("GET repos/example/repo/milestones/v1", `{"id": 5, "title": "v1"}`)
+	fake.respond("POST repos/example/repo/pulls", `{
+		"number": 3,
+		"html_url": "https://gitea.example.com/example/repo/pulls/3"
+	}`)
+	repo := openTestRepository(t, f)
+
+	res, err := repo.SubmitChange(context.Background(), forge.SubmitChangeRequest{
+		Subject:   "Add feature",
+		Body:      "Adds a feature",
+		Base:      "main",
+		Head:      "feature",
+		Draft:     true,
+		Labels:    []string{"a", "b"},
+		Milestone: "v1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &PR{Number: 3}, res.ID)
+	assert.Equal(t, "https://gitea.example.com/example/repo/pulls/3", res.URL)
+
+	assert.JSONEq(t, `{"name": "b", "color": "#ededed"}`,
+		fake.request("POST repos/example/repo/labels"))
+	assert.JSONEq(t, `{
+		"head": "feature",
+		"base": "main",
+		"title": "WIP: Add feature",
+		"body": "Adds a feature",
+		"labels": [1, 2],
+		"milestone": 5
+	}`, fake.request("POST repos/example/repo/pulls"))
+}
+
+func TestRepository_FindChangesByBranch(t *testing.T) {
+	fake, f := newFakeGitea(t)
+	fake.respond("GET repos/example/repo/pulls", `[
+		{
+			"number": 4,
+			"title": "Unrelated",
+			"state": "open",
+			"head": {"ref": "other", "sha": "def456"},
+			"base": {"ref": "main"}
+		},
+		{
+			"number": 3,
+			"html_url": "https://gitea.example.com/example/repo/pulls/3",
+			"title": "WIP: Add feature",
+			"state": "open",
+			"head": {"ref": "feature", "sha": "abc123"},
+			"base": {"ref": "main"}
+		}
+	]`)
+	repo := openTestRepository(t, f)
+
+	changes, err := repo.FindChangesByBranch(context.Background(), "feature", forge.FindChangesOptions{
+		State: forge.ChangeOpen,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*forge.FindChangeItem{
+		{
+			ID:       &PR{Number: 3},
+			URL:      "https://gitea.example.com/example/repo/pulls/3",
+			State:    forge.ChangeOpen,
+			Subject:  "Add feature",
+			HeadHash: "abc123",
+			BaseName: "main",
+			Draft:    true,
+		},
+	}, changes)
+
+	assert.Equal(t,
+		"limit=50&page=1&sort=recentupdate&state=open",
+		fake.query("GET repos/example/repo/pulls"))
+}
+
+func TestRepository_EditChange(t *testing.T) {
+	fake, f := newFakeGitea(t)
+	fake.respond("GET repos/example/repo/pulls/3", `{
+		"number": 3,
+		"title": "WIP: Add feature"
+	}`)
+	fake.respond("PATCH repos/example/repo/pulls/3", `{}`)
+	fake.respond("GET repos/example/repo/labels", `[{"id": 1, "name": "ready"}]`)
+	fake.respond("POST repos/example/repo/issues/3/labels", `[]`)
+	fake.respond("POST repos/example/repo/pulls/3/requested_reviewers", `[]`)
+	repo := openTestRepository(t, f)
+
+	draft := false
+	require.NoError(t, repo.EditChange(context.Background(), &PR{Number: 3}, forge.EditChangeOptions{
+		Base:         "develop",
+		Draft:        &draft,
+		AddLabels:    []string{"ready"},
+		AddReviewers: []string{"bob", "org/team"},
+	}))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(fake.request("PATCH repos/example/repo/pulls/3")), &got))
+	assert.Equal(t, map[string]any{
+		"base":  "develop",
+		"title": "Add feature",
+	}, got)
+	assert.JSONEq(t, `{"labels": [1]}`,
+		fake.request("POST repos/example/repo/issues/3/labels"))
+	assert.JSONEq(t, `{"reviewers": ["bob"], "team_reviewers": ["team"]}`,
+		fake.request("POST repos/example/repo/pulls/3/requested_reviewers"))
+}
+
+func TestRepository_ChangeChecksState(t *testing.T) {
+	fake, f := newFakeGitea(t)
+	fake.respond("GET repos/example/repo/pulls/3", `{
+		"number": 3,
+		"head": {"ref": "feature", "sha": "abc123"}
+	}`)
+	fake.respond("GET repos/example/repo/commits/abc123/status", `{"state": "failure"}`)
+	repo := openTestRepository(t, f)
+
+	state, err := repo.ChangeChecksState(context.Background(), &PR{Number: 3})
+	require.NoError(t, err)
+	assert.Equal(t, forge.ChecksFailing, state)
+}
+
+func TestRepository_ListChangeTemplates(t *testing.T) {
+	fake, f := newFakeGitea(t)
+	body := base64.StdEncoding.EncodeToString([]byte("## Summary\n"))
+	fake.respond("GET repos/example/repo/contents/.gitea/PULL_REQUEST_TEMPLATE.md",
+		`{"content": "`+body+`", "encoding": "base64"}`)
+	repo := openTestRepository(t, f)
+
+	templates, err := repo.ListChangeTemplates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*forge.ChangeTemplate{
+		{Filename: "PULL_REQUEST_TEMPLATE.md", Body: "## Summary\n"},
+	}, templates)
+}
+
+func TestRepository_FindMilestone_notFound(t *testing.T) {
+	_, f := newFakeGitea(t)
+	repo := openTestRepository(t, f)
+
+	_, err := repo.FindMilestone(context.Background(), "v2")
+	assert.ErrorIs(t, err, forge.ErrMilestoneNotFound)
+}
+
+func TestRepository_EditChange_conflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		conflict bool
+	}{
+		{name: "Conflict", status: http.StatusConflict, conflict: true},
+		{name: "ServerError", status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, f := newFakeGitea(t)
+			fake.fail("PATCH repos/example/repo/pulls/3", tt.status)
+			repo := openTestRepository(t, f)
+
+			err := repo.EditChange(context.Background(), &PR{Number: 3}, forge.EditChangeOptions{
+				Base: "develop",
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.conflict, errors.Is(err, forge.ErrChangeConflict), "error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type createPullRequestRequest struct {
+	Head      string   `json:"head"`
+	Base      string   `json:"base"`
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Labels    []int64  `json:"labels,omitempty"`
+	Milestone int64    `json:"milestone,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// SubmitChange creates a new pull request in a repository.
+func (r *Repository) SubmitChange(ctx context.Context, req forge.SubmitChangeRequest) (forge.SubmitChangeResult, error) {
+	input := createPullRequestRequest{
+		Head:  req.Head,
+		Base:  req.Base,
+		Title: draftTitle(req.Subject, req.Draft),
+		Body:  req.Body,
+
+		Assignees: req.Assignees,
+	}
+
+	// Failing to resolve labels or the milestone
+	// shouldn't prevent the pull request from being created,
+	// so these only log warnings.
+	if len(req.Labels) > 0 {
+		ids, err := r.resolveLabels(ctx, req.Labels)
+		if err != nil {
+			r.log.Warn("Could not add labels", "error", err)
+		}
+		input.Labels = ids
+	}
+	if req.Milestone != "" {
+		m, err := r.findMilestone(ctx, req.Milestone)
+		if err != nil {
+			r.log.Warn("Could not set milestone", "milestone", req.Milestone, "error", err)
+		} else {
+			input.Milestone = m.ID
+		}
+	}
+
+	var res pullRequest
+	if err := r.client.Post(ctx, r.apiPath("pulls"), input, &res); err != nil {
+		return forge.SubmitChangeResult{}, fmt.Errorf("create pull request: %w", err)
+	}
+
+	return forge.SubmitChangeResult{
+		ID:  &PR{Number: res.Number},
+		URL: res.HTMLURL,
+	}, nil
+}
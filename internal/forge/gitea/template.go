@@ -0,0 +1,67 @@
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// _changeTemplatePaths is the list of paths where Gitea
+// looks for pull request templates.
+//
+// Ref https://docs.gitea.com/usage/issue-pull-request-templates.
+var _changeTemplatePaths = []string{
+	"PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	".gitea/PULL_REQUEST_TEMPLATE.md",
+	".gitea/pull_request_template.md",
+	".forgejo/PULL_REQUEST_TEMPLATE.md",
+	".forgejo/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	".github/pull_request_template.md",
+}
+
+// ChangeTemplatePaths reports the allowed paths for possible PR templates.
+func (f *Forge) ChangeTemplatePaths() []string {
+	return _changeTemplatePaths
+}
+
+// ListChangeTemplates returns PR templates defined in the repository.
+func (r *Repository) ListChangeTemplates(ctx context.Context) ([]*forge.ChangeTemplate, error) {
+	var out []*forge.ChangeTemplate
+	for _, p := range _changeTemplatePaths {
+		var res struct {
+			Content  string `json:"content"`
+			Encoding string `json:"encoding"`
+		}
+		if err := r.client.Get(ctx, r.apiPath("contents", p), nil, &res); err != nil {
+			if errors.Is(err, errNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("get template %q: %w", p, err)
+		}
+
+		body := res.Content
+		if res.Encoding == "base64" {
+			bs, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				return nil, fmt.Errorf("decode template %q: %w", p, err)
+			}
+			body = string(bs)
+		}
+
+		if strings.TrimSpace(body) != "" {
+			out = append(out, &forge.ChangeTemplate{
+				Filename: path.Base(p),
+				Body:     body,
+			})
+		}
+	}
+
+	return out, nil
+}
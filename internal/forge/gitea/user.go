@@ -0,0 +1,27 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// CurrentUser reports the user authenticated against Gitea.
+func (r *Repository) CurrentUser(ctx context.Context) (forge.User, error) {
+	var u struct {
+		ID       int64  `json:"id"`
+		Login    string `json:"login"`
+		FullName string `json:"full_name"`
+	}
+	if err := r.client.Get(ctx, "user", nil, &u); err != nil {
+		return forge.User{}, fmt.Errorf("get current user: %w", err)
+	}
+
+	return forge.User{
+		Login: u.Login,
+		ID:    strconv.FormatInt(u.ID, 10),
+		Name:  u.FullName,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// addAssignees assigns the users with the given logins to a pull request.
+func (r *Repository) addAssignees(ctx context.Context, id githubv4.ID, names []string) error {
+	userIDs, teamIDs, err := r.reviewerIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+	if len(teamIDs) > 0 {
+		return fmt.Errorf("teams cannot be assigned: %v", names)
+	}
+
+	var m struct {
+		AddAssigneesToAssignable struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"addAssigneesToAssignable(input: $input)"`
+	}
+	input := githubv4.AddAssigneesToAssignableInput{
+		AssignableID: id,
+		AssigneeIDs:  userIDs,
+	}
+	return r.client.Mutate(ctx, &m, input, nil)
+}
+
+// hasAssignees reports whether a pull request is assigned to anyone.
+func (r *Repository) hasAssignees(ctx context.Context, id githubv4.ID) (bool, error) {
+	var q struct {
+		Node struct {
+			PullRequest struct {
+				Assignees struct {
+					TotalCount githubv4.Int `graphql:"totalCount"`
+				} `graphql:"assignees(first: 1)"`
+			} `graphql:"... on PullRequest"`
+		} `graphql:"node(id: $id)"`
+	}
+	if err := r.client.Query(ctx, &q, map[string]any{
+		"id": id,
+	}); err != nil {
+		return false, fmt.Errorf("query assignees: %w", err)
+	}
+
+	return q.Node.PullRequest.Assignees.TotalCount > 0, nil
+}
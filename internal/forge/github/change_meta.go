@@ -13,7 +13,8 @@ import (
 type PRMetadata struct {
 	PR *PR `json:"pr,omitempty"`
 
-	StackComment *PRComment `json:"comment,omitempty"`
+	StackComment  *PRComment `json:"comment,omitempty"`
+	CustomComment *PRComment `json:"customComment,omitempty"`
 }
 
 var _ forge.ChangeMetadata = (*PRMetadata)(nil)
@@ -45,6 +46,23 @@ func (m *PRMetadata) SetStackCommentID(id forge.ChangeCommentID) {
 	m.StackComment = mustPRComment(id)
 }
 
+// CustomCommentID reports the comment ID of the custom comment
+// posted on the pull request with 'submit --comment'.
+func (m *PRMetadata) CustomCommentID() forge.ChangeCommentID {
+	if m.CustomComment == nil {
+		return nil
+	}
+	return m.CustomComment
+}
+
+// SetCustomCommentID sets the comment ID of the custom comment
+// posted on the pull request.
+//
+// id may be nil.
+func (m *PRMetadata) SetCustomCommentID(id forge.ChangeCommentID) {
+	m.CustomComment = mustPRComment(id)
+}
+
 // NewChangeMetadata returns the metadata for a pull request.
 func (f *Repository) NewChangeMetadata(
 	ctx context.Context,
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeChecksState reports the combined state of the status checks
+// reported against the head commit of a PR.
+func (r *Repository) ChangeChecksState(ctx context.Context, id forge.ChangeID) (forge.ChecksState, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							StatusCheckRollup *struct {
+								State githubv4.StatusState `graphql:"state"`
+							} `graphql:"statusCheckRollup"`
+						} `graphql:"commit"`
+					} `graphql:"nodes"`
+				} `graphql:"commits(last: 1)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	err := r.client.Query(ctx, &q, map[string]any{
+		"owner":  githubv4.String(r.owner),
+		"repo":   githubv4.String(r.repo),
+		"number": githubv4.Int(mustPR(id).Number),
+	})
+	if err != nil {
+		return forge.ChecksNone, fmt.Errorf("query failed: %w", err)
+	}
+
+	commits := q.Repository.PullRequest.Commits.Nodes
+	if len(commits) == 0 || commits[0].Commit.StatusCheckRollup == nil {
+		return forge.ChecksNone, nil
+	}
+
+	return forgeChecksState(commits[0].Commit.StatusCheckRollup.State), nil
+}
+
+func forgeChecksState(s githubv4.StatusState) forge.ChecksState {
+	switch s {
+	case githubv4.StatusStateSuccess:
+		return forge.ChecksPassing
+	case githubv4.StatusStateFailure, githubv4.StatusStateError:
+		return forge.ChecksFailing
+	case githubv4.StatusStatePending, githubv4.StatusStateExpected:
+		return forge.ChecksPending
+	default:
+		return forge.ChecksNone
+	}
+}
@@ -0,0 +1,32 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// CloseChange closes an open PR without merging it.
+func (r *Repository) CloseChange(ctx context.Context, fid forge.ChangeID) error {
+	pr := mustPR(fid)
+	gqlID, err := r.graphQLID(ctx, pr)
+	if err != nil {
+		return fmt.Errorf("get pull request ID: %w", err)
+	}
+
+	var m struct {
+		ClosePullRequest struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"closePullRequest(input: $input)"`
+	}
+	input := githubv4.ClosePullRequestInput{
+		PullRequestID: gqlID,
+	}
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("close pull request: %w", err)
+	}
+
+	return nil
+}
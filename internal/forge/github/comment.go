@@ -100,6 +100,27 @@ func (f *Repository) UpdateChangeComment(
 	return nil
 }
 
+// ChangeComment reports the contents of an existing comment on a PR.
+func (f *Repository) ChangeComment(
+	ctx context.Context,
+	id forge.ChangeCommentID,
+) (string, error) {
+	cid := mustPRComment(id)
+
+	var q struct {
+		Node struct {
+			IssueComment struct {
+				Body githubv4.String `graphql:"body"`
+			} `graphql:"... on IssueComment"`
+		} `graphql:"node(id: $id)"`
+	}
+	if err := f.client.Query(ctx, &q, map[string]any{"id": cid.GQLID}); err != nil {
+		return "", fmt.Errorf("query comment: %w", err)
+	}
+
+	return string(q.Node.IssueComment.Body), nil
+}
+
 // DeleteChangeComment deletes an existing comment on a PR.
 func (f *Repository) DeleteChangeComment(
 	ctx context.Context,
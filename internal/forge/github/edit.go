@@ -3,15 +3,43 @@ package github
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/shurcooL/githubv4"
-	"go.abhg.dev/gs/internal/cmputil"
 	"go.abhg.dev/gs/internal/forge"
 )
 
 // EditChange edits an existing change in a repository.
 func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts forge.EditChangeOptions) error {
-	if cmputil.Zero(opts) {
+	err := r.editChange(ctx, fid, opts)
+	if isConflictError(err) {
+		return fmt.Errorf("%w: %w", forge.ErrChangeConflict, err)
+	}
+	return err
+}
+
+// isConflictError reports whether an error from a GraphQL mutation
+// indicates that the pull request was modified concurrently.
+//
+// GitHub reports GraphQL errors in the response body
+// of a 200 OK response, and the GraphQL client exposes
+// only the message of the first error,
+// so conflicts are identified by their message.
+func isConflictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "conflict") ||
+		strings.Contains(msg, "was modified")
+}
+
+func (r *Repository) editChange(ctx context.Context, fid forge.ChangeID, opts forge.EditChangeOptions) error {
+	if opts.Base == "" && opts.Draft == nil &&
+		opts.Title == nil && opts.Body == nil &&
+		len(opts.AddLabels) == 0 && len(opts.RemoveLabels) == 0 &&
+		len(opts.AddReviewers) == 0 && opts.Milestone == "" &&
+		len(opts.AddAssignees) == 0 {
 		return nil // nothing to do
 	}
 
@@ -21,7 +49,7 @@ func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts fo
 		return fmt.Errorf("get pull request ID: %w", err)
 	}
 
-	if opts.Base != "" {
+	if opts.Base != "" || opts.Title != nil || opts.Body != nil {
 		var m struct {
 			UpdatePullRequest struct {
 				// We don't need any information back,
@@ -32,7 +60,11 @@ func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts fo
 
 		input := githubv4.UpdatePullRequestInput{
 			PullRequestID: graphQLID,
-			BaseRefName:   (*githubv4.String)(&opts.Base),
+			Title:         (*githubv4.String)(opts.Title),
+			Body:          (*githubv4.String)(opts.Body),
+		}
+		if opts.Base != "" {
+			input.BaseRefName = (*githubv4.String)(&opts.Base)
 		}
 
 		if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
@@ -76,5 +108,41 @@ func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts fo
 		}
 	}
 
+	if len(opts.AddLabels) > 0 {
+		if err := r.addLabels(ctx, graphQLID, opts.AddLabels); err != nil {
+			return fmt.Errorf("add labels: %w", err)
+		}
+	}
+
+	if len(opts.RemoveLabels) > 0 {
+		if err := r.removeLabels(ctx, graphQLID, opts.RemoveLabels); err != nil {
+			return fmt.Errorf("remove labels: %w", err)
+		}
+	}
+
+	if len(opts.AddReviewers) > 0 {
+		if err := r.addReviewers(ctx, graphQLID, opts.AddReviewers); err != nil {
+			return fmt.Errorf("request reviews: %w", err)
+		}
+	}
+
+	if opts.Milestone != "" {
+		if err := r.setMilestone(ctx, graphQLID, opts.Milestone); err != nil {
+			return fmt.Errorf("set milestone: %w", err)
+		}
+	}
+
+	if len(opts.AddAssignees) > 0 {
+		assigned, err := r.hasAssignees(ctx, graphQLID)
+		if err != nil {
+			return err
+		}
+		if !assigned {
+			if err := r.addAssignees(ctx, graphQLID, opts.AddAssignees); err != nil {
+				return fmt.Errorf("add assignees: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"golang.org/x/oauth2"
+)
+
+func TestRepository_EditChange_conflict(t *testing.T) {
+	// GitHub reports GraphQL errors with a 200 OK status.
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		conflict bool
+	}{
+		{
+			name:     "Conflict",
+			status:   http.StatusOK,
+			body:     `{"data": null, "errors": [{"type": "CONFLICT", "message": "Pull request was modified concurrently"}]}`,
+			conflict: true,
+		},
+		{
+			name:   "Validation",
+			status: http.StatusOK,
+			body:   `{"data": null, "errors": [{"type": "UNPROCESSABLE", "message": "Title can't be blank"}]}`,
+		},
+		{
+			name:   "ServerError",
+			status: http.StatusInternalServerError,
+			body:   `{"message": "failed"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/graphql", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			t.Cleanup(srv.Close)
+
+			ctx := context.Background()
+			client, err := newGitHubv4Client(ctx, srv.URL,
+				oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}))
+			require.NoError(t, err)
+
+			repo, err := newRepository(ctx, new(Forge), "example", "repo", log.New(io.Discard), client, "R_1")
+			require.NoError(t, err)
+
+			title := "New title"
+			err = repo.EditChange(ctx, &PR{Number: 1, GQLID: "PR_1"}, forge.EditChangeOptions{
+				Title: &title,
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.conflict, errors.Is(err, forge.ErrChangeConflict), "error: %v", err)
+		})
+	}
+}
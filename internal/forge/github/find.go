@@ -14,13 +14,45 @@ type findPRNode struct {
 	Number      githubv4.Int              `graphql:"number"`
 	URL         githubv4.URI              `graphql:"url"`
 	Title       githubv4.String           `graphql:"title"`
+	Body        githubv4.String           `graphql:"body"`
 	State       githubv4.PullRequestState `graphql:"state"`
 	HeadRefOid  githubv4.GitObjectID      `graphql:"headRefOid"`
 	BaseRefName githubv4.String           `graphql:"baseRefName"`
 	IsDraft     githubv4.Boolean          `graphql:"isDraft"`
+
+	Labels struct {
+		Nodes []struct {
+			Name githubv4.String `graphql:"name"`
+		} `graphql:"nodes"`
+	} `graphql:"labels(first: 100)"`
+
+	Assignees struct {
+		Nodes []struct {
+			Login githubv4.String `graphql:"login"`
+		} `graphql:"nodes"`
+	} `graphql:"assignees(first: 100)"`
+
+	Milestone *struct {
+		Title githubv4.String `graphql:"title"`
+	} `graphql:"milestone"`
 }
 
 func (n *findPRNode) toFindChangeItem() *forge.FindChangeItem {
+	var labels []string
+	for _, label := range n.Labels.Nodes {
+		labels = append(labels, string(label.Name))
+	}
+
+	var assignees []string
+	for _, user := range n.Assignees.Nodes {
+		assignees = append(assignees, string(user.Login))
+	}
+
+	var milestone string
+	if n.Milestone != nil {
+		milestone = string(n.Milestone.Title)
+	}
+
 	return &forge.FindChangeItem{
 		ID: &PR{
 			Number: int(n.Number),
@@ -29,9 +61,14 @@ func (n *findPRNode) toFindChangeItem() *forge.FindChangeItem {
 		URL:      n.URL.String(),
 		State:    forgeChangeState(n.State),
 		Subject:  string(n.Title),
+		Body:     string(n.Body),
 		BaseName: string(n.BaseRefName),
 		HeadHash: git.Hash(n.HeadRefOid),
 		Draft:    bool(n.IsDraft),
+		Labels:   labels,
+
+		Assignees: assignees,
+		Milestone: milestone,
 	}
 }
 
@@ -123,7 +123,7 @@ func extractRepoInfo(githubURL, remoteURL string) (owner, repo string, err error
 		return "", "", fmt.Errorf("parse remote URL: %w", err)
 	}
 
-	if u.Host != baseURL.Host {
+	if !forge.MatchHost(u.Host, baseURL.Host) {
 		return "", "", fmt.Errorf("%v is not a GitHub URL: expected host %q", u, baseURL.Host)
 	}
 
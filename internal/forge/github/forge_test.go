@@ -59,6 +59,13 @@ func TestExtractRepoInfo(t *testing.T) {
 			wantOwner: "example",
 			wantRepo:  "repo",
 		},
+		{
+			name:      "ssh/custom URL subdomain",
+			give:      "git@ssh.github.example.com:example/repo.git",
+			githubURL: "https://github.example.com",
+			wantOwner: "example",
+			wantRepo:  "repo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -332,19 +332,19 @@ func TestIntegration_Repository_SubmitEditChange(t *testing.T) {
 		}), "could not commit changes")
 
 		t.Logf("Pushing to origin")
-		require.NoError(t,
-			gitRepo.Push(ctx, git.PushOptions{
-				Remote:  "origin",
-				Refspec: git.Refspec(branchName),
-			}), "error pushing branch")
+		_, err = gitRepo.Push(ctx, git.PushOptions{
+			Remote:  "origin",
+			Refspec: git.Refspec(branchName),
+		})
+		require.NoError(t, err, "error pushing branch")
 
 		t.Cleanup(func() {
 			t.Logf("Deleting remote branch: %s", branchName)
-			assert.NoError(t,
-				gitRepo.Push(ctx, git.PushOptions{
-					Remote:  "origin",
-					Refspec: git.Refspec(":" + branchName),
-				}), "error deleting branch")
+			_, err := gitRepo.Push(ctx, git.PushOptions{
+				Remote:  "origin",
+				Refspec: git.Refspec(":" + branchName),
+			})
+			assert.NoError(t, err, "error deleting branch")
 		})
 	}
 
@@ -372,19 +372,19 @@ func TestIntegration_Repository_SubmitEditChange(t *testing.T) {
 		newBase := newBaseFixture.Get(t)
 		t.Logf("Pushing new base: %s", newBase)
 		if *_update {
-			require.NoError(t,
-				gitRepo.Push(ctx, git.PushOptions{
-					Remote:  "origin",
-					Refspec: git.Refspec("main:" + newBase),
-				}), "could not push base branch")
+			_, err := gitRepo.Push(ctx, git.PushOptions{
+				Remote:  "origin",
+				Refspec: git.Refspec("main:" + newBase),
+			})
+			require.NoError(t, err, "could not push base branch")
 
 			t.Cleanup(func() {
 				t.Logf("Deleting remote branch: %s", newBase)
-				require.NoError(t,
-					gitRepo.Push(ctx, git.PushOptions{
-						Remote:  "origin",
-						Refspec: git.Refspec(":" + newBase),
-					}), "error deleting branch")
+				_, err := gitRepo.Push(ctx, git.PushOptions{
+					Remote:  "origin",
+					Refspec: git.Refspec(":" + newBase),
+				})
+				require.NoError(t, err, "error deleting branch")
 			})
 		}
 
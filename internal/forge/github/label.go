@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// labelIDs resolves the GraphQL IDs of the labels with the given names.
+//
+// Labels that don't exist in the repository are reported
+// in the missing slice.
+func (r *Repository) labelIDs(ctx context.Context, names []string) (ids []githubv4.ID, missing []string, err error) {
+	for _, name := range names {
+		var q struct {
+			Repository struct {
+				Label *struct {
+					ID githubv4.ID `graphql:"id"`
+				} `graphql:"label(name: $name)"`
+			} `graphql:"repository(owner: $owner, name: $repo)"`
+		}
+		if err := r.client.Query(ctx, &q, map[string]any{
+			"owner": githubv4.String(r.owner),
+			"repo":  githubv4.String(r.repo),
+			"name":  githubv4.String(name),
+		}); err != nil {
+			return nil, nil, fmt.Errorf("query label %q: %w", name, err)
+		}
+
+		if q.Repository.Label == nil {
+			missing = append(missing, name)
+			continue
+		}
+		ids = append(ids, q.Repository.Label.ID)
+	}
+
+	return ids, missing, nil
+}
+
+// _newLabelColor is the color of labels created by addLabels.
+// This matches the default color of new labels on GitHub.
+const _newLabelColor = "ededed"
+
+// addLabels adds the labels with the given names to a labelable object.
+// Labels that don't exist in the repository are created.
+// If a label can't be created, it's skipped with a warning.
+func (r *Repository) addLabels(ctx context.Context, id githubv4.ID, names []string) error {
+	labelIDs, missing, err := r.labelIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+	for _, name := range missing {
+		labelID, err := r.createLabel(ctx, name)
+		if err != nil {
+			r.log.Warn("Label does not exist and could not be created. Skipping.", "label", name, "error", err)
+			continue
+		}
+		labelIDs = append(labelIDs, labelID)
+	}
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	var m struct {
+		AddLabelsToLabelable struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"addLabelsToLabelable(input: $input)"`
+	}
+	input := githubv4.AddLabelsToLabelableInput{
+		LabelableID: id,
+		LabelIDs:    labelIDs,
+	}
+	return r.client.Mutate(ctx, &m, input, nil)
+}
+
+// createLabel creates a label with the given name in the repository
+// and returns its GraphQL ID.
+func (r *Repository) createLabel(ctx context.Context, name string) (githubv4.ID, error) {
+	var m struct {
+		CreateLabel struct {
+			Label struct {
+				ID githubv4.ID `graphql:"id"`
+			} `graphql:"label"`
+		} `graphql:"createLabel(input: $input)"`
+	}
+	input := githubv4.CreateLabelInput{
+		RepositoryID: r.repoID,
+		Name:         githubv4.String(name),
+		Color:        githubv4.String(_newLabelColor),
+	}
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return nil, fmt.Errorf("create label %q: %w", name, err)
+	}
+	return m.CreateLabel.Label.ID, nil
+}
+
+func (r *Repository) removeLabels(ctx context.Context, id githubv4.ID, names []string) error {
+	// Labels that don't exist can't be on the change,
+	// so there's nothing to remove.
+	labelIDs, _, err := r.labelIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	var m struct {
+		RemoveLabelsFromLabelable struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"removeLabelsFromLabelable(input: $input)"`
+	}
+	input := githubv4.RemoveLabelsFromLabelableInput{
+		LabelableID: id,
+		LabelIDs:    labelIDs,
+	}
+	return r.client.Mutate(ctx, &m, input, nil)
+}
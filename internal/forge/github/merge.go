@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// EnqueueMerge adds a PR to the merge queue of its base branch
+// if it has one, and enables auto-merge for it otherwise.
+//
+// The merge method is ignored for merge queues
+// as they use the method configured for the queue.
+func (r *Repository) EnqueueMerge(ctx context.Context, fid forge.ChangeID, method forge.MergeMethod) (forge.EnqueueMergeResult, error) {
+	pr := mustPR(fid)
+
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				ID                  githubv4.ID `graphql:"id"`
+				IsMergeQueueEnabled bool        `graphql:"isMergeQueueEnabled"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	if err := r.client.Query(ctx, &q, map[string]any{
+		"owner":  githubv4.String(r.owner),
+		"repo":   githubv4.String(r.repo),
+		"number": githubv4.Int(pr.Number),
+	}); err != nil {
+		return forge.EnqueueMergeResult{}, fmt.Errorf("query pull request: %w", err)
+	}
+	gqlID := q.Repository.PullRequest.ID
+
+	if q.Repository.PullRequest.IsMergeQueueEnabled {
+		var m struct {
+			EnqueuePullRequest struct {
+				ClientMutationID string `graphql:"clientMutationId"`
+			} `graphql:"enqueuePullRequest(input: $input)"`
+		}
+		input := githubv4.EnqueuePullRequestInput{
+			PullRequestID: gqlID,
+		}
+		if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+			return forge.EnqueueMergeResult{}, fmt.Errorf("enqueue pull request: %w", err)
+		}
+
+		return forge.EnqueueMergeResult{}, nil
+	}
+
+	var m struct {
+		EnablePullRequestAutoMerge struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"enablePullRequestAutoMerge(input: $input)"`
+	}
+	input := githubv4.EnablePullRequestAutoMergeInput{
+		PullRequestID: gqlID,
+		MergeMethod:   pullRequestMergeMethod(method),
+	}
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return forge.EnqueueMergeResult{}, fmt.Errorf("enable auto-merge: %w", err)
+	}
+
+	return forge.EnqueueMergeResult{AutoMerge: true}, nil
+}
+
+// pullRequestMergeMethod returns the GitHub merge method
+// for the given forge merge method,
+// or nil to use the default.
+func pullRequestMergeMethod(m forge.MergeMethod) *githubv4.PullRequestMergeMethod {
+	var method githubv4.PullRequestMergeMethod
+	switch m {
+	case forge.MergeMethodMerge:
+		method = githubv4.PullRequestMergeMethodMerge
+	case forge.MergeMethodSquash:
+		method = githubv4.PullRequestMergeMethodSquash
+	case forge.MergeMethodRebase:
+		method = githubv4.PullRequestMergeMethodRebase
+	default:
+		return nil
+	}
+	return &method
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type milestoneNode struct {
+	ID     githubv4.ID     `graphql:"id"`
+	Title  githubv4.String `graphql:"title"`
+	Number githubv4.Int    `graphql:"number"`
+}
+
+// FindMilestone looks up a milestone in the repository
+// by its title or number.
+func (r *Repository) FindMilestone(ctx context.Context, name string) (*forge.Milestone, error) {
+	m, err := r.findMilestone(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &forge.Milestone{
+		Title:  string(m.Title),
+		Number: int(m.Number),
+	}, nil
+}
+
+func (r *Repository) findMilestone(ctx context.Context, name string) (*milestoneNode, error) {
+	if num, err := strconv.Atoi(name); err == nil {
+		var q struct {
+			Repository struct {
+				Milestone *milestoneNode `graphql:"milestone(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $repo)"`
+		}
+		if err := r.client.Query(ctx, &q, map[string]any{
+			"owner":  githubv4.String(r.owner),
+			"repo":   githubv4.String(r.repo),
+			"number": githubv4.Int(num),
+		}); err != nil {
+			return nil, fmt.Errorf("query milestone %d: %w", num, err)
+		}
+
+		if q.Repository.Milestone != nil {
+			return q.Repository.Milestone, nil
+		}
+		// A milestone may be titled with just a number.
+		// Fall back to searching by title.
+	}
+
+	var q struct {
+		Repository struct {
+			Milestones struct {
+				Nodes []milestoneNode `graphql:"nodes"`
+			} `graphql:"milestones(first: 100, query: $query)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	if err := r.client.Query(ctx, &q, map[string]any{
+		"owner": githubv4.String(r.owner),
+		"repo":  githubv4.String(r.repo),
+		"query": githubv4.String(name),
+	}); err != nil {
+		return nil, fmt.Errorf("query milestones: %w", err)
+	}
+
+	// The search is fuzzy, so look for an exact match.
+	for _, m := range q.Repository.Milestones.Nodes {
+		if strings.EqualFold(string(m.Title), name) {
+			return &m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%q: %w", name, forge.ErrMilestoneNotFound)
+}
+
+// setMilestone attaches the milestone with the given title or number
+// to a pull request.
+func (r *Repository) setMilestone(ctx context.Context, id githubv4.ID, name string) error {
+	milestone, err := r.findMilestone(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var m struct {
+		UpdatePullRequest struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"updatePullRequest(input: $input)"`
+	}
+	input := githubv4.UpdatePullRequestInput{
+		PullRequestID: id,
+		MilestoneID:   &milestone.ID,
+	}
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("update pull request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// reviewerIDs resolves the GraphQL IDs of the given reviewers.
+// Reviewers in the form "org/team" are teams,
+// and all others are users.
+func (r *Repository) reviewerIDs(ctx context.Context, names []string) (userIDs, teamIDs []githubv4.ID, err error) {
+	for _, name := range names {
+		if org, slug, ok := strings.Cut(name, "/"); ok {
+			var q struct {
+				Organization *struct {
+					Team *struct {
+						ID githubv4.ID `graphql:"id"`
+					} `graphql:"team(slug: $slug)"`
+				} `graphql:"organization(login: $org)"`
+			}
+			if err := r.client.Query(ctx, &q, map[string]any{
+				"org":  githubv4.String(org),
+				"slug": githubv4.String(slug),
+			}); err != nil {
+				return nil, nil, fmt.Errorf("query team %q: %w", name, err)
+			}
+			if q.Organization == nil || q.Organization.Team == nil {
+				return nil, nil, fmt.Errorf("team not found: %q", name)
+			}
+			teamIDs = append(teamIDs, q.Organization.Team.ID)
+			continue
+		}
+
+		var q struct {
+			User *struct {
+				ID githubv4.ID `graphql:"id"`
+			} `graphql:"user(login: $login)"`
+		}
+		if err := r.client.Query(ctx, &q, map[string]any{
+			"login": githubv4.String(name),
+		}); err != nil {
+			return nil, nil, fmt.Errorf("query user %q: %w", name, err)
+		}
+		if q.User == nil {
+			return nil, nil, fmt.Errorf("user not found: %q", name)
+		}
+		userIDs = append(userIDs, q.User.ID)
+	}
+
+	return userIDs, teamIDs, nil
+}
+
+func (r *Repository) addReviewers(ctx context.Context, id githubv4.ID, names []string) error {
+	userIDs, teamIDs, err := r.reviewerIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	var m struct {
+		RequestReviews struct {
+			ClientMutationID string `graphql:"clientMutationId"`
+		} `graphql:"requestReviews(input: $input)"`
+	}
+	input := githubv4.RequestReviewsInput{
+		PullRequestID: id,
+		Union:         githubv4.NewBoolean(true),
+	}
+	if len(userIDs) > 0 {
+		input.UserIDs = &userIDs
+	}
+	if len(teamIDs) > 0 {
+		input.TeamIDs = &teamIDs
+	}
+	return r.client.Mutate(ctx, &m, input, nil)
+}
@@ -37,11 +37,35 @@ func (r *Repository) SubmitChange(ctx context.Context, req forge.SubmitChangeReq
 		return forge.SubmitChangeResult{}, fmt.Errorf("create pull request: %w", err)
 	}
 
+	pr := &PR{
+		Number: int(m.CreatePullRequest.PullRequest.Number),
+		GQLID:  m.CreatePullRequest.PullRequest.ID,
+	}
+
+	// Labels can't be set when the pull request is created.
+	// Failing to label it shouldn't lose track of the pull request,
+	// so this only logs a warning.
+	if len(req.Labels) > 0 {
+		if err := r.addLabels(ctx, pr.GQLID, req.Labels); err != nil {
+			r.log.Warn("Could not add labels", "pr", pr, "error", err)
+		}
+	}
+
+	// Same for the milestone.
+	if req.Milestone != "" {
+		if err := r.setMilestone(ctx, pr.GQLID, req.Milestone); err != nil {
+			r.log.Warn("Could not set milestone", "pr", pr, "error", err)
+		}
+	}
+
+	if len(req.Assignees) > 0 {
+		if err := r.addAssignees(ctx, pr.GQLID, req.Assignees); err != nil {
+			r.log.Warn("Could not add assignees", "pr", pr, "error", err)
+		}
+	}
+
 	return forge.SubmitChangeResult{
-		ID: &PR{
-			Number: int(m.CreatePullRequest.PullRequest.Number),
-			GQLID:  m.CreatePullRequest.PullRequest.ID,
-		},
+		ID:  pr,
 		URL: m.CreatePullRequest.PullRequest.URL.String(),
 	}, nil
 }
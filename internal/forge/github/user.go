@@ -0,0 +1,29 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// CurrentUser reports the user authenticated against GitHub.
+func (r *Repository) CurrentUser(ctx context.Context) (forge.User, error) {
+	var q struct {
+		Viewer struct {
+			ID    githubv4.ID     `graphql:"id"`
+			Login githubv4.String `graphql:"login"`
+			Name  githubv4.String `graphql:"name"`
+		} `graphql:"viewer"`
+	}
+	if err := r.client.Query(ctx, &q, nil); err != nil {
+		return forge.User{}, fmt.Errorf("query viewer: %w", err)
+	}
+
+	return forge.User{
+		Login: string(q.Viewer.Login),
+		ID:    fmt.Sprint(q.Viewer.ID),
+		Name:  string(q.Viewer.Name),
+	}, nil
+}
@@ -0,0 +1,107 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// MRMetadata is the metadata for a merge request.
+type MRMetadata struct {
+	MR *MR `json:"mr,omitempty"`
+
+	StackComment  *MRComment `json:"comment,omitempty"`
+	CustomComment *MRComment `json:"customComment,omitempty"`
+}
+
+var _ forge.ChangeMetadata = (*MRMetadata)(nil)
+
+// ForgeID reports the forge ID that owns this metadata.
+func (*MRMetadata) ForgeID() string {
+	return "gitlab"
+}
+
+// ChangeID reports the change ID of the merge request.
+func (m *MRMetadata) ChangeID() forge.ChangeID {
+	return m.MR
+}
+
+// StackCommentID reports the comment ID of the stack comment
+// left on the merge request.
+func (m *MRMetadata) StackCommentID() forge.ChangeCommentID {
+	if m.StackComment == nil {
+		return nil
+	}
+	return m.StackComment
+}
+
+// SetStackCommentID sets the comment ID of the stack comment
+// left on the merge request.
+//
+// id may be nil.
+func (m *MRMetadata) SetStackCommentID(id forge.ChangeCommentID) {
+	m.StackComment = mustMRComment(id)
+}
+
+// CustomCommentID reports the comment ID of the custom comment
+// posted on the merge request with 'submit --comment'.
+func (m *MRMetadata) CustomCommentID() forge.ChangeCommentID {
+	if m.CustomComment == nil {
+		return nil
+	}
+	return m.CustomComment
+}
+
+// SetCustomCommentID sets the comment ID of the custom comment
+// posted on the merge request.
+//
+// id may be nil.
+func (m *MRMetadata) SetCustomCommentID(id forge.ChangeCommentID) {
+	m.CustomComment = mustMRComment(id)
+}
+
+// NewChangeMetadata returns the metadata for a merge request.
+func (r *Repository) NewChangeMetadata(
+	ctx context.Context,
+	id forge.ChangeID,
+) (forge.ChangeMetadata, error) {
+	return &MRMetadata{MR: mustMR(id)}, nil
+}
+
+// MarshalChangeMetadata serializes a MRMetadata into JSON.
+func (*Forge) MarshalChangeMetadata(md forge.ChangeMetadata) (json.RawMessage, error) {
+	return json.Marshal(md)
+}
+
+// UnmarshalChangeMetadata deserializes a MRMetadata from JSON.
+func (*Forge) UnmarshalChangeMetadata(data json.RawMessage) (forge.ChangeMetadata, error) {
+	var md MRMetadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("unmarshal MR metadata: %w", err)
+	}
+	return &md, nil
+}
+
+// MR uniquely identifies a merge request in a GitLab project.
+// It's a valid forge.ChangeID.
+type MR struct {
+	// Number is the project-scoped ID (IID) of the merge request.
+	// This is the number shown in the GitLab UI.
+	Number int `json:"number"`
+}
+
+var _ forge.ChangeID = (*MR)(nil)
+
+func mustMR(cid forge.ChangeID) *MR {
+	mr, ok := cid.(*MR)
+	if !ok {
+		panic(fmt.Sprintf("unexpected change ID type: %T", cid))
+	}
+	return mr
+}
+
+func (id *MR) String() string {
+	return fmt.Sprintf("!%d", id.Number)
+}
@@ -0,0 +1,48 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestMustMR(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.Equal(t, &MR{Number: 42}, mustMR(&MR{Number: 42}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var x struct{ forge.ChangeID }
+
+		assert.Panics(t, func() {
+			mustMR(&x)
+		})
+	})
+}
+
+func TestMRString(t *testing.T) {
+	assert.Equal(t, "!42", (&MR{Number: 42}).String())
+}
+
+func TestMRMetadata_roundTrip(t *testing.T) {
+	var f Forge
+
+	md := &MRMetadata{MR: &MR{Number: 42}}
+	md.SetStackCommentID(&MRComment{MR: 42, ID: 100, URL: "https://gitlab.com/a/b/-/merge_requests/42#note_100"})
+
+	bs, err := f.MarshalChangeMetadata(md)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"mr": {"number": 42},
+		"comment": {"mr": 42, "id": 100, "url": "https://gitlab.com/a/b/-/merge_requests/42#note_100"}
+	}`, string(bs))
+
+	got, err := f.UnmarshalChangeMetadata(bs)
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+	assert.Equal(t, "gitlab", got.ForgeID())
+	assert.Equal(t, &MR{Number: 42}, got.ChangeID())
+	assert.Nil(t, got.CustomCommentID())
+}
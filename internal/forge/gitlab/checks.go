@@ -0,0 +1,35 @@
+package gitlab
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeChecksState reports the state of the pipeline
+// that ran against the head commit of a merge request.
+func (r *Repository) ChangeChecksState(ctx context.Context, id forge.ChangeID) (forge.ChecksState, error) {
+	mr, err := r.mergeRequest(ctx, mustMR(id))
+	if err != nil {
+		return forge.ChecksNone, err
+	}
+
+	if mr.HeadPipeline == nil {
+		return forge.ChecksNone, nil
+	}
+	return forgeChecksState(mr.HeadPipeline.Status), nil
+}
+
+func forgeChecksState(status string) forge.ChecksState {
+	switch status {
+	case "success":
+		return forge.ChecksPassing
+	case "failed", "canceled":
+		return forge.ChecksFailing
+	case "created", "waiting_for_resource", "preparing",
+		"pending", "running", "scheduled", "manual":
+		return forge.ChecksPending
+	default:
+		return forge.ChecksNone
+	}
+}
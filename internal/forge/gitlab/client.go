@@ -0,0 +1,124 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// errNotFound indicates that the requested resource does not exist.
+var errNotFound = errors.New("not found")
+
+// errConflict indicates that the request conflicts
+// with the current state of the resource.
+var errConflict = errors.New("conflict")
+
+// client is a minimal client for the GitLab REST API.
+type client struct {
+	baseURL *url.URL
+	token   string
+	http    interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+}
+
+func newClient(apiURL, token string) (*client, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad API URL: %w", err)
+	}
+
+	return &client{
+		baseURL: u,
+		token:   token,
+		http:    http.DefaultClient,
+	}, nil
+}
+
+// Get sends a GET request to the given path under the API URL,
+// and decodes the JSON response into res.
+func (c *client) Get(ctx context.Context, path string, query url.Values, res any) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, res)
+}
+
+// Post sends a POST request with a JSON body
+// to the given path under the API URL.
+func (c *client) Post(ctx context.Context, path string, req, res any) error {
+	return c.do(ctx, http.MethodPost, path, nil, req, res)
+}
+
+// Put sends a PUT request with a JSON body
+// to the given path under the API URL.
+func (c *client) Put(ctx context.Context, path string, req, res any) error {
+	return c.do(ctx, http.MethodPut, path, nil, req, res)
+}
+
+func (c *client) do(ctx context.Context, method, path string, query url.Values, req, res any) error {
+	// Paths contain URL-encoded project paths (e.g. "group%2Fproject")
+	// that must not be decoded and re-encoded.
+	u := *c.baseURL
+	u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + "/" + path
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	u.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if req != nil {
+		bs, err := json.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(bs)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return fmt.Errorf("create HTTP request: %w", err)
+	}
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		// GitLab accepts both, OAuth tokens and
+		// Personal Access Tokens as bearer tokens.
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send HTTP request: %w", err)
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	resBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body: %w", err)
+	}
+
+	switch {
+	case httpResp.StatusCode >= 200 && httpResp.StatusCode < 300:
+		// ok
+	case httpResp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s %s: %w", method, path, errNotFound)
+	case httpResp.StatusCode == http.StatusConflict:
+		return fmt.Errorf("%s %s: %w\nbody: %s", method, path, errConflict, resBody)
+	default:
+		return fmt.Errorf("%s %s: unexpected status code %d\nbody: %s", method, path, httpResp.StatusCode, resBody)
+	}
+
+	if res == nil || len(resBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resBody, res); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// CloseChange closes an open merge request without merging it.
+func (r *Repository) CloseChange(ctx context.Context, fid forge.ChangeID) error {
+	input := updateMergeRequestRequest{StateEvent: "close"}
+	if err := r.client.Put(ctx, r.mrPath(mustMR(fid)), input, nil); err != nil {
+		return fmt.Errorf("close merge request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// MRComment is a ChangeCommentID for a GitLab merge request note.
+type MRComment struct {
+	// MR is the IID of the merge request the note is on.
+	// Notes can only be addressed through their merge request.
+	MR int `json:"mr"`
+
+	// ID is the ID of the note.
+	ID int `json:"id"`
+
+	URL string `json:"url,omitempty"`
+}
+
+var _ forge.ChangeCommentID = (*MRComment)(nil)
+
+func mustMRComment(id forge.ChangeCommentID) *MRComment {
+	if id == nil {
+		return nil
+	}
+
+	mrc, ok := id.(*MRComment)
+	if !ok {
+		panic(fmt.Sprintf("unexpected MR comment type: %T", id))
+	}
+	return mrc
+}
+
+func (c *MRComment) String() string {
+	return c.URL
+}
+
+type noteRequest struct {
+	Body string `json:"body"`
+}
+
+type noteResponse struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+// PostChangeComment posts a new comment on a merge request.
+func (r *Repository) PostChangeComment(
+	ctx context.Context,
+	id forge.ChangeID,
+	markdown string,
+) (forge.ChangeCommentID, error) {
+	mr := mustMR(id)
+
+	var res noteResponse
+	if err := r.client.Post(ctx, r.mrPath(mr, "notes"), noteRequest{Body: markdown}, &res); err != nil {
+		return nil, fmt.Errorf("post comment: %w", err)
+	}
+
+	// The notes API doesn't report a URL for the note,
+	// so link to its anchor on the merge request page.
+	// Failure to get the URL isn't fatal.
+	var noteURL string
+	if item, err := r.FindChangeByID(ctx, mr); err == nil {
+		noteURL = item.URL + "#note_" + strconv.Itoa(res.ID)
+	}
+
+	r.log.Debug("Posted comment", "mr", mr, "note", res.ID)
+	return &MRComment{
+		MR:  mr.Number,
+		ID:  res.ID,
+		URL: noteURL,
+	}, nil
+}
+
+// UpdateChangeComment updates the contents of an existing comment
+// on a merge request.
+func (r *Repository) UpdateChangeComment(
+	ctx context.Context,
+	id forge.ChangeCommentID,
+	markdown string,
+) error {
+	cid := mustMRComment(id)
+
+	path := r.mrPath(&MR{Number: cid.MR}, "notes", strconv.Itoa(cid.ID))
+	if err := r.client.Put(ctx, path, noteRequest{Body: markdown}, nil); err != nil {
+		return fmt.Errorf("update comment: %w", err)
+	}
+
+	r.log.Debug("Updated comment", "url", cid.URL)
+	return nil
+}
+
+// ChangeComment reports the contents of an existing comment
+// on a merge request.
+func (r *Repository) ChangeComment(
+	ctx context.Context,
+	id forge.ChangeCommentID,
+) (string, error) {
+	cid := mustMRComment(id)
+
+	var res noteResponse
+	path := r.mrPath(&MR{Number: cid.MR}, "notes", strconv.Itoa(cid.ID))
+	if err := r.client.Get(ctx, path, nil, &res); err != nil {
+		return "", fmt.Errorf("get comment: %w", err)
+	}
+
+	return res.Body, nil
+}
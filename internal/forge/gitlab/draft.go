@@ -0,0 +1,41 @@
+package gitlab
+
+import "strings"
+
+// _draftPrefix is the prefix added to the titles of draft merge requests.
+const _draftPrefix = "Draft: "
+
+// _draftPrefixes are the title prefixes that GitLab recognizes
+// as marking a merge request as a draft.
+// These are matched case-insensitively.
+var _draftPrefixes = []string{
+	"draft:",
+	"[draft]",
+	"(draft)",
+}
+
+// trimDraftPrefix removes the prefix marking a merge request as a draft
+// from the given title, if it has one.
+// It reports whether a prefix was removed.
+func trimDraftPrefix(title string) (string, bool) {
+	lower := strings.ToLower(title)
+	for _, prefix := range _draftPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(title[len(prefix):]), true
+		}
+	}
+	return title, false
+}
+
+// draftTitle returns the title to use for a merge request
+// with the given draft status.
+//
+// GitLab has no separate draft flag:
+// merge requests are drafts if their title has a draft prefix.
+func draftTitle(title string, draft bool) string {
+	title, _ = trimDraftPrefix(title)
+	if draft {
+		return _draftPrefix + title
+	}
+	return title
+}
@@ -0,0 +1,38 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimDraftPrefix(t *testing.T) {
+	tests := []struct {
+		give string
+
+		want      string
+		wantDraft bool
+	}{
+		{give: "Add feature", want: "Add feature"},
+		{give: "Draft: Add feature", want: "Add feature", wantDraft: true},
+		{give: "draft:Add feature", want: "Add feature", wantDraft: true},
+		{give: "[Draft] Add feature", want: "Add feature", wantDraft: true},
+		{give: "(DRAFT) Add feature", want: "Add feature", wantDraft: true},
+		{give: "Drafting: Add feature", want: "Drafting: Add feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, draft := trimDraftPrefix(tt.give)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantDraft, draft)
+		})
+	}
+}
+
+func TestDraftTitle(t *testing.T) {
+	assert.Equal(t, "Draft: Add feature", draftTitle("Add feature", true))
+	assert.Equal(t, "Draft: Add feature", draftTitle("[Draft] Add feature", true))
+	assert.Equal(t, "Add feature", draftTitle("Draft: Add feature", false))
+	assert.Equal(t, "Add feature", draftTitle("Add feature", false))
+}
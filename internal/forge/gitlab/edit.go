@@ -0,0 +1,112 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type updateMergeRequestRequest struct {
+	TargetBranch *string `json:"target_branch,omitempty"`
+	Title        *string `json:"title,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	AddLabels    string  `json:"add_labels,omitempty"`
+	RemoveLabels string  `json:"remove_labels,omitempty"`
+	MilestoneID  *int    `json:"milestone_id,omitempty"`
+	ReviewerIDs  *[]int  `json:"reviewer_ids,omitempty"`
+	AssigneeIDs  *[]int  `json:"assignee_ids,omitempty"`
+	StateEvent   string  `json:"state_event,omitempty"`
+}
+
+// EditChange edits an existing merge request.
+func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts forge.EditChangeOptions) error {
+	if opts.Base == "" && opts.Draft == nil &&
+		opts.Title == nil && opts.Body == nil &&
+		len(opts.AddLabels) == 0 && len(opts.RemoveLabels) == 0 &&
+		len(opts.AddReviewers) == 0 && opts.Milestone == "" &&
+		len(opts.AddAssignees) == 0 {
+		return nil // nothing to do
+	}
+
+	id := mustMR(fid)
+	input := updateMergeRequestRequest{
+		Description:  opts.Body,
+		AddLabels:    strings.Join(opts.AddLabels, ","),
+		RemoveLabels: strings.Join(opts.RemoveLabels, ","),
+	}
+	if opts.Base != "" {
+		input.TargetBranch = &opts.Base
+	}
+
+	// The draft status is part of the title,
+	// and the reviewers are replaced wholesale,
+	// so changing either requires the current state.
+	// Assignees are only added if there aren't any yet.
+	if opts.Draft != nil || opts.Title != nil ||
+		len(opts.AddReviewers) > 0 || len(opts.AddAssignees) > 0 {
+		mr, err := r.mergeRequest(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if opts.Draft != nil || opts.Title != nil {
+			title, draft := mr.Title, mr.Draft
+			if opts.Title != nil {
+				title = *opts.Title
+			}
+			if opts.Draft != nil {
+				draft = *opts.Draft
+			}
+			if title = draftTitle(title, draft); title != mr.Title {
+				input.Title = &title
+			}
+		}
+
+		if len(opts.AddReviewers) > 0 {
+			reviewerIDs := make([]int, 0, len(mr.Reviewers)+len(opts.AddReviewers))
+			for _, reviewer := range mr.Reviewers {
+				reviewerIDs = append(reviewerIDs, reviewer.ID)
+			}
+
+			userIDs, err := r.userIDs(ctx, opts.AddReviewers)
+			if err != nil {
+				return fmt.Errorf("request reviews: %w", err)
+			}
+			for _, userID := range userIDs {
+				if !slices.Contains(reviewerIDs, userID) {
+					reviewerIDs = append(reviewerIDs, userID)
+				}
+			}
+			input.ReviewerIDs = &reviewerIDs
+		}
+
+		if len(opts.AddAssignees) > 0 && len(mr.Assignees) == 0 {
+			assigneeIDs, err := r.userIDs(ctx, opts.AddAssignees)
+			if err != nil {
+				return fmt.Errorf("add assignees: %w", err)
+			}
+			input.AssigneeIDs = &assigneeIDs
+		}
+	}
+
+	if opts.Milestone != "" {
+		m, err := r.findMilestone(ctx, opts.Milestone)
+		if err != nil {
+			return fmt.Errorf("set milestone: %w", err)
+		}
+		input.MilestoneID = &m.ID
+	}
+
+	if err := r.client.Put(ctx, r.mrPath(id), input, nil); err != nil {
+		if errors.Is(err, errConflict) {
+			return fmt.Errorf("edit merge request: %w: %w", forge.ErrChangeConflict, err)
+		}
+		return fmt.Errorf("edit merge request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+)
+
+// mergeRequest is a merge request as reported by the GitLab API.
+// Only the fields we need are included.
+type mergeRequest struct {
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	State        string `json:"state"`
+	SHA          string `json:"sha"`
+	TargetBranch string `json:"target_branch"`
+	Draft        bool   `json:"draft"`
+
+	Labels []string `json:"labels"`
+
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+
+	Reviewers []struct {
+		ID int `json:"id"`
+	} `json:"reviewers"`
+
+	Assignees []struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	} `json:"assignees"`
+}
+
+func (mr *mergeRequest) toFindChangeItem() *forge.FindChangeItem {
+	// The draft status is part of the title on GitLab.
+	// Report the title without it so that it can be compared
+	// with titles generated from commit messages.
+	subject, _ := trimDraftPrefix(mr.Title)
+
+	var assignees []string
+	for _, user := range mr.Assignees {
+		assignees = append(assignees, user.Username)
+	}
+
+	var milestone string
+	if mr.Milestone != nil {
+		milestone = mr.Milestone.Title
+	}
+
+	return &forge.FindChangeItem{
+		ID:       &MR{Number: mr.IID},
+		URL:      mr.WebURL,
+		State:    forgeChangeState(mr.State),
+		Subject:  subject,
+		Body:     mr.Description,
+		BaseName: mr.TargetBranch,
+		HeadHash: git.Hash(mr.SHA),
+		Draft:    mr.Draft,
+		Labels:   mr.Labels,
+
+		Assignees: assignees,
+		Milestone: milestone,
+	}
+}
+
+func mergeRequestState(s forge.ChangeState) string {
+	switch s {
+	case forge.ChangeOpen:
+		return "opened"
+	case forge.ChangeClosed:
+		return "closed"
+	case forge.ChangeMerged:
+		return "merged"
+	default:
+		return "all"
+	}
+}
+
+func forgeChangeState(s string) forge.ChangeState {
+	switch s {
+	case "opened", "locked":
+		return forge.ChangeOpen
+	case "closed":
+		return forge.ChangeClosed
+	case "merged":
+		return forge.ChangeMerged
+	default:
+		return 0
+	}
+}
+
+// FindChangesByBranch searches for merge requests
+// with the given branch as their source branch.
+// Only recent changes are returned, limited by the given limit.
+func (r *Repository) FindChangesByBranch(ctx context.Context, branch string, opts forge.FindChangesOptions) ([]*forge.FindChangeItem, error) {
+	if opts.Limit == 0 {
+		opts.Limit = 10
+	}
+
+	query := url.Values{
+		"source_branch": {branch},
+		"state":         {mergeRequestState(opts.State)},
+		"order_by":      {"updated_at"},
+		"sort":          {"desc"},
+		"per_page":      {strconv.Itoa(opts.Limit)},
+	}
+
+	var res []*mergeRequest
+	if err := r.client.Get(ctx, r.apiPath("merge_requests"), query, &res); err != nil {
+		return nil, fmt.Errorf("find changes by branch: %w", err)
+	}
+
+	changes := make([]*forge.FindChangeItem, len(res))
+	for i, mr := range res {
+		changes[i] = mr.toFindChangeItem()
+	}
+
+	return changes, nil
+}
+
+// FindChangeByID searches for a merge request with the given ID.
+func (r *Repository) FindChangeByID(ctx context.Context, id forge.ChangeID) (*forge.FindChangeItem, error) {
+	mr, err := r.mergeRequest(ctx, mustMR(id))
+	if err != nil {
+		return nil, fmt.Errorf("find change by ID: %w", err)
+	}
+
+	return mr.toFindChangeItem(), nil
+}
+
+// mergeRequest fetches information about a merge request.
+func (r *Repository) mergeRequest(ctx context.Context, id *MR) (*mergeRequest, error) {
+	var mr mergeRequest
+	if err := r.client.Get(ctx, r.mrPath(id), nil, &mr); err != nil {
+		return nil, fmt.Errorf("get merge request %v: %w", id, err)
+	}
+	return &mr, nil
+}
@@ -0,0 +1,167 @@
+// Package gitlab provides a wrapper around GitLab's APIs
+// in a manner compliant with the [forge.Forge] interface.
+package gitlab
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// Options defines command line options for the GitLab Forge.
+// These are all hidden in the CLI,
+// and are expected to be set only via environment variables.
+type Options struct {
+	// URL is the URL for GitLab.
+	// Override this for testing or self-hosted GitLab instances.
+	URL string `name:"gitlab-url" hidden:"" env:"GITLAB_URL" help:"Base URL for GitLab web requests"`
+
+	// APIURL is the URL for the GitLab API.
+	// Defaults to the /api/v4 path under URL.
+	APIURL string `name:"gitlab-api-url" hidden:"" env:"GITLAB_API_URL" help:"Base URL for GitLab API requests"`
+
+	// Token is a fixed token used to authenticate with GitLab.
+	// This may be used to skip the login flow.
+	Token string `name:"gitlab-token" hidden:"" env:"GITLAB_TOKEN" help:"GitLab API token"`
+}
+
+// Forge builds a GitLab Forge.
+type Forge struct {
+	Options Options
+
+	// Log specifies the logger to use.
+	Log *log.Logger
+}
+
+var _ forge.Forge = (*Forge)(nil)
+
+// URL returns the base URL configured for the GitLab Forge
+// or the default URL if none is set.
+func (f *Forge) URL() string {
+	return cmp.Or(f.Options.URL, "https://gitlab.com")
+}
+
+// APIURL returns the base API URL configured for the GitLab Forge
+// or the default URL if none is set.
+func (f *Forge) APIURL() string {
+	if f.Options.APIURL != "" {
+		return f.Options.APIURL
+	}
+	return strings.TrimSuffix(f.URL(), "/") + "/api/v4"
+}
+
+// ID reports a unique key for this forge.
+func (*Forge) ID() string { return "gitlab" }
+
+// CLIPlugin returns the CLI plugin for the GitLab Forge.
+func (f *Forge) CLIPlugin() any { return &f.Options }
+
+// MatchURL reports whether the given URL is a GitLab URL.
+func (f *Forge) MatchURL(remoteURL string) bool {
+	_, err := extractProjectPath(f.URL(), remoteURL)
+	return err == nil
+}
+
+// OpenURL opens a GitLab project from a remote URL.
+// Returns [forge.ErrUnsupportedURL] if the URL is not a valid GitLab URL.
+func (f *Forge) OpenURL(ctx context.Context, tok forge.AuthenticationToken, remoteURL string) (forge.Repository, error) {
+	if f.Log == nil {
+		f.Log = log.New(io.Discard)
+	}
+
+	path, err := extractProjectPath(f.URL(), remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", forge.ErrUnsupportedURL, err)
+	}
+
+	client, err := newClient(f.APIURL(), tok.(*AuthenticationToken).AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("create GitLab client: %w", err)
+	}
+
+	return newRepository(ctx, f, path, f.Log, client)
+}
+
+// extractProjectPath extracts the path of a project
+// (e.g. "group/subgroup/project") from a remote URL.
+func extractProjectPath(gitlabURL, remoteURL string) (string, error) {
+	baseURL, err := url.Parse(gitlabURL)
+	if err != nil {
+		return "", fmt.Errorf("bad base URL: %w", err)
+	}
+
+	// We recognize the following GitLab remote URL formats:
+	//
+	//	http(s)://gitlab.com/GROUP/PROJECT.git
+	//	git@gitlab.com:GROUP/PROJECT.git
+	//
+	// Projects may be nested in any number of subgroups.
+	// We can parse these all with url.Parse
+	// if we normalize the latter to:
+	//
+	//	ssh://git@gitlab.com/GROUP/PROJECT.git
+	if !hasGitProtocol(remoteURL) && strings.Contains(remoteURL, ":") {
+		// $user@$host:$path => ssh://$user@$host/$path
+		remoteURL = "ssh://" + strings.Replace(remoteURL, ":", "/", 1)
+	}
+
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", fmt.Errorf("parse remote URL: %w", err)
+	}
+
+	if !forge.MatchHost(u.Host, baseURL.Host) {
+		return "", fmt.Errorf("%v is not a GitLab URL: expected host %q", u, baseURL.Host)
+	}
+
+	s := u.Path                       // /GROUP/PROJECT.git/
+	s = strings.TrimPrefix(s, "/")    // GROUP/PROJECT.git/
+	s = strings.TrimSuffix(s, "/")    // GROUP/PROJECT.git
+	s = strings.TrimSuffix(s, ".git") // GROUP/PROJECT
+
+	// GitLab instances hosted under a path prefix
+	// (e.g. https://example.com/gitlab)
+	// include that prefix in HTTP remote URLs.
+	if prefix := strings.Trim(baseURL.Path, "/"); prefix != "" && u.Scheme != "ssh" {
+		s = strings.TrimPrefix(s, prefix+"/")
+	}
+
+	if !strings.Contains(s, "/") {
+		return "", fmt.Errorf("path %q does not contain a GitLab project", s)
+	}
+
+	return s, nil
+}
+
+// _gitProtocols is a list of known git protocols
+// including the :// suffix.
+var _gitProtocols = []string{
+	"ssh",
+	"git",
+	"git+ssh",
+	"git+https",
+	"git+http",
+	"https",
+	"http",
+}
+
+func init() {
+	for i, proto := range _gitProtocols {
+		_gitProtocols[i] = proto + "://"
+	}
+}
+
+func hasGitProtocol(url string) bool {
+	for _, proto := range _gitProtocols {
+		if strings.HasPrefix(url, proto) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractProjectPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		give      string
+		gitlabURL string
+
+		want string
+	}{
+		{
+			name: "https",
+			give: "https://gitlab.com/example/repo",
+			want: "example/repo",
+		},
+		{
+			name: "ssh",
+			give: "git@gitlab.com:example/repo",
+			want: "example/repo",
+		},
+		{
+			name: "ssh with git protocol",
+			give: "ssh://git@gitlab.com/example/repo",
+			want: "example/repo",
+		},
+		{
+			name: "https/.git/trailing slash",
+			give: "https://gitlab.com/example/repo.git/",
+			want: "example/repo",
+		},
+		{
+			name: "subgroups",
+			give: "git@gitlab.com:example/sub/group/repo.git",
+			want: "example/sub/group/repo",
+		},
+		{
+			name:      "https/custom URL",
+			give:      "https://example.com/example/repo",
+			gitlabURL: "https://example.com",
+			want:      "example/repo",
+		},
+		{
+			name:      "https/custom URL with path",
+			give:      "https://example.com/gitlab/example/repo.git",
+			gitlabURL: "https://example.com/gitlab",
+			want:      "example/repo",
+		},
+		{
+			name:      "ssh/custom URL with path",
+			give:      "git@example.com:example/repo.git",
+			gitlabURL: "https://example.com/gitlab",
+			want:      "example/repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Forge{Options: Options{URL: tt.gitlabURL}}
+			got, err := extractProjectPath(f.URL(), tt.give)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtractProjectPathErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		give      string
+		gitlabURL string
+
+		wantErr string
+	}{
+		{
+			name:    "not a GitLab URL",
+			give:    "https://github.com/example/repo",
+			wantErr: "not a GitLab URL",
+		},
+		{
+			name:    "no project",
+			give:    "https://gitlab.com/example",
+			wantErr: "does not contain a GitLab project",
+		},
+		{
+			name:      "bad base URL",
+			give:      "https://gitlab.com/example/repo",
+			gitlabURL: "://",
+			wantErr:   "bad base URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Forge{Options: Options{URL: tt.gitlabURL}}
+			_, err := extractProjectPath(f.URL(), tt.give)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestForgeAPIURL(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		var f Forge
+		assert.Equal(t, "https://gitlab.com/api/v4", f.APIURL())
+	})
+
+	t.Run("custom URL", func(t *testing.T) {
+		f := Forge{Options: Options{URL: "https://example.com/gitlab/"}}
+		assert.Equal(t, "https://example.com/gitlab/api/v4", f.APIURL())
+	})
+
+	t.Run("custom API URL", func(t *testing.T) {
+		f := Forge{Options: Options{
+			URL:    "https://example.com",
+			APIURL: "https://api.example.com",
+		}}
+		assert.Equal(t, "https://api.example.com", f.APIURL())
+	})
+}
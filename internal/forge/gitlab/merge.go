@@ -0,0 +1,40 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type acceptMergeRequestRequest struct {
+	MergeWhenPipelineSucceeds bool  `json:"merge_when_pipeline_succeeds"`
+	Squash                    *bool `json:"squash,omitempty"`
+}
+
+// EnqueueMerge sets a merge request to merge
+// once its pipeline succeeds.
+//
+// GitLab uses the merge method configured for the project,
+// so only squashing can be controlled with the merge method.
+// If the merge request has no running pipeline,
+// it's merged immediately.
+func (r *Repository) EnqueueMerge(ctx context.Context, fid forge.ChangeID, method forge.MergeMethod) (forge.EnqueueMergeResult, error) {
+	input := acceptMergeRequestRequest{
+		MergeWhenPipelineSucceeds: true,
+	}
+	switch method {
+	case forge.MergeMethodSquash:
+		squash := true
+		input.Squash = &squash
+	case forge.MergeMethodMerge, forge.MergeMethodRebase:
+		squash := false
+		input.Squash = &squash
+	}
+
+	if err := r.client.Put(ctx, r.mrPath(mustMR(fid), "merge"), input, nil); err != nil {
+		return forge.EnqueueMergeResult{}, fmt.Errorf("merge when pipeline succeeds: %w", err)
+	}
+
+	return forge.EnqueueMergeResult{AutoMerge: true}, nil
+}
@@ -0,0 +1,17 @@
+package gitlab
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeIsMerged reports whether a merge request has been merged.
+func (r *Repository) ChangeIsMerged(ctx context.Context, id forge.ChangeID) (bool, error) {
+	mr, err := r.mergeRequest(ctx, mustMR(id))
+	if err != nil {
+		return false, err
+	}
+
+	return mr.State == "merged", nil
+}
@@ -0,0 +1,51 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type milestone struct {
+	ID    int    `json:"id"`
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+}
+
+// FindMilestone looks up a milestone in the project
+// by its title or number.
+func (r *Repository) FindMilestone(ctx context.Context, name string) (*forge.Milestone, error) {
+	m, err := r.findMilestone(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &forge.Milestone{
+		Title:  m.Title,
+		Number: m.IID,
+	}, nil
+}
+
+func (r *Repository) findMilestone(ctx context.Context, name string) (*milestone, error) {
+	queries := []url.Values{{"title": {name}}}
+	if _, err := strconv.Atoi(name); err == nil {
+		// Prefer the milestone with that number,
+		// but a milestone may be titled with just a number.
+		queries = append([]url.Values{{"iids[]": {name}}}, queries...)
+	}
+
+	for _, query := range queries {
+		var res []*milestone
+		if err := r.client.Get(ctx, r.apiPath("milestones"), query, &res); err != nil {
+			return nil, fmt.Errorf("list milestones: %w", err)
+		}
+		if len(res) > 0 {
+			return res[0], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%q: %w", name, forge.ErrMilestoneNotFound)
+}
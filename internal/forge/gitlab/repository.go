@@ -0,0 +1,64 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// Repository is a GitLab project.
+type Repository struct {
+	path      string // e.g. "group/project"
+	projectID int
+	log       *log.Logger
+	client    *client
+	forge     *Forge
+}
+
+var _ forge.Repository = (*Repository)(nil)
+
+func newRepository(
+	ctx context.Context,
+	forge *Forge,
+	path string,
+	log *log.Logger,
+	client *client,
+) (*Repository, error) {
+	var res struct {
+		ID int `json:"id"`
+	}
+	if err := client.Get(ctx, "projects/"+url.PathEscape(path), nil, &res); err != nil {
+		return nil, fmt.Errorf("get project ID: %w", err)
+	}
+
+	return &Repository{
+		path:      path,
+		projectID: res.ID,
+		log:       log,
+		client:    client,
+		forge:     forge,
+	}, nil
+}
+
+// Forge returns the forge this repository belongs to.
+func (r *Repository) Forge() forge.Forge { return r.forge }
+
+// apiPath returns the path to an API endpoint
+// scoped to this project.
+func (r *Repository) apiPath(elems ...string) string {
+	p := "projects/" + strconv.Itoa(r.projectID)
+	for _, e := range elems {
+		p += "/" + e
+	}
+	return p
+}
+
+// mrPath returns the path to an API endpoint
+// scoped to a merge request in this project.
+func (r *Repository) mrPath(mr *MR, elems ...string) string {
+	return r.apiPath(append([]string{"merge_requests", strconv.Itoa(mr.Number)}, elems...)...)
+}
@@ -0,0 +1,232 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// fakeGitLab is a fake GitLab API server that serves canned responses
+// and records the requests made to it.
+type fakeGitLab struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	responses map[string]string // "METHOD path" -> JSON response
+	requests  map[string]string // "METHOD path" -> JSON request body
+	queries   map[string]string // "METHOD path" -> raw query
+	failures  map[string]int    // "METHOD path" -> HTTP status code
+}
+
+func newFakeGitLab(t *testing.T) (*fakeGitLab, *Forge) {
+	fake := &fakeGitLab{
+		t:         t,
+		responses: make(map[string]string),
+		requests:  make(map[string]string),
+		queries:   make(map[string]string),
+		failures:  make(map[string]int),
+	}
+	fake.respond("GET projects/example%2Frepo", `{"id": 7}`)
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	return fake, &Forge{
+		Options: Options{
+			URL:    "https://gitlab.example.com",
+			APIURL: srv.URL + "/api/v4",
+		},
+		Log: log.New(io.Discard),
+	}
+}
+
+func (f *fakeGitLab) respond(key, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[key] = body
+}
+
+// fail makes requests to key fail with the given HTTP status code.
+func (f *fakeGitLab) fail(key string, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[key] = status
+}
+
+func (f *fakeGitLab) request(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[key]
+}
+
+func (f *fakeGitLab) query(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries[key]
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(f.t, "Bearer token", r.Header.Get("Authorization"))
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/")
+	key := r.Method + " " + path
+
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(f.t, err)
+
+	f.mu.Lock()
+	f.requests[key] = string(body)
+	f.queries[key] = r.URL.RawQuery
+	res, ok := f.responses[key]
+	status, failed := f.failures[key]
+	f.mu.Unlock()
+
+	if failed {
+		http.Error(w, `{"message": "failed"}`, status)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	_, _ = io.WriteString(w, res)
+}
+
+func openTestRepository(t *testing.T, f *Forge) *Repository {
+	repo, err := f.OpenURL(context.Background(),
+		&AuthenticationToken{AccessToken: "token"},
+		"git@gitlab.example.com:example/repo.git")
+	require.NoError(t, err)
+	return repo.(*Repository)
+}
+
+func TestRepository_SubmitChange(t *testing.T) {
+	fake, f := newFakeGitLab(t)
+	fake.respond("POST projects/7/merge_requests", `{
+		"iid": 3,
+		"web_url": "https://gitlab.example.com/example/repo/-/merge_requests/3"
+	}`)
+	repo := openTestRepository(t, f)
+
+	res, err := repo.SubmitChange(context.Background(), forge.SubmitChangeRequest{
+		Subject: "Add feature",
+		Body:    "Adds a feature",
+		Base:    "main",
+		Head:    "feature",
+		Draft:   true,
+		Labels:  []string{"a", "b"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &MR{Number: 3}, res.ID)
+	assert.Equal(t, "https://gitlab.example.com/example/repo/-/merge_requests/3", res.URL)
+
+	assert.JSONEq(t, `{
+		"source_branch": "feature",
+		"target_branch": "main",
+		"title": "Draft: Add feature",
+		"description": "Adds a feature",
+		"labels": "a,b"
+	}`, fake.request("POST projects/7/merge_requests"))
+}
+
+func TestRepository_FindChangesByBranch(t *testing.T) {
+	fake, f := newFakeGitLab(t)
+	fake.respond("GET projects/7/merge_requests", `[{
+		"iid": 3,
+		"web_url": "https://gitlab.example.com/example/repo/-/merge_requests/3",
+		"title": "Draft: Add feature",
+		"state": "opened",
+		"sha": "abc123",
+		"target_branch": "main",
+		"draft": true
+	}]`)
+	repo := openTestRepository(t, f)
+
+	changes, err := repo.FindChangesByBranch(context.Background(), "feature", forge.FindChangesOptions{
+		State: forge.ChangeOpen,
+		Limit: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*forge.FindChangeItem{
+		{
+			ID:       &MR{Number: 3},
+			URL:      "https://gitlab.example.com/example/repo/-/merge_requests/3",
+			State:    forge.ChangeOpen,
+			Subject:  "Add feature",
+			HeadHash: "abc123",
+			BaseName: "main",
+			Draft:    true,
+		},
+	}, changes)
+
+	assert.Equal(t,
+		"order_by=updated_at&per_page=3&sort=desc&source_branch=feature&state=opened",
+		fake.query("GET projects/7/merge_requests"))
+}
+
+func TestRepository_EditChange(t *testing.T) {
+	fake, f := newFakeGitLab(t)
+	fake.respond("GET projects/7/merge_requests/3", `{
+		"iid": 3,
+		"title": "Draft: Add feature",
+		"draft": true,
+		"reviewers": [{"id": 10}]
+	}`)
+	fake.respond("GET users", `[{"id": 11, "username": "bob"}]`)
+	fake.respond("PUT projects/7/merge_requests/3", `{}`)
+	repo := openTestRepository(t, f)
+
+	draft := false
+	require.NoError(t, repo.EditChange(context.Background(), &MR{Number: 3}, forge.EditChangeOptions{
+		Base:         "develop",
+		Draft:        &draft,
+		AddLabels:    []string{"ready"},
+		AddReviewers: []string{"bob"},
+	}))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(fake.request("PUT projects/7/merge_requests/3")), &got))
+	assert.Equal(t, map[string]any{
+		"target_branch": "develop",
+		"title":         "Add feature",
+		"add_labels":    "ready",
+		"reviewer_ids":  []any{10.0, 11.0},
+	}, got)
+	assert.Equal(t, "username=bob", fake.query("GET users"))
+}
+
+func TestRepository_EditChange_conflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		conflict bool
+	}{
+		{name: "Conflict", status: http.StatusConflict, conflict: true},
+		{name: "ServerError", status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, f := newFakeGitLab(t)
+			fake.fail("PUT projects/7/merge_requests/3", tt.status)
+			repo := openTestRepository(t, f)
+
+			err := repo.EditChange(context.Background(), &MR{Number: 3}, forge.EditChangeOptions{
+				Base: "develop",
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.conflict, errors.Is(err, forge.ErrChangeConflict), "error: %v", err)
+		})
+	}
+}
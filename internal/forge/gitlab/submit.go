@@ -0,0 +1,60 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type createMergeRequestRequest struct {
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	Labels       string `json:"labels,omitempty"`
+	MilestoneID  int    `json:"milestone_id,omitempty"`
+	AssigneeIDs  []int  `json:"assignee_ids,omitempty"`
+}
+
+// SubmitChange creates a new merge request in a project.
+func (r *Repository) SubmitChange(ctx context.Context, req forge.SubmitChangeRequest) (forge.SubmitChangeResult, error) {
+	input := createMergeRequestRequest{
+		SourceBranch: req.Head,
+		TargetBranch: req.Base,
+		Title:        draftTitle(req.Subject, req.Draft),
+		Description:  req.Body,
+		// GitLab creates labels that don't exist yet.
+		Labels: strings.Join(req.Labels, ","),
+	}
+
+	// Failing to find the milestone shouldn't prevent
+	// the merge request from being created,
+	// so this only logs a warning.
+	if req.Milestone != "" {
+		m, err := r.findMilestone(ctx, req.Milestone)
+		if err != nil {
+			r.log.Warn("Could not set milestone", "milestone", req.Milestone, "error", err)
+		} else {
+			input.MilestoneID = m.ID
+		}
+	}
+	if len(req.Assignees) > 0 {
+		ids, err := r.userIDs(ctx, req.Assignees)
+		if err != nil {
+			r.log.Warn("Could not add assignees", "error", err)
+		}
+		input.AssigneeIDs = ids
+	}
+
+	var res mergeRequest
+	if err := r.client.Post(ctx, r.apiPath("merge_requests"), input, &res); err != nil {
+		return forge.SubmitChangeResult{}, fmt.Errorf("create merge request: %w", err)
+	}
+
+	return forge.SubmitChangeResult{
+		ID:  &MR{Number: res.IID},
+		URL: res.WebURL,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeTemplatePaths reports the allowed paths for possible MR templates.
+//
+// GitLab allows any number of templates
+// in the .gitlab/merge_request_templates directory.
+// Only the default template is reported here
+// as the paths of the others can't be known ahead of time.
+//
+// Ref https://docs.gitlab.com/ee/user/project/description_templates.html.
+func (f *Forge) ChangeTemplatePaths() []string {
+	return []string{
+		".gitlab/merge_request_templates/Default.md",
+	}
+}
+
+// ListChangeTemplates returns MR templates defined in the project.
+func (r *Repository) ListChangeTemplates(ctx context.Context) ([]*forge.ChangeTemplate, error) {
+	var names []struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	}
+	if err := r.client.Get(ctx, r.apiPath("templates", "merge_requests"), nil, &names); err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+
+	out := make([]*forge.ChangeTemplate, 0, len(names))
+	for _, n := range names {
+		var t struct {
+			Content string `json:"content"`
+		}
+		path := r.apiPath("templates", "merge_requests", url.PathEscape(n.Key))
+		if err := r.client.Get(ctx, path, nil, &t); err != nil {
+			return nil, fmt.Errorf("get template %q: %w", n.Name, err)
+		}
+
+		if t.Content != "" {
+			out = append(out, &forge.ChangeTemplate{
+				Filename: n.Name + ".md",
+				Body:     t.Content,
+			})
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type user struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// CurrentUser reports the user authenticated against GitLab.
+func (r *Repository) CurrentUser(ctx context.Context) (forge.User, error) {
+	var u user
+	if err := r.client.Get(ctx, "user", nil, &u); err != nil {
+		return forge.User{}, fmt.Errorf("get current user: %w", err)
+	}
+
+	return forge.User{
+		Login: u.Username,
+		ID:    strconv.Itoa(u.ID),
+		Name:  u.Name,
+	}, nil
+}
+
+// userIDs resolves the IDs of the users with the given usernames.
+//
+// GitLab only allows requesting reviews from users,
+// so teams in the form "org/team" are rejected.
+func (r *Repository) userIDs(ctx context.Context, names []string) ([]int, error) {
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		if strings.Contains(name, "/") {
+			return nil, fmt.Errorf("cannot request reviews from group %q: GitLab only supports users", name)
+		}
+
+		var res []*user
+		if err := r.client.Get(ctx, "users", url.Values{"username": {name}}, &res); err != nil {
+			return nil, fmt.Errorf("query user %q: %w", name, err)
+		}
+		if len(res) == 0 {
+			return nil, fmt.Errorf("user not found: %q", name)
+		}
+		ids = append(ids, res[0].ID)
+	}
+
+	return ids, nil
+}
@@ -63,6 +63,44 @@ type shamUser struct {
 	Username string
 }
 
+type userResponse struct {
+	Login string `json:"login"`
+}
+
+var _ = shamhubHandler("GET /user", (*ShamHub).handleUser)
+
+func (sh *ShamHub) handleUser(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authentication-Token")
+
+	sh.mu.RLock()
+	username, ok := sh.tokens[token]
+	sh.mu.RUnlock()
+	if !ok {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(userResponse{Login: username}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (f *forgeRepository) CurrentUser(ctx context.Context) (forge.User, error) {
+	u := f.apiURL.JoinPath("user")
+	var res userResponse
+	if err := f.client.Get(ctx, u.String(), &res); err != nil {
+		return forge.User{}, fmt.Errorf("get user: %w", err)
+	}
+
+	// ShamHub users don't have IDs or display names.
+	return forge.User{
+		Login: res.Login,
+		ID:    res.Login,
+	}, nil
+}
+
 // RegisterUser registers a new user against the Forge
 // with the given username and password.
 func (sh *ShamHub) RegisterUser(username string) error {
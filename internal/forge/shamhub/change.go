@@ -65,6 +65,37 @@ type shamChange struct {
 
 	Base string
 	Head string
+
+	// Checks is the combined state of checks
+	// reported against the head of the change.
+	Checks forge.ChecksState
+
+	// Labels attached to the change.
+	Labels []string
+
+	// Reviewers requested on the change.
+	Reviewers []string
+
+	// Assignees of the change.
+	Assignees []string
+
+	// Milestone is the title of the milestone
+	// that the change is attached to, if any.
+	Milestone string
+
+	// AutoMerge is set if the change will be merged automatically
+	// with AutoMergeMethod once it's ready.
+	AutoMerge       bool
+	AutoMergeMethod string
+
+	// EditConflicts is the number of upcoming edits
+	// to the base of the change that will be rejected
+	// as if the change was modified concurrently.
+	EditConflicts int
+
+	// ConflictBase, if set, is the base that the change is moved to
+	// when an edit is rejected because of EditConflicts.
+	ConflictBase string
 }
 
 // Change is a change proposal against a repository.
@@ -76,8 +107,17 @@ type Change struct {
 	State  string `json:"state"`
 	Merged bool   `json:"merged,omitempty"`
 
-	Subject string `json:"title"`
-	Body    string `json:"body"`
+	Subject string   `json:"title"`
+	Body    string   `json:"body"`
+	Labels  []string `json:"labels,omitempty"`
+
+	Reviewers []string `json:"reviewers,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+
+	Milestone string `json:"milestone,omitempty"`
+
+	AutoMerge       bool   `json:"auto_merge,omitempty"`
+	AutoMergeMethod string `json:"auto_merge_method,omitempty"`
 
 	Base *ChangeBranch `json:"base"`
 	Head *ChangeBranch `json:"head"`
@@ -100,8 +140,16 @@ func (sh *ShamHub) toChange(c shamChange) (*Change, error) {
 		Draft:   c.Draft,
 		Subject: c.Subject,
 		Body:    c.Body,
+		Labels:  c.Labels,
 		Base:    base,
 		Head:    head,
+
+		Reviewers: c.Reviewers,
+		Assignees: c.Assignees,
+		Milestone: c.Milestone,
+
+		AutoMerge:       c.AutoMerge,
+		AutoMergeMethod: c.AutoMergeMethod,
 	}
 	switch c.State {
 	case shamChangeOpen:
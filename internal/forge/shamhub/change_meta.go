@@ -10,8 +10,9 @@ import (
 
 // ChangeMetadata records the metadata for a change on a ShamHub server.
 type ChangeMetadata struct {
-	Number       int `json:"number"`
-	StackComment int `json:"stack_comment"`
+	Number        int `json:"number"`
+	StackComment  int `json:"stack_comment"`
+	CustomComment int `json:"custom_comment,omitempty"`
 }
 
 // ForgeID reports the forge ID that owns this metadata.
@@ -42,6 +43,24 @@ func (m *ChangeMetadata) SetStackCommentID(id forge.ChangeCommentID) {
 	}
 }
 
+// CustomCommentID reports the comment ID of the custom comment.
+func (m *ChangeMetadata) CustomCommentID() forge.ChangeCommentID {
+	if m.CustomComment == 0 {
+		return nil
+	}
+	return ChangeCommentID(m.CustomComment)
+}
+
+// SetCustomCommentID sets the comment ID of the custom comment.
+// id may be nil.
+func (m *ChangeMetadata) SetCustomCommentID(id forge.ChangeCommentID) {
+	if id == nil {
+		m.CustomComment = 0
+	} else {
+		m.CustomComment = int(id.(ChangeCommentID))
+	}
+}
+
 // NewChangeMetadata returns the metadata for a change on a ShamHub server.
 func (f *forgeRepository) NewChangeMetadata(ctx context.Context, id forge.ChangeID) (forge.ChangeMetadata, error) {
 	return &ChangeMetadata{
@@ -0,0 +1,106 @@
+package shamhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// SetChangeChecksRequest is a request to set the state of checks
+// reported against a change.
+type SetChangeChecksRequest struct {
+	Owner, Repo string
+	Number      int
+
+	State forge.ChecksState
+}
+
+// SetChangeChecks sets the combined state of checks
+// reported against a change.
+func (sh *ShamHub) SetChangeChecks(req SetChangeChecksRequest) error {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for idx, change := range sh.changes {
+		if change.Owner == req.Owner && change.Repo == req.Repo && change.Number == req.Number {
+			sh.changes[idx].Checks = req.State
+			return nil
+		}
+	}
+
+	return fmt.Errorf("change %d not found", req.Number)
+}
+
+// parseChecksState parses the string form of a forge.ChecksState.
+func parseChecksState(s string) (forge.ChecksState, error) {
+	for _, state := range []forge.ChecksState{
+		forge.ChecksNone,
+		forge.ChecksPending,
+		forge.ChecksPassing,
+		forge.ChecksFailing,
+	} {
+		if state.String() == s {
+			return state, nil
+		}
+	}
+	return forge.ChecksNone, fmt.Errorf("unknown checks state: %q", s)
+}
+
+type changeChecksResponse struct {
+	State string `json:"state"`
+}
+
+var _ = shamhubHandler("GET /{owner}/{repo}/change/{number}/checks", (*ShamHub).handleChangeChecks)
+
+func (sh *ShamHub) handleChangeChecks(w http.ResponseWriter, r *http.Request) {
+	owner, repo, numStr := r.PathValue("owner"), r.PathValue("repo"), r.PathValue("number")
+	if owner == "" || repo == "" || numStr == "" {
+		http.Error(w, "owner, repo, and number are required", http.StatusBadRequest)
+		return
+	}
+
+	num, err := strconv.Atoi(numStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sh.mu.RLock()
+	var (
+		checks forge.ChecksState
+		found  bool
+	)
+	for _, c := range sh.changes {
+		if c.Owner == owner && c.Repo == repo && c.Number == num {
+			checks = c.Checks
+			found = true
+			break
+		}
+	}
+	sh.mu.RUnlock()
+
+	if !found {
+		http.Error(w, "change not found", http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(changeChecksResponse{State: checks.String()}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (f *forgeRepository) ChangeChecksState(ctx context.Context, fid forge.ChangeID) (forge.ChecksState, error) {
+	id := fid.(ChangeID)
+	u := f.apiURL.JoinPath(f.owner, f.repo, "change", strconv.Itoa(int(id)), "checks")
+	var res changeChecksResponse
+	if err := f.client.Get(ctx, u.String(), &res); err != nil {
+		return forge.ChecksNone, fmt.Errorf("get checks: %w", err)
+	}
+	return parseChecksState(res.State)
+}
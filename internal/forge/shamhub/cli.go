@@ -157,6 +157,83 @@ func (c *Cmd) Run(ts *testscript.TestScript, neg bool, args []string) {
 
 		ts.Check(sh.MergeChange(req))
 
+	case "checks":
+		if len(args) != 3 {
+			ts.Fatalf("usage: shamhub checks <owner/repo> <pr> <state>")
+		}
+		if sh == nil {
+			ts.Fatalf("ShamHub not initialized")
+		}
+
+		ownerRepo, prStr, stateStr := args[0], args[1], args[2]
+		owner, repo, ok := strings.Cut(ownerRepo, "/")
+		if !ok {
+			ts.Fatalf("invalid owner/repo: %s", ownerRepo)
+		}
+		pr, err := strconv.Atoi(prStr)
+		if err != nil {
+			ts.Fatalf("invalid PR number: %s", err)
+		}
+		state, err := parseChecksState(stateStr)
+		if err != nil {
+			ts.Fatalf("invalid checks state: %s", err)
+		}
+
+		ts.Check(sh.SetChangeChecks(SetChangeChecksRequest{
+			Owner:  owner,
+			Repo:   repo,
+			Number: pr,
+			State:  state,
+		}))
+
+	case "conflict":
+		if len(args) != 3 && len(args) != 4 {
+			ts.Fatalf("usage: shamhub conflict <owner/repo> <pr> <count> [<base>]")
+		}
+		if sh == nil {
+			ts.Fatalf("ShamHub not initialized")
+		}
+
+		ownerRepo, prStr, countStr := args[0], args[1], args[2]
+		owner, repo, ok := strings.Cut(ownerRepo, "/")
+		if !ok {
+			ts.Fatalf("invalid owner/repo: %s", ownerRepo)
+		}
+		pr, err := strconv.Atoi(prStr)
+		if err != nil {
+			ts.Fatalf("invalid PR number: %s", err)
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			ts.Fatalf("invalid count: %s", err)
+		}
+
+		req := SetEditConflictsRequest{
+			Owner:  owner,
+			Repo:   repo,
+			Number: pr,
+			Count:  count,
+		}
+		if len(args) == 4 {
+			req.Base = args[3]
+		}
+		ts.Check(sh.SetEditConflicts(req))
+
+	case "milestone":
+		if len(args) != 2 {
+			ts.Fatalf("usage: shamhub milestone <owner/repo> <title>")
+		}
+		if sh == nil {
+			ts.Fatalf("ShamHub not initialized")
+		}
+
+		ownerRepo, title := args[0], args[1]
+		owner, repo, ok := strings.Cut(ownerRepo, "/")
+		if !ok {
+			ts.Fatalf("invalid owner/repo: %s", ownerRepo)
+		}
+		ts.Check(sh.CreateMilestone(owner, repo, title))
+
 	case "register":
 		if len(args) != 1 {
 			ts.Fatalf("usage: shamhub register <username>")
@@ -0,0 +1,68 @@
+package shamhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type closeChangeRequest struct{}
+
+type closeChangeResponse struct{}
+
+var _ = shamhubHandler("POST /{owner}/{repo}/change/{number}/close", (*ShamHub).handleCloseChange)
+
+func (sh *ShamHub) handleCloseChange(w http.ResponseWriter, r *http.Request) {
+	owner, repo, numStr := r.PathValue("owner"), r.PathValue("repo"), r.PathValue("number")
+	if owner == "" || repo == "" || numStr == "" {
+		http.Error(w, "owner, repo, and number are required", http.StatusBadRequest)
+		return
+	}
+
+	num, err := strconv.Atoi(numStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	changeIdx := -1
+	for idx, c := range sh.changes {
+		if c.Owner == owner && c.Repo == repo && c.Number == num {
+			changeIdx = idx
+			break
+		}
+	}
+	if changeIdx == -1 {
+		http.Error(w, "change not found", http.StatusNotFound)
+		return
+	}
+	if sh.changes[changeIdx].State != shamChangeOpen {
+		http.Error(w, "change is not open", http.StatusBadRequest)
+		return
+	}
+
+	sh.changes[changeIdx].State = shamChangeClosed
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(closeChangeResponse{}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (f *forgeRepository) CloseChange(ctx context.Context, fid forge.ChangeID) error {
+	id := fid.(ChangeID)
+	u := f.apiURL.JoinPath(f.owner, f.repo, "change", strconv.Itoa(int(id)), "close")
+	var res closeChangeResponse
+	if err := f.client.Post(ctx, u.String(), closeChangeRequest{}, &res); err != nil {
+		return fmt.Errorf("close change: %w", err)
+	}
+	return nil
+}
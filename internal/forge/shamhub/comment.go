@@ -52,6 +52,7 @@ type shamComment struct {
 var (
 	_ = shamhubHandler("POST /{owner}/{repo}/comments", (*ShamHub).handlePostChangeComment)
 	_ = shamhubHandler("PATCH /{owner}/{repo}/comments/{id}", (*ShamHub).handleUpdateChangeComment)
+	_ = shamhubHandler("GET /{owner}/{repo}/comments/{id}", (*ShamHub).handleGetChangeComment)
 )
 
 type postCommentRequest struct {
@@ -165,6 +166,49 @@ func (sh *ShamHub) handleUpdateChangeComment(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+type getCommentResponse struct {
+	Body string `json:"body"`
+}
+
+func (sh *ShamHub) handleGetChangeComment(w http.ResponseWriter, r *http.Request) {
+	owner, repo, idStr := r.PathValue("owner"), r.PathValue("repo"), r.PathValue("id")
+	if owner == "" || repo == "" || idStr == "" {
+		http.Error(w, "owner, repo, and id are required", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sh.mu.RLock()
+	var (
+		res   getCommentResponse
+		found bool
+	)
+	for _, c := range sh.comments {
+		if c.ID == id {
+			found = true
+			res.Body = c.Body
+			break
+		}
+	}
+	sh.mu.RUnlock()
+
+	if !found {
+		http.Error(w, "comment not found", http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (r *forgeRepository) PostChangeComment(
 	ctx context.Context,
 	id forge.ChangeID,
@@ -199,3 +243,17 @@ func (r *forgeRepository) UpdateChangeComment(
 
 	return nil
 }
+
+func (r *forgeRepository) ChangeComment(
+	ctx context.Context,
+	id forge.ChangeCommentID,
+) (string, error) {
+	cid := int(id.(ChangeCommentID))
+	u := r.apiURL.JoinPath(r.owner, r.repo, "comments", strconv.Itoa(cid))
+	var res getCommentResponse
+	if err := r.client.Get(ctx, u.String(), &res); err != nil {
+		return "", fmt.Errorf("get comment: %w", err)
+	}
+
+	return res.Body, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"go.abhg.dev/gs/internal/forge"
@@ -13,10 +14,52 @@ import (
 type editChangeRequest struct {
 	Base  *string `json:"base,omitempty"`
 	Draft *bool   `json:"draft,omitempty"`
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+
+	AddLabels    []string `json:"add_labels,omitempty"`
+	RemoveLabels []string `json:"remove_labels,omitempty"`
+
+	AddReviewers []string `json:"add_reviewers,omitempty"`
+	AddAssignees []string `json:"add_assignees,omitempty"`
+
+	Milestone string `json:"milestone,omitempty"`
 }
 
 type editChangeResponse struct{}
 
+// SetEditConflictsRequest is a request to reject upcoming edits
+// to the base of a change.
+type SetEditConflictsRequest struct {
+	Owner, Repo string
+	Number      int
+
+	// Count is the number of edits to reject.
+	Count int
+
+	// Base, if set, is the base that the change is moved to
+	// when an edit is rejected,
+	// as if someone else changed it concurrently.
+	Base string
+}
+
+// SetEditConflicts makes the next few edits to the base of a change
+// fail as if the change was modified concurrently.
+func (sh *ShamHub) SetEditConflicts(req SetEditConflictsRequest) error {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for idx, change := range sh.changes {
+		if change.Owner == req.Owner && change.Repo == req.Repo && change.Number == req.Number {
+			sh.changes[idx].EditConflicts = req.Count
+			sh.changes[idx].ConflictBase = req.Base
+			return nil
+		}
+	}
+
+	return fmt.Errorf("change %d not found", req.Number)
+}
+
 var _ = shamhubHandler("PATCH /{owner}/{repo}/change/{number}", (*ShamHub).handleEditChange)
 
 func (sh *ShamHub) handleEditChange(w http.ResponseWriter, r *http.Request) {
@@ -56,11 +99,56 @@ func (sh *ShamHub) handleEditChange(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if b := data.Base; b != nil {
+		if change := &sh.changes[changeIdx]; change.EditConflicts > 0 {
+			change.EditConflicts--
+			if change.ConflictBase != "" {
+				change.Base = change.ConflictBase
+			}
+			http.Error(w, "change was modified concurrently", http.StatusConflict)
+			return
+		}
+
 		sh.changes[changeIdx].Base = *b
 	}
+	if name := data.Milestone; name != "" {
+		m, ok := sh.findMilestone(owner, repo, name)
+		if !ok {
+			http.Error(w, "milestone not found", http.StatusBadRequest)
+			return
+		}
+		sh.changes[changeIdx].Milestone = m.Title
+	}
 	if d := data.Draft; d != nil {
 		sh.changes[changeIdx].Draft = *d
 	}
+	if t := data.Title; t != nil {
+		sh.changes[changeIdx].Subject = *t
+	}
+	if b := data.Body; b != nil {
+		sh.changes[changeIdx].Body = *b
+	}
+	for _, label := range data.AddLabels {
+		if !slices.Contains(sh.changes[changeIdx].Labels, label) {
+			sh.changes[changeIdx].Labels = append(sh.changes[changeIdx].Labels, label)
+		}
+	}
+	if len(data.RemoveLabels) > 0 {
+		sh.changes[changeIdx].Labels = slices.DeleteFunc(sh.changes[changeIdx].Labels, func(label string) bool {
+			return slices.Contains(data.RemoveLabels, label)
+		})
+	}
+
+	for _, reviewer := range data.AddReviewers {
+		if !slices.Contains(sh.changes[changeIdx].Reviewers, reviewer) {
+			sh.changes[changeIdx].Reviewers = append(sh.changes[changeIdx].Reviewers, reviewer)
+		}
+	}
+
+	for _, assignee := range data.AddAssignees {
+		if !slices.Contains(sh.changes[changeIdx].Assignees, assignee) {
+			sh.changes[changeIdx].Assignees = append(sh.changes[changeIdx].Assignees, assignee)
+		}
+	}
 
 	res := editChangeResponse{} // empty for now
 
@@ -79,9 +167,27 @@ func (f *forgeRepository) EditChange(ctx context.Context, fid forge.ChangeID, op
 	if opts.Draft != nil {
 		req.Draft = opts.Draft
 	}
+	req.Title = opts.Title
+	req.Body = opts.Body
+	req.AddLabels = opts.AddLabels
+	req.RemoveLabels = opts.RemoveLabels
+	req.AddReviewers = opts.AddReviewers
+	req.Milestone = opts.Milestone
 
 	id := fid.(ChangeID)
 	u := f.apiURL.JoinPath(f.owner, f.repo, "change", strconv.Itoa(int(id)))
+
+	// Assignees are only added if there aren't any yet.
+	if len(opts.AddAssignees) > 0 {
+		var change Change
+		if err := f.client.Get(ctx, u.String(), &change); err != nil {
+			return fmt.Errorf("get change: %w", err)
+		}
+		if len(change.Assignees) == 0 {
+			req.AddAssignees = opts.AddAssignees
+		}
+	}
+
 	var res editChangeResponse
 	if err := f.client.Patch(ctx, u.String(), req, &res); err != nil {
 		return fmt.Errorf("edit change: %w", err)
@@ -142,14 +142,7 @@ func (f *forgeRepository) FindChangeByID(ctx context.Context, fid forge.ChangeID
 		return nil, fmt.Errorf("find change by ID: %w", err)
 	}
 
-	return &forge.FindChangeItem{
-		ID:       ChangeID(res.Number),
-		URL:      res.URL,
-		Subject:  res.Subject,
-		HeadHash: git.Hash(res.Head.Hash),
-		BaseName: res.Base.Name,
-		Draft:    res.Draft,
-	}, nil
+	return res.findChangeItem(), nil
 }
 
 func (f *forgeRepository) FindChangesByBranch(ctx context.Context, branch string, opts forge.FindChangesOptions) ([]*forge.FindChangeItem, error) {
@@ -174,27 +167,36 @@ func (f *forgeRepository) FindChangesByBranch(ctx context.Context, branch string
 
 	changes := make([]*forge.FindChangeItem, len(res))
 	for i, c := range res {
-		var state forge.ChangeState
-		switch c.State {
-		case "open":
-			state = forge.ChangeOpen
-		case "closed":
-			if c.Merged {
-				state = forge.ChangeMerged
-			} else {
-				state = forge.ChangeClosed
-			}
-		}
+		changes[i] = c.findChangeItem()
+	}
+	return changes, nil
+}
 
-		changes[i] = &forge.FindChangeItem{
-			ID:       ChangeID(c.Number),
-			URL:      c.URL,
-			State:    state,
-			Subject:  c.Subject,
-			HeadHash: git.Hash(c.Head.Hash),
-			BaseName: c.Base.Name,
-			Draft:    c.Draft,
+func (c *Change) findChangeItem() *forge.FindChangeItem {
+	var state forge.ChangeState
+	switch c.State {
+	case "open":
+		state = forge.ChangeOpen
+	case "closed":
+		if c.Merged {
+			state = forge.ChangeMerged
+		} else {
+			state = forge.ChangeClosed
 		}
 	}
-	return changes, nil
+
+	return &forge.FindChangeItem{
+		ID:       ChangeID(c.Number),
+		URL:      c.URL,
+		State:    state,
+		Subject:  c.Subject,
+		Body:     c.Body,
+		HeadHash: git.Hash(c.Head.Hash),
+		BaseName: c.Base.Name,
+		Draft:    c.Draft,
+		Labels:   c.Labels,
+
+		Assignees: c.Assignees,
+		Milestone: c.Milestone,
+	}
 }
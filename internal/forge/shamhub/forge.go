@@ -108,7 +108,12 @@ func (c *jsonHTTPClient) do(ctx context.Context, method, url string, req, res an
 		return fmt.Errorf("read response body: %w", err)
 	}
 
-	if httpResp.StatusCode != http.StatusOK {
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+		// ok
+	case http.StatusConflict:
+		return fmt.Errorf("%w\nbody: %s", forge.ErrChangeConflict, resBody)
+	default:
 		return fmt.Errorf("unexpected status code: %d\nbody: %s", httpResp.StatusCode, resBody)
 	}
 
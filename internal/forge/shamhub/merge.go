@@ -71,6 +71,80 @@ func (f *forgeRepository) ChangeIsMerged(ctx context.Context, fid forge.ChangeID
 	return res.Merged, nil
 }
 
+type enqueueMergeRequest struct {
+	Method string `json:"method,omitempty"`
+}
+
+type enqueueMergeResponse struct{}
+
+var _ = shamhubHandler("POST /{owner}/{repo}/change/{number}/auto-merge", (*ShamHub).handleEnqueueMerge)
+
+// handleEnqueueMerge enables auto-merge for a change.
+// ShamHub doesn't have merge queues,
+// and it never actually merges changes on its own.
+func (sh *ShamHub) handleEnqueueMerge(w http.ResponseWriter, r *http.Request) {
+	owner, repo, numStr := r.PathValue("owner"), r.PathValue("repo"), r.PathValue("number")
+	if owner == "" || repo == "" || numStr == "" {
+		http.Error(w, "owner, repo, and number are required", http.StatusBadRequest)
+		return
+	}
+
+	num, err := strconv.Atoi(numStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req enqueueMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	changeIdx := -1
+	for idx, c := range sh.changes {
+		if c.Owner == owner && c.Repo == repo && c.Number == num {
+			changeIdx = idx
+			break
+		}
+	}
+	if changeIdx == -1 {
+		http.Error(w, "change not found", http.StatusNotFound)
+		return
+	}
+	if sh.changes[changeIdx].State != shamChangeOpen {
+		http.Error(w, "change is not open", http.StatusBadRequest)
+		return
+	}
+
+	sh.changes[changeIdx].AutoMerge = true
+	sh.changes[changeIdx].AutoMergeMethod = req.Method
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(enqueueMergeResponse{}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (f *forgeRepository) EnqueueMerge(ctx context.Context, fid forge.ChangeID, method forge.MergeMethod) (forge.EnqueueMergeResult, error) {
+	var req enqueueMergeRequest
+	if method != forge.MergeMethodDefault {
+		req.Method = method.String()
+	}
+
+	id := fid.(ChangeID)
+	u := f.apiURL.JoinPath(f.owner, f.repo, "change", strconv.Itoa(int(id)), "auto-merge")
+	var res enqueueMergeResponse
+	if err := f.client.Post(ctx, u.String(), req, &res); err != nil {
+		return forge.EnqueueMergeResult{}, fmt.Errorf("enable auto-merge: %w", err)
+	}
+	return forge.EnqueueMergeResult{AutoMerge: true}, nil
+}
+
 // MergeChangeRequest is a request to merge an open change
 // proposed against this forge.
 type MergeChangeRequest struct {
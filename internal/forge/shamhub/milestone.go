@@ -0,0 +1,111 @@
+package shamhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+type shamMilestone struct {
+	Owner string
+	Repo  string
+
+	Number int
+	Title  string
+}
+
+// CreateMilestone creates a milestone with the given title
+// in a repository.
+func (sh *ShamHub) CreateMilestone(owner, repo, title string) error {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if _, ok := sh.findMilestone(owner, repo, title); ok {
+		return fmt.Errorf("milestone %q already exists", title)
+	}
+
+	sh.milestones = append(sh.milestones, shamMilestone{
+		Owner:  owner,
+		Repo:   repo,
+		Number: len(sh.milestones) + 1,
+		Title:  title,
+	})
+	return nil
+}
+
+// findMilestone looks up a milestone by title or number.
+// The caller must hold the lock.
+func (sh *ShamHub) findMilestone(owner, repo, name string) (shamMilestone, bool) {
+	num, err := strconv.Atoi(name)
+	hasNum := err == nil
+	for _, m := range sh.milestones {
+		if m.Owner != owner || m.Repo != repo {
+			continue
+		}
+		if strings.EqualFold(m.Title, name) || (hasNum && m.Number == num) {
+			return m, true
+		}
+	}
+	return shamMilestone{}, false
+}
+
+type milestonesResponse []*milestone
+
+type milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+var _ = shamhubHandler("GET /{owner}/{repo}/milestones", (*ShamHub).handleListMilestones)
+
+func (sh *ShamHub) handleListMilestones(w http.ResponseWriter, r *http.Request) {
+	owner, repo := r.PathValue("owner"), r.PathValue("repo")
+	if owner == "" || repo == "" {
+		http.Error(w, "owner and repo are required", http.StatusBadRequest)
+		return
+	}
+
+	sh.mu.RLock()
+	res := milestonesResponse{} // non-nil so that it's encoded as []
+	for _, m := range sh.milestones {
+		if m.Owner == owner && m.Repo == repo {
+			res = append(res, &milestone{
+				Number: m.Number,
+				Title:  m.Title,
+			})
+		}
+	}
+	sh.mu.RUnlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (f *forgeRepository) FindMilestone(ctx context.Context, name string) (*forge.Milestone, error) {
+	u := f.apiURL.JoinPath(f.owner, f.repo, "milestones")
+	var res milestonesResponse
+	if err := f.client.Get(ctx, u.String(), &res); err != nil {
+		return nil, fmt.Errorf("list milestones: %w", err)
+	}
+
+	num, err := strconv.Atoi(name)
+	hasNum := err == nil
+	for _, m := range res {
+		if strings.EqualFold(m.Title, name) || (hasNum && m.Number == num) {
+			return &forge.Milestone{
+				Title:  m.Title,
+				Number: m.Number,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%q: %w", name, forge.ErrMilestoneNotFound)
+}
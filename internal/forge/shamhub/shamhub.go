@@ -40,6 +40,8 @@ type ShamHub struct {
 	users    []shamUser    // all users
 	comments []shamComment // all comments
 
+	milestones []shamMilestone // all milestones
+
 	tokens map[string]string // token -> username
 }
 
@@ -15,6 +15,10 @@ type submitChangeRequest struct {
 	Base    string `json:"base,omitempty"`
 	Head    string `json:"head,omitempty"`
 	Draft   bool   `json:"draft,omitempty"`
+
+	Labels    []string `json:"labels,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
 }
 
 type submitChangeResponse struct {
@@ -40,6 +44,17 @@ func (sh *ShamHub) handleSubmitChange(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sh.mu.Lock()
+	var milestone string
+	if data.Milestone != "" {
+		m, ok := sh.findMilestone(owner, repo, data.Milestone)
+		if !ok {
+			sh.mu.Unlock()
+			http.Error(w, "milestone not found", http.StatusBadRequest)
+			return
+		}
+		milestone = m.Title
+	}
+
 	change := shamChange{
 		// We'll just use a global counter for the change number for now.
 		// We can scope it by owner/repo if needed.
@@ -51,6 +66,10 @@ func (sh *ShamHub) handleSubmitChange(w http.ResponseWriter, r *http.Request) {
 		Body:    data.Body,
 		Base:    data.Base,
 		Head:    data.Head,
+		Labels:  data.Labels,
+
+		Milestone: milestone,
+		Assignees: data.Assignees,
 	}
 	sh.changes = append(sh.changes, change)
 	sh.mu.Unlock()
@@ -74,6 +93,10 @@ func (f *forgeRepository) SubmitChange(ctx context.Context, r forge.SubmitChange
 		Body:    r.Body,
 		Head:    r.Head,
 		Draft:   r.Draft,
+		Labels:  r.Labels,
+
+		Milestone: r.Milestone,
+		Assignees: r.Assignees,
 	}
 
 	u := f.apiURL.JoinPath(f.owner, f.repo, "changes")
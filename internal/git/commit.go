@@ -1,12 +1,11 @@
 package git
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -185,48 +184,27 @@ func (m CommitMessage) String() string {
 	return m.Subject
 }
 
-// CommitMessageRange returns the commit messages in the range (start, ^stop).
-// That is, all commits reachable from start but not from stop.
-func (r *Repository) CommitMessageRange(ctx context.Context, start, stop string) ([]CommitMessage, error) {
-	cmd := r.gitCmd(ctx, "rev-list",
-		"--no-commit-header",
-		"--format=%B%x00", // null-byte separated
-		start, "--not", stop, "--",
-	)
-	out, err := cmd.StdoutPipe()
+// CommitMessageRange returns the commit messages
+// of commits matched by the given range.
+func (r *Repository) CommitMessageRange(ctx context.Context, commits CommitRange) ([]CommitMessage, error) {
+	details, err := r.ListCommitsDetails(ctx, commits)
 	if err != nil {
-		return nil, fmt.Errorf("pipe: %w", err)
+		return nil, err
 	}
 
-	if err := cmd.Start(r.exec); err != nil {
-		return nil, fmt.Errorf("start rev-list: %w", err)
-	}
-
-	scanner := bufio.NewScanner(out)
-	scanner.Split(splitNullByte)
-
-	var bodies []CommitMessage
-	for scanner.Scan() {
-		raw := strings.TrimSpace(scanner.Text())
-		if len(raw) == 0 {
-			continue
-		}
-		subject, body, _ := strings.Cut(raw, "\n")
-		bodies = append(bodies, CommitMessage{
-			Subject: strings.TrimSpace(subject),
-			Body:    strings.TrimSpace(body),
-		})
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scan: %w", err)
+	msgs := make([]CommitMessage, len(details))
+	for i, c := range details {
+		msgs[i] = c.Message()
 	}
+	return msgs, nil
+}
 
-	if err := cmd.Wait(r.exec); err != nil {
-		return nil, fmt.Errorf("rev-list: %w", err)
+func parseUnixTime(s string) (time.Time, error) {
+	epoch, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad timestamp %q: %w", s, err)
 	}
-
-	return bodies, nil
+	return time.Unix(epoch, 0), nil
 }
 
 func splitNullByte(data []byte, atEOF bool) (advance int, token []byte, err error) {
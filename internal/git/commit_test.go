@@ -0,0 +1,246 @@
+package git_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestIntegrationListCommitsDetails(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-08-01T10:00:00Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+
+		git checkout -b feature
+		as 'Alice <alice@example.com>'
+		at '2024-08-02T11:00:00Z'
+		git commit --allow-empty -m 'First feature commit' -m 'With a body.'
+
+		as 'Bob <bob@example.com>'
+		at '2024-08-03T12:00:00Z'
+		git commit --allow-empty -m 'Second feature commit'
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	feature, err := repo.PeelToCommit(ctx, "feature")
+	require.NoError(t, err)
+	main, err := repo.PeelToCommit(ctx, "main")
+	require.NoError(t, err)
+
+	commits, err := repo.ListCommitsDetails(ctx,
+		git.CommitRangeFrom(feature).ExcludeFrom(main))
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	firstHash := commits[1].Hash
+
+	for i := range commits {
+		c := &commits[i]
+		assert.NotEmpty(t, c.Hash)
+		assert.Equal(t, c.Hash.Short(), c.ShortHash.String())
+		assert.Len(t, c.Parents, 1)
+		c.Hash = ""
+		c.ShortHash = ""
+		c.Parents = nil
+
+		// Normalize times for comparison.
+		c.Author.Time = c.Author.Time.UTC()
+		c.Committer.Time = c.Committer.Time.UTC()
+	}
+
+	bob := git.Signature{
+		Name:  "Bob",
+		Email: "bob@example.com",
+		Time:  time.Date(2024, 8, 3, 12, 0, 0, 0, time.UTC),
+	}
+	alice := git.Signature{
+		Name:  "Alice",
+		Email: "alice@example.com",
+		Time:  time.Date(2024, 8, 2, 11, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, []git.CommitDetail{
+		{
+			Author:    bob,
+			Committer: bob,
+			Subject:   "Second feature commit",
+		},
+		{
+			Author:    alice,
+			Committer: alice,
+			Subject:   "First feature commit",
+			Body:      "With a body.",
+		},
+	}, commits)
+
+	msgs, err := repo.CommitMessageRange(ctx,
+		git.CommitRangeFrom(feature).ExcludeFrom(main))
+	require.NoError(t, err)
+	assert.Equal(t, []git.CommitMessage{
+		{Subject: "Second feature commit"},
+		{Subject: "First feature commit", Body: "With a body."},
+	}, msgs)
+
+	head, err := repo.ReadCommitDetail(ctx, "feature")
+	require.NoError(t, err)
+	assert.Equal(t, "Second feature commit", head.Subject)
+	assert.Equal(t, []git.Hash{firstHash}, head.Parents)
+}
+
+func TestIntegrationCommitMessageRange_merges(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-08-01T10:00:00Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+
+		git checkout -b feature
+		at '2024-08-01T11:00:00Z'
+		git commit --allow-empty -m 'First feature commit'
+
+		git checkout main
+		git checkout -b other
+		at '2024-08-01T12:00:00Z'
+		git commit --allow-empty -m 'Other commit'
+
+		git checkout feature
+		at '2024-08-01T13:00:00Z'
+		git merge --no-ff -m 'Merge other' other
+		at '2024-08-01T14:00:00Z'
+		git commit --allow-empty -m 'Second feature commit'
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	feature, err := repo.PeelToCommit(ctx, "feature")
+	require.NoError(t, err)
+	main, err := repo.PeelToCommit(ctx, "main")
+	require.NoError(t, err)
+	commits := func() git.CommitRange {
+		return git.CommitRangeFrom(feature).ExcludeFrom(main)
+	}
+
+	tests := []struct {
+		name    string
+		commits git.CommitRange
+		want    []string
+	}{
+		{
+			name:    "All",
+			commits: commits(),
+			want: []string{
+				"Second feature commit",
+				"Merge other",
+				"Other commit",
+				"First feature commit",
+			},
+		},
+		{
+			name:    "FirstParent",
+			commits: commits().FirstParent(),
+			want: []string{
+				"Second feature commit",
+				"Merge other",
+				"First feature commit",
+			},
+		},
+		{
+			name:    "NoMerges",
+			commits: commits().NoMerges(),
+			want: []string{
+				"Second feature commit",
+				"Other commit",
+				"First feature commit",
+			},
+		},
+		{
+			name:    "FirstParentNoMerges",
+			commits: commits().FirstParent().NoMerges(),
+			want: []string{
+				"Second feature commit",
+				"First feature commit",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := repo.CommitMessageRange(ctx, tt.commits)
+			require.NoError(t, err)
+
+			var got []string
+			for _, msg := range msgs {
+				got = append(got, msg.Subject)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIntegrationIsAncestor(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-08-01T10:00:00Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+
+		git checkout -b feature1
+		git commit --allow-empty -m 'feature1'
+
+		git checkout main
+		git checkout -b feature2
+		git commit --allow-empty -m 'feature2'
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	hash := func(ref string) git.Hash {
+		h, err := repo.PeelToCommit(ctx, ref)
+		require.NoError(t, err)
+		return h
+	}
+	main, feature1, feature2 := hash("main"), hash("feature1"), hash("feature2")
+
+	assert.True(t, repo.IsAncestor(ctx, main, feature1))
+	assert.True(t, repo.IsAncestor(ctx, main, main), "commits are their own ancestors")
+	assert.False(t, repo.IsAncestor(ctx, feature1, main))
+	assert.False(t, repo.IsAncestor(ctx, feature1, feature2))
+
+	// Invalid commits are never ancestors.
+	assert.False(t, repo.IsAncestor(ctx, git.ZeroHash, feature1))
+}
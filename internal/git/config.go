@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ConfigValue reports the value of the given Git configuration key.
+// It returns [ErrNotExist] if the key is not set.
+func (r *Repository) ConfigValue(ctx context.Context, key string) (string, error) {
+	return r.configGet(ctx, key)
+}
+
+// ConfigBool reports the value of the given Git configuration key
+// interpreted as a boolean.
+// Git accepts values like "true", "yes", "on", and "1" for true.
+// It returns [ErrNotExist] if the key is not set.
+func (r *Repository) ConfigBool(ctx context.Context, key string) (bool, error) {
+	out, err := r.configGet(ctx, key, "--type=bool")
+	if err != nil {
+		return false, err
+	}
+	return out == "true", nil
+}
+
+// ConfigValues reports all values of the given multi-valued
+// Git configuration key in the order they are defined.
+// It returns [ErrNotExist] if the key is not set.
+func (r *Repository) ConfigValues(ctx context.Context, key string) ([]string, error) {
+	out, err := r.gitCmd(ctx, "config", "--null", "--get-all", key).Output(r.exec)
+	if err != nil {
+		if exitErr := new(exec.ExitError); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, ErrNotExist
+		}
+		return nil, fmt.Errorf("git config: %w", err)
+	}
+
+	// With --null, each value is terminated by a NUL byte.
+	values := strings.Split(string(out), "\x00")
+	return values[:len(values)-1], nil
+}
+
+func (r *Repository) configGet(ctx context.Context, key string, flags ...string) (string, error) {
+	args := append([]string{"config"}, flags...)
+	args = append(args, "--get", key)
+	out, err := r.gitCmd(ctx, args...).OutputString(r.exec)
+	if err != nil {
+		// 'git config --get' exits with 1 if the key is not set.
+		if exitErr := new(exec.ExitError); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", ErrNotExist
+		}
+		return "", fmt.Errorf("git config: %w", err)
+	}
+	return out, nil
+}
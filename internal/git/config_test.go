@@ -0,0 +1,77 @@
+package git_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestIntegrationConfigValue(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		git init
+		git config spice.draftLabel wip
+		git config spice.enabled yes
+		git config spice.disabled off
+		git config --add spice.patterns 'spike/*'
+		git config --add spice.patterns '!spike/ready-*'
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("Set", func(t *testing.T) {
+		value, err := repo.ConfigValue(ctx, "spice.draftLabel")
+		require.NoError(t, err)
+		assert.Equal(t, "wip", value)
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		_, err := repo.ConfigValue(ctx, "spice.doesNotExist")
+		assert.ErrorIs(t, err, git.ErrNotExist)
+	})
+
+	t.Run("Bool", func(t *testing.T) {
+		enabled, err := repo.ConfigBool(ctx, "spice.enabled")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		disabled, err := repo.ConfigBool(ctx, "spice.disabled")
+		require.NoError(t, err)
+		assert.False(t, disabled)
+
+		_, err = repo.ConfigBool(ctx, "spice.doesNotExist")
+		assert.ErrorIs(t, err, git.ErrNotExist)
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		values, err := repo.ConfigValues(ctx, "spice.patterns")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"spike/*", "!spike/ready-*"}, values)
+
+		values, err = repo.ConfigValues(ctx, "spice.draftLabel")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"wip"}, values)
+
+		_, err = repo.ConfigValues(ctx, "spice.doesNotExist")
+		assert.ErrorIs(t, err, git.ErrNotExist)
+	})
+
+	t.Run("BoolInvalid", func(t *testing.T) {
+		_, err := repo.ConfigBool(ctx, "spice.draftLabel")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, git.ErrNotExist)
+	})
+}
@@ -37,7 +37,52 @@ type FileStatus struct {
 //
 // The treeish argument can be any valid tree-ish reference.
 func (r *Repository) DiffIndex(ctx context.Context, treeish string) ([]FileStatus, error) {
-	cmd := r.gitCmd(ctx, "diff-index", "--cached", "--name-status", treeish)
+	return r.diffNameStatus(ctx, "diff-index", "--cached", "--name-status", treeish)
+}
+
+// DiffTree compares the two given trees
+// and returns the list of files that are different between them.
+// Files in subdirectories are reported individually.
+//
+// The arguments can be any valid tree-ish references.
+func (r *Repository) DiffTree(ctx context.Context, treeish1, treeish2 string) ([]FileStatus, error) {
+	return r.diffNameStatus(ctx, "diff-tree", "-r", "--name-status", treeish1, treeish2)
+}
+
+// ChangedFiles returns the paths of files changed in branch
+// since it diverged from base.
+// This is similar to 'git diff --name-only base...branch':
+// changes made to base after the branch diverged are not included.
+//
+// The arguments can be any valid commit-ish references.
+func (r *Repository) ChangedFiles(ctx context.Context, base, branch string) ([]string, error) {
+	files, err := r.ChangedFileStatuses(ctx, base, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths, nil
+}
+
+// ChangedFileStatuses is like [Repository.ChangedFiles],
+// but it also reports how each file was changed.
+func (r *Repository) ChangedFileStatuses(ctx context.Context, base, branch string) ([]FileStatus, error) {
+	mergeBase, err := r.MergeBase(ctx, base, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DiffTree(ctx, mergeBase.String(), branch)
+}
+
+// diffNameStatus runs a diff command that reports --name-status output
+// and parses the result.
+func (r *Repository) diffNameStatus(ctx context.Context, args ...string) ([]FileStatus, error) {
+	cmd := r.gitCmd(ctx, args...)
 	out, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("pipe: %w", err)
@@ -57,7 +102,7 @@ func (r *Repository) DiffIndex(ctx context.Context, treeish string) ([]FileStatu
 
 		status, name, ok := bytes.Cut(bs, []byte{'\t'})
 		if !ok {
-			r.log.Warnf("invalid diff-index output: %s", bs)
+			r.log.Warnf("invalid %v output: %s", args[0], bs)
 			continue
 		}
 		files = append(files, FileStatus{
@@ -71,7 +116,7 @@ func (r *Repository) DiffIndex(ctx context.Context, treeish string) ([]FileStatu
 	}
 
 	if err := cmd.Wait(r.exec); err != nil {
-		return nil, fmt.Errorf("diff-index: %w", err)
+		return nil, fmt.Errorf("%v: %w", args[0], err)
 	}
 
 	return files, nil
@@ -0,0 +1,132 @@
+package git_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestIntegrationDiffTree(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-08-06T12:00:00Z'
+
+		git init
+		git add foo.txt old.txt
+		git commit -m 'Initial commit'
+
+		git checkout -b feature
+		mv new-foo.txt foo.txt
+		git rm old.txt
+		git add foo.txt dir/bar.txt
+		git commit -m 'Change things'
+
+		-- foo.txt --
+		foo
+
+		-- old.txt --
+		old
+
+		-- new-foo.txt --
+		new foo
+
+		-- dir/bar.txt --
+		bar
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	files, err := repo.DiffTree(ctx, "main", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, []git.FileStatus{
+		{Status: "A", Path: "dir/bar.txt"},
+		{Status: "M", Path: "foo.txt"},
+		{Status: "D", Path: "old.txt"},
+	}, files)
+
+	t.Run("NoChanges", func(t *testing.T) {
+		files, err := repo.DiffTree(ctx, "main", "main")
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestIntegrationChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-08-06T12:00:00Z'
+
+		git init
+		git add foo.txt old.txt
+		git commit -m 'Initial commit'
+
+		git checkout -b feature
+		git rm old.txt
+		git add dir/bar.txt
+		git commit -m 'Change things'
+
+		# Changes to main after the branch diverged
+		# are not reported.
+		git checkout main
+		mv new-foo.txt foo.txt
+		git add foo.txt
+		git commit -m 'Change foo'
+
+		-- foo.txt --
+		foo
+
+		-- old.txt --
+		old
+
+		-- new-foo.txt --
+		new foo
+
+		-- dir/bar.txt --
+		bar
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("Paths", func(t *testing.T) {
+		files, err := repo.ChangedFiles(ctx, "main", "feature")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/bar.txt", "old.txt"}, files)
+	})
+
+	t.Run("Statuses", func(t *testing.T) {
+		files, err := repo.ChangedFileStatuses(ctx, "main", "feature")
+		require.NoError(t, err)
+		assert.Equal(t, []git.FileStatus{
+			{Status: "A", Path: "dir/bar.txt"},
+			{Status: "D", Path: "old.txt"},
+		}, files)
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		files, err := repo.ChangedFiles(ctx, "feature", "main~1")
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // FetchOptions specifies parameters for the Fetch method.
@@ -18,6 +20,21 @@ type FetchOptions struct {
 	// Refspecs are the refspecs to fetch.
 	// If non-empty, the Remote must be specified as well.
 	Refspecs []Refspec
+
+	// Depth limits the fetched history to this many commits
+	// from the tip of each fetched ref.
+	// If zero, the history is not limited.
+	//
+	// Depth is ignored when fetching from the local repository
+	// (Remote is ".").
+	Depth int
+
+	// Unshallow fetches the complete history of a shallow clone.
+	// It cannot be used with Depth.
+	//
+	// Unshallow is ignored when fetching from the local repository
+	// (Remote is ".").
+	Unshallow bool
 }
 
 // Fetch fetches objects and refs from a remote repository.
@@ -26,7 +43,24 @@ func (r *Repository) Fetch(ctx context.Context, opts FetchOptions) error {
 		return errors.New("fetch: no remote or refspecs specified")
 	}
 
+	if opts.Depth < 0 {
+		return errors.New("fetch: depth must not be negative")
+	}
+	if opts.Depth > 0 && opts.Unshallow {
+		return errors.New("fetch: depth and unshallow cannot be used together")
+	}
+
 	args := []string{"fetch"}
+	// Local fetches don't transfer any history,
+	// so there's nothing to deepen or shorten.
+	if opts.Remote != "." {
+		if opts.Depth > 0 {
+			args = append(args, "--depth="+strconv.Itoa(opts.Depth))
+		}
+		if opts.Unshallow {
+			args = append(args, "--unshallow")
+		}
+	}
 	if opts.Remote != "" {
 		args = append(args, opts.Remote)
 	}
@@ -40,3 +74,13 @@ func (r *Repository) Fetch(ctx context.Context, opts FetchOptions) error {
 
 	return nil
 }
+
+// IsShallow reports whether the repository is a shallow clone.
+func (r *Repository) IsShallow(ctx context.Context) (bool, error) {
+	out, err := r.gitCmd(ctx, "rev-parse", "--is-shallow-repository").
+		OutputString(r.exec)
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse: %w", err)
+	}
+	return strings.TrimSpace(out) == "true", nil
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFetchArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		give FetchOptions
+
+		want []string
+	}{
+		{
+			name: "remote",
+			give: FetchOptions{Remote: "origin"},
+			want: []string{"fetch", "origin"},
+		},
+		{
+			name: "refspecs",
+			give: FetchOptions{
+				Remote:   "origin",
+				Refspecs: []Refspec{"main:main", "feature"},
+			},
+			want: []string{"fetch", "origin", "main:main", "feature"},
+		},
+		{
+			name: "depth",
+			give: FetchOptions{
+				Remote:   "origin",
+				Refspecs: []Refspec{"main:main"},
+				Depth:    10,
+			},
+			want: []string{"fetch", "--depth=10", "origin", "main:main"},
+		},
+		{
+			name: "unshallow",
+			give: FetchOptions{
+				Remote:    "origin",
+				Unshallow: true,
+			},
+			want: []string{"fetch", "--unshallow", "origin"},
+		},
+		{
+			name: "local ignores depth",
+			give: FetchOptions{
+				Remote:   ".",
+				Refspecs: []Refspec{"feature:main"},
+				Depth:    1,
+			},
+			want: []string{"fetch", ".", "feature:main"},
+		},
+		{
+			name: "local ignores unshallow",
+			give: FetchOptions{
+				Remote:    ".",
+				Refspecs:  []Refspec{"feature:main"},
+				Unshallow: true,
+			},
+			want: []string{"fetch", ".", "feature:main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExecer := NewMockExecer(gomock.NewController(t))
+			repo := NewTestRepository(t, "", mockExecer)
+
+			mockExecer.EXPECT().
+				Run(gomock.Any()).
+				DoAndReturn(func(cmd *exec.Cmd) error {
+					assert.Equal(t, tt.want, cmd.Args[1:])
+					return nil
+				})
+
+			ctx := context.Background()
+			err := repo.Fetch(ctx, tt.give)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	execer := NewMockExecer(gomock.NewController(t))
+	repo := NewTestRepository(t, "", execer)
+	ctx := context.Background()
+
+	t.Run("no remote", func(t *testing.T) {
+		if err := repo.Fetch(ctx, FetchOptions{}); assert.Error(t, err) {
+			assert.ErrorContains(t, err, "no remote or refspecs specified")
+		}
+	})
+
+	t.Run("negative depth", func(t *testing.T) {
+		err := repo.Fetch(ctx, FetchOptions{Remote: "origin", Depth: -1})
+		if assert.Error(t, err) {
+			assert.ErrorContains(t, err, "depth must not be negative")
+		}
+	})
+
+	t.Run("depth and unshallow", func(t *testing.T) {
+		err := repo.Fetch(ctx, FetchOptions{
+			Remote:    "origin",
+			Depth:     1,
+			Unshallow: true,
+		})
+		if assert.Error(t, err) {
+			assert.ErrorContains(t, err, "depth and unshallow cannot be used together")
+		}
+	})
+
+	t.Run("git error", func(t *testing.T) {
+		giveErr := errors.New("great sadness")
+		execer.EXPECT().
+			Run(gomock.Any()).
+			Return(giveErr)
+
+		err := repo.Fetch(ctx, FetchOptions{Remote: "origin"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, giveErr)
+	})
+}
@@ -83,6 +83,8 @@ func (r *Repository) MergeBase(ctx context.Context, a, b string) (Hash, error) {
 }
 
 // IsAncestor reports whether a is an ancestor of b.
+// A commit is its own ancestor.
+// This reports false if either commit does not exist.
 func (r *Repository) IsAncestor(ctx context.Context, a, b Hash) bool {
 	return r.gitCmd(ctx,
 		"merge-base", "--is-ancestor", string(a), string(b),
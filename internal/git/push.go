@@ -1,9 +1,12 @@
 package git
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // PushOptions specifies options for the Push operation.
@@ -26,31 +29,210 @@ type PushOptions struct {
 	// Refspec is the refspec to push.
 	// If empty, the current branch is pushed to the remote.
 	Refspec Refspec
+
+	// Refspecs are additional refspecs to push
+	// in the same operation as Refspec.
+	Refspecs []Refspec
+
+	// ForceWithLeases are leases for the refs in Refspecs.
+	// Each uses the same format as ForceWithLease.
+	ForceWithLeases []string
+
+	// Atomic indicates that either all refs should be updated
+	// on the remote, or none of them.
+	// This is useful when pushing multiple refs at once.
+	Atomic bool
+}
+
+// PushStatus is the outcome of pushing a single ref.
+type PushStatus int
+
+const (
+	// PushFastForward indicates that the remote ref
+	// was fast-forwarded to the new value.
+	PushFastForward PushStatus = iota
+
+	// PushForced indicates that the remote ref
+	// was force-updated to the new value.
+	PushForced
+
+	// PushCreated indicates that a new ref was created on the remote.
+	PushCreated
+
+	// PushDeleted indicates that the remote ref was deleted.
+	PushDeleted
+
+	// PushRejected indicates that the remote ref was not updated.
+	PushRejected
+
+	// PushUpToDate indicates that the remote ref
+	// already had the pushed value.
+	PushUpToDate
+)
+
+// PushedRef reports what happened to a single ref during a push.
+type PushedRef struct {
+	// Local is the source side of the refspec.
+	// This is empty if the ref was deleted.
+	Local string
+
+	// Remote is the full name of the ref on the remote.
+	Remote string
+
+	Status PushStatus
+
+	// OldHash and NewHash are the values of the remote ref
+	// before and after the push.
+	//
+	// These are abbreviated hashes,
+	// and are only set for fast-forward and forced updates.
+	OldHash, NewHash Hash
+
+	// Summary is the summary reported by Git for the ref,
+	// e.g. "[new branch]" or "abc123..def456".
+	Summary string
+}
+
+// Updated reports whether the push changed the remote ref.
+func (ref *PushedRef) Updated() bool {
+	switch ref.Status {
+	case PushRejected, PushUpToDate:
+		return false
+	default:
+		return true
+	}
+}
+
+// PushResult is the result of a push operation.
+type PushResult struct {
+	// Refs are the refs that were pushed
+	// in the order reported by Git.
+	Refs []PushedRef
+}
+
+// Ref returns the result for the given remote ref,
+// or nil if the ref was not part of the push.
+// The name may be a full ref name or a branch name.
+func (r *PushResult) Ref(name string) *PushedRef {
+	if !strings.HasPrefix(name, "refs/") {
+		name = "refs/heads/" + name
+	}
+	for i := range r.Refs {
+		if r.Refs[i].Remote == name {
+			return &r.Refs[i]
+		}
+	}
+	return nil
 }
 
 // Push pushes objects and refs to a remote repository.
-func (r *Repository) Push(ctx context.Context, opts PushOptions) error {
-	if opts.Remote == "" && opts.Refspec == "" {
-		return errors.New("push: no remote or refspec specified")
+//
+// It reports the outcome of the push for each ref
+// as parsed from Git's porcelain output.
+func (r *Repository) Push(ctx context.Context, opts PushOptions) (*PushResult, error) {
+	if opts.Remote == "" && opts.Refspec == "" && len(opts.Refspecs) == 0 {
+		return nil, errors.New("push: no remote or refspec specified")
+	}
+	if opts.Remote == "" && len(opts.Refspecs) > 0 {
+		return nil, errors.New("push: multiple refspecs specified without remote")
 	}
 
-	args := []string{"push"}
+	args := []string{"push", "--porcelain"}
 	if lease := opts.ForceWithLease; lease != "" {
 		args = append(args, "--force-with-lease="+lease)
 	}
+	for _, lease := range opts.ForceWithLeases {
+		args = append(args, "--force-with-lease="+lease)
+	}
 	if opts.Force {
 		args = append(args, "--force")
 	}
+	if opts.Atomic {
+		args = append(args, "--atomic")
+	}
 	if opts.Remote != "" {
 		args = append(args, opts.Remote)
 	}
 	if opts.Refspec != "" {
 		args = append(args, opts.Refspec.String())
 	}
+	for _, refspec := range opts.Refspecs {
+		args = append(args, refspec.String())
+	}
 
-	if err := r.gitCmd(ctx, args...).Run(r.exec); err != nil {
-		return fmt.Errorf("push: %w", err)
+	out, err := r.gitCmd(ctx, args...).Output(r.exec)
+	if err != nil {
+		return nil, fmt.Errorf("push: %w", err)
 	}
 
-	return nil
+	result, err := parsePushPorcelain(out)
+	if err != nil {
+		return nil, fmt.Errorf("push: %w", err)
+	}
+	return result, nil
+}
+
+// parsePushPorcelain parses the output of 'git push --porcelain'.
+//
+// The output takes the form:
+//
+//	To <url>
+//	<flag> TAB <from>:<to> TAB <summary> [(<reason>)]
+//	...
+//	Done
+func parsePushPorcelain(out []byte) (*PushResult, error) {
+	var result PushResult
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "Done" || strings.HasPrefix(line, "To ") {
+			continue
+		}
+
+		flag, rest, ok := strings.Cut(line, "\t")
+		if !ok || len(flag) != 1 {
+			continue
+		}
+
+		refs, summary, _ := strings.Cut(rest, "\t")
+		from, to, ok := strings.Cut(refs, ":")
+		if !ok {
+			return nil, fmt.Errorf("bad ref line: %q", line)
+		}
+
+		ref := PushedRef{
+			Local:   from,
+			Remote:  to,
+			Summary: summary,
+		}
+		switch flag[0] {
+		case ' ':
+			ref.Status = PushFastForward
+		case '+':
+			ref.Status = PushForced
+		case '*':
+			ref.Status = PushCreated
+		case '-':
+			ref.Status = PushDeleted
+		case '!':
+			ref.Status = PushRejected
+		case '=':
+			ref.Status = PushUpToDate
+		default:
+			return nil, fmt.Errorf("bad ref flag %q: %q", flag, line)
+		}
+
+		if ref.Status == PushFastForward || ref.Status == PushForced {
+			hashes, _, _ := strings.Cut(summary, " ")
+			if oldHash, newHash, ok := strings.Cut(hashes, "..."); ok {
+				ref.OldHash, ref.NewHash = Hash(oldHash), Hash(newHash)
+			} else if oldHash, newHash, ok := strings.Cut(hashes, ".."); ok {
+				ref.OldHash, ref.NewHash = Hash(oldHash), Hash(newHash)
+			}
+		}
+
+		result.Refs = append(result.Refs, ref)
+	}
+
+	return &result, scanner.Err()
 }
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPushArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		give PushOptions
+
+		want []string
+	}{
+		{
+			name: "remote",
+			give: PushOptions{Remote: "origin"},
+			want: []string{"push", "--porcelain", "origin"},
+		},
+		{
+			name: "force with lease",
+			give: PushOptions{
+				Remote:         "origin",
+				Refspec:        "abc:refs/heads/feature",
+				ForceWithLease: "feature:def",
+			},
+			want: []string{
+				"push", "--porcelain", "--force-with-lease=feature:def",
+				"origin", "abc:refs/heads/feature",
+			},
+		},
+		{
+			name: "atomic multiple refs",
+			give: PushOptions{
+				Remote:  "origin",
+				Refspec: "abc:refs/heads/feature1",
+				Refspecs: []Refspec{
+					"def:refs/heads/feature2",
+				},
+				ForceWithLease:  "feature1:123",
+				ForceWithLeases: []string{"feature2:456"},
+				Atomic:          true,
+			},
+			want: []string{
+				"push", "--porcelain",
+				"--force-with-lease=feature1:123",
+				"--force-with-lease=feature2:456",
+				"--atomic",
+				"origin",
+				"abc:refs/heads/feature1",
+				"def:refs/heads/feature2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExecer := NewMockExecer(gomock.NewController(t))
+			repo := NewTestRepository(t, "", mockExecer)
+
+			mockExecer.EXPECT().
+				Output(gomock.Any()).
+				DoAndReturn(func(cmd *exec.Cmd) ([]byte, error) {
+					assert.Equal(t, tt.want, cmd.Args[1:])
+					return []byte("Done\n"), nil
+				})
+
+			ctx := context.Background()
+			_, err := repo.Push(ctx, tt.give)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPushErrors(t *testing.T) {
+	execer := NewMockExecer(gomock.NewController(t))
+	repo := NewTestRepository(t, "", execer)
+	ctx := context.Background()
+
+	t.Run("nothing to push", func(t *testing.T) {
+		_, err := repo.Push(ctx, PushOptions{})
+		assert.ErrorContains(t, err, "no remote or refspec specified")
+	})
+
+	t.Run("refspecs without remote", func(t *testing.T) {
+		_, err := repo.Push(ctx, PushOptions{
+			Refspecs: []Refspec{"feature"},
+		})
+		assert.ErrorContains(t, err, "multiple refspecs specified without remote")
+	})
+}
+
+func TestParsePushPorcelain(t *testing.T) {
+	out := strings.Join([]string{
+		"To ../remote.git",
+		" \tabc:refs/heads/ff\tee8c7dd..ac3fa37",
+		"+\tdef:refs/heads/forced\tac3fa37...ee8c7dd (forced update)",
+		"*\tHEAD:refs/heads/new\t[new branch]",
+		"-\t:refs/heads/gone\t[deleted]",
+		"=\tHEAD:refs/heads/same\t[up to date]",
+		"!\tHEAD:refs/heads/main\t[rejected] (non-fast-forward)",
+		"Done",
+		"",
+	}, "\n")
+
+	got, err := parsePushPorcelain([]byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, []PushedRef{
+		{
+			Local:   "abc",
+			Remote:  "refs/heads/ff",
+			Status:  PushFastForward,
+			OldHash: "ee8c7dd",
+			NewHash: "ac3fa37",
+			Summary: "ee8c7dd..ac3fa37",
+		},
+		{
+			Local:   "def",
+			Remote:  "refs/heads/forced",
+			Status:  PushForced,
+			OldHash: "ac3fa37",
+			NewHash: "ee8c7dd",
+			Summary: "ac3fa37...ee8c7dd (forced update)",
+		},
+		{
+			Local:   "HEAD",
+			Remote:  "refs/heads/new",
+			Status:  PushCreated,
+			Summary: "[new branch]",
+		},
+		{
+			Remote:  "refs/heads/gone",
+			Status:  PushDeleted,
+			Summary: "[deleted]",
+		},
+		{
+			Local:   "HEAD",
+			Remote:  "refs/heads/same",
+			Status:  PushUpToDate,
+			Summary: "[up to date]",
+		},
+		{
+			Local:   "HEAD",
+			Remote:  "refs/heads/main",
+			Status:  PushRejected,
+			Summary: "[rejected] (non-fast-forward)",
+		},
+	}, got.Refs)
+
+	assert.True(t, got.Ref("ff").Updated())
+	assert.False(t, got.Ref("refs/heads/same").Updated())
+	assert.Nil(t, got.Ref("unknown"))
+}
@@ -61,8 +61,18 @@ func (e *RebaseInterruptError) Unwrap() error {
 }
 
 // RebaseRequest is a request to rebase a branch.
+//
+// The request maps to:
+//
+//	git rebase --onto <Onto> <Upstream> <Branch>
+//
+// This rebases the commits in Upstream..Branch onto Onto,
+// regardless of which branch is currently checked out.
 type RebaseRequest struct {
 	// Branch is the branch to rebase.
+	// If unspecified, the current branch is rebased.
+	//
+	// Upstream must be set if Branch is set.
 	Branch string
 
 	// Upstream is the upstream commitish
@@ -87,12 +97,24 @@ type RebaseRequest struct {
 	// with a list of rebase instructions to edit
 	// before starting the rebase operation.
 	Interactive bool
+
+	// UpdateRefs is true if other branches that point to commits
+	// being rebased should also be updated to point to the rebased commits.
+	//
+	// This requires Git 2.38 or newer.
+	UpdateRefs bool
 }
 
 // Rebase runs a git rebase operation with the specified parameters.
 // It returns [ErrRebaseInterrupted] or [ErrRebaseConflict] for known
 // rebase interruptions.
 func (r *Repository) Rebase(ctx context.Context, req RebaseRequest) error {
+	// Without an upstream, Git would interpret Branch as the upstream
+	// and rebase the current branch onto it instead.
+	if req.Branch != "" && req.Upstream == "" {
+		return errors.New("rebase: upstream is required when branch is set")
+	}
+
 	args := []string{
 		// Never include advice on how to resolve merge conflicts.
 		// We'll do that ourselves.
@@ -111,6 +133,9 @@ func (r *Repository) Rebase(ctx context.Context, req RebaseRequest) error {
 	if req.Quiet {
 		args = append(args, "--quiet")
 	}
+	if req.UpdateRefs {
+		args = append(args, "--update-refs")
+	}
 	if req.Upstream != "" {
 		args = append(args, req.Upstream)
 	}
@@ -221,6 +246,10 @@ type RebaseState struct {
 	// Merge is the default.
 	// Apply is rarely used and may be phased out in the future.
 	Backend RebaseBackend
+
+	// UpdateRefs is true if the rebase was started with --update-refs,
+	// and will update other branches in addition to Branch.
+	UpdateRefs bool
 }
 
 // ErrNoRebase indicates that a rebase is not in progress.
@@ -236,6 +265,7 @@ func (r *Repository) RebaseState(context.Context) (*RebaseState, error) {
 	// Inside that directory, we care about the following files:
 	//
 	//   - head-name: full ref name of the branch being rebased (e.g. refs/heads/main)
+	//   - update-refs: present only if the rebase was started with --update-refs
 	//
 	// There's no Git porcelain command to directly get this information.
 	for _, backend := range []RebaseBackend{RebaseBackendApply, RebaseBackendMerge} {
@@ -260,6 +290,9 @@ func (r *Repository) RebaseState(context.Context) (*RebaseState, error) {
 			Branch:  strings.TrimPrefix(branchRef, "refs/heads/"),
 			Backend: backend,
 		}
+		if _, err := os.Stat(filepath.Join(stateDir, "update-refs")); err == nil {
+			state.UpdateRefs = true
+		}
 
 		return state, nil
 	}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRebaseArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		give RebaseRequest
+
+		want []string
+	}{
+		{
+			name: "upstream",
+			give: RebaseRequest{Upstream: "main"},
+			want: []string{"rebase", "main"},
+		},
+		{
+			name: "onto upstream branch",
+			give: RebaseRequest{
+				Onto:     "main",
+				Upstream: "abc123",
+				Branch:   "feature",
+			},
+			want: []string{"rebase", "--onto", "main", "abc123", "feature"},
+		},
+		{
+			name: "options",
+			give: RebaseRequest{
+				Onto:       "main",
+				Upstream:   "abc123",
+				Branch:     "feature",
+				Autostash:  true,
+				Quiet:      true,
+				UpdateRefs: true,
+			},
+			want: []string{
+				"rebase", "--onto", "main", "--autostash", "--quiet", "--update-refs",
+				"abc123", "feature",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExecer := NewMockExecer(gomock.NewController(t))
+			repo := NewTestRepository(t, "", mockExecer)
+
+			mockExecer.EXPECT().
+				Run(gomock.Any()).
+				DoAndReturn(func(cmd *exec.Cmd) error {
+					// Skip the '-c advice.mergeConflict=false' prefix.
+					assert.Equal(t, tt.want, cmd.Args[3:])
+					return nil
+				})
+
+			err := repo.Rebase(context.Background(), tt.give)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRebaseErrors(t *testing.T) {
+	repo := NewTestRepository(t, "", NewMockExecer(gomock.NewController(t)))
+
+	t.Run("branch without upstream", func(t *testing.T) {
+		err := repo.Rebase(context.Background(), RebaseRequest{
+			Onto:   "main",
+			Branch: "feature",
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "upstream is required")
+	})
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ReflogEntry is an entry in the reflog of a ref.
+type ReflogEntry struct {
+	// Hash is the hash that the ref pointed to
+	// after the change recorded by this entry.
+	Hash Hash
+
+	// Message describes the change.
+	// For example, "commit: Add feature"
+	// or "fetch . feature:main: fast-forward".
+	Message string
+}
+
+// Reflog lists the entries in the reflog of the given ref,
+// newest first.
+// The ref should be fully qualified, e.g. "refs/heads/main".
+//
+// It returns an empty list if the ref has no reflog.
+func (r *Repository) Reflog(ctx context.Context, ref string) ([]ReflogEntry, error) {
+	// 'git reflog show' fails for refs that don't exist.
+	// Check first so that we can tell that apart from other failures.
+	if err := r.gitCmd(ctx, "reflog", "exists", ref).Run(r.exec); err != nil {
+		return nil, nil
+	}
+
+	cmd := r.gitCmd(ctx, "reflog", "show", "--format=%H %gs", ref, "--")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("git reflog: %w", err)
+	}
+
+	if err := cmd.Start(r.exec); err != nil {
+		return nil, fmt.Errorf("start git reflog: %w", err)
+	}
+
+	var entries []ReflogEntry
+	scan := bufio.NewScanner(out)
+	for scan.Scan() {
+		line := scan.Text()
+		hash, msg, ok := strings.Cut(line, " ")
+		if !ok {
+			r.log.Warn("Bad reflog output", "line", line, "error", "missing a message")
+			continue
+		}
+
+		entries = append(entries, ReflogEntry{
+			Hash:    Hash(hash),
+			Message: msg,
+		})
+	}
+
+	if err := scan.Err(); err != nil {
+		return nil, fmt.Errorf("read output: %w", err)
+	}
+
+	if err := cmd.Wait(r.exec); err != nil {
+		return nil, fmt.Errorf("git reflog: %w", err)
+	}
+
+	return entries, nil
+}
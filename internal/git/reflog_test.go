@@ -0,0 +1,58 @@
+package git_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestIntegrationReflog(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-08-01T10:00:00Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+
+		git checkout -b feature
+		git commit --allow-empty -m 'Feature commit'
+
+		git fetch . feature:main
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	feature, err := repo.PeelToCommit(ctx, "feature")
+	require.NoError(t, err)
+	initial, err := repo.PeelToCommit(ctx, "feature~")
+	require.NoError(t, err)
+
+	t.Run("Branch", func(t *testing.T) {
+		entries, err := repo.Reflog(ctx, "refs/heads/main")
+		require.NoError(t, err)
+		assert.Equal(t, []git.ReflogEntry{
+			{Hash: feature, Message: "fetch . feature:main: fast-forward"},
+			{Hash: initial, Message: "commit (initial): Initial commit"},
+		}, entries)
+	})
+
+	t.Run("NoReflog", func(t *testing.T) {
+		entries, err := repo.Reflog(ctx, "refs/heads/does-not-exist")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -1,9 +1,11 @@
 package git
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/log"
@@ -50,11 +52,21 @@ type OpenOptions struct {
 	// Log specifies the logger to use for messages.
 	Log *log.Logger
 
+	// GitDir is the path to the Git directory of the repository.
+	//
+	// If unset, the Git directory is discovered
+	// from the working tree as usual.
+	// Set this for working trees whose Git directory
+	// can't be discovered from the working tree.
+	GitDir string
+
 	exec execer
 }
 
 // Open opens the repository at the given directory.
 // If dir is empty, the current working directory is used.
+//
+// The directory must be inside a Git working tree.
 func Open(ctx context.Context, dir string, opts OpenOptions) (*Repository, error) {
 	if opts.exec == nil {
 		opts.exec = _realExec
@@ -63,11 +75,26 @@ func Open(ctx context.Context, dir string, opts OpenOptions) (*Repository, error
 		opts.Log = log.New(io.Discard)
 	}
 
+	var env []string
+	if opts.GitDir != "" {
+		gitDir, err := filepath.Abs(opts.GitDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolve git directory: %w", err)
+		}
+
+		workTree, err := filepath.Abs(cmp.Or(dir, "."))
+		if err != nil {
+			return nil, fmt.Errorf("resolve working tree: %w", err)
+		}
+
+		env = []string{"GIT_DIR=" + gitDir, "GIT_WORK_TREE=" + workTree}
+	}
+
 	out, err := newGitCmd(ctx, opts.Log,
 		"rev-parse",
 		"--show-toplevel",
 		"--absolute-git-dir",
-	).Dir(dir).OutputString(opts.exec)
+	).Dir(dir).AppendEnv(env...).OutputString(opts.exec)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +104,9 @@ func Open(ctx context.Context, dir string, opts OpenOptions) (*Repository, error
 		return nil, fmt.Errorf("unexpected output from git rev-parse: %q", out)
 	}
 
-	return newRepository(root, gitDir, opts.Log, opts.exec), nil
+	repo := newRepository(root, gitDir, opts.Log, opts.exec)
+	repo.env = env
+	return repo, nil
 }
 
 // Repository is a handle to a Git repository.
@@ -86,6 +115,10 @@ type Repository struct {
 	root   string
 	gitDir string
 
+	// env holds environment variables set for all Git commands,
+	// if the Git directory was specified explicitly.
+	env []string
+
 	log  *log.Logger
 	exec execer
 }
@@ -102,5 +135,5 @@ func newRepository(root, gitDir string, log *log.Logger, exec execer) *Repositor
 // gitCmd returns a gitCmd that will run
 // with the repository's root as the working directory.
 func (r *Repository) gitCmd(ctx context.Context, args ...string) *gitCmd {
-	return newGitCmd(ctx, r.log, args...).Dir(r.root)
+	return newGitCmd(ctx, r.log, args...).Dir(r.root).AppendEnv(r.env...)
 }
@@ -0,0 +1,53 @@
+package git_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestIntegrationOpenGitDir(t *testing.T) {
+	t.Parallel()
+
+	// The working tree has no .git,
+	// so the Git directory can't be discovered from it.
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2024-10-16T16:40:32Z'
+
+		mkdir work
+		git --git-dir=repo.git --work-tree=work init --initial-branch=main
+		git --git-dir=repo.git --work-tree=work commit --allow-empty -m 'Initial commit'
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := context.Background()
+	workTree := filepath.Join(fixture.Dir(), "work")
+
+	t.Run("NoGitDir", func(t *testing.T) {
+		_, err := git.Open(ctx, workTree, git.OpenOptions{
+			Log: logtest.New(t),
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("GitDir", func(t *testing.T) {
+		repo, err := git.Open(ctx, workTree, git.OpenOptions{
+			Log:    logtest.New(t),
+			GitDir: filepath.Join(fixture.Dir(), "repo.git"),
+		})
+		require.NoError(t, err)
+
+		branch, err := repo.CurrentBranch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "main", branch)
+	})
+}
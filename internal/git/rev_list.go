@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 )
 
 // ListCommits returns a list of commits matched by the given range.
@@ -32,69 +31,123 @@ type CommitDetail struct {
 	// ShortHash is the short (usually 7-character) hash of the commit.
 	ShortHash Hash
 
+	// Parents are the hashes of the parent commits.
+	Parents []Hash
+
+	// Author and Committer of the commit.
+	Author, Committer Signature
+
 	// Subject is the first line of the commit message.
+	// Contains no leading or trailing whitespace.
 	Subject string
 
-	// AuthorDate is the time the commit was authored.
-	AuthorDate time.Time
+	// Body is the rest of the commit message.
+	// Contains no leading or trailing whitespace.
+	Body string
 }
 
 func (cd *CommitDetail) String() string {
-	return fmt.Sprintf("%s %s %s", cd.ShortHash, cd.AuthorDate, cd.Subject)
+	return fmt.Sprintf("%s %s %s", cd.ShortHash, cd.Author.Time, cd.Subject)
 }
 
+// Message returns the subject and body of the commit.
+func (cd *CommitDetail) Message() CommitMessage {
+	return CommitMessage{
+		Subject: cd.Subject,
+		Body:    cd.Body,
+	}
+}
+
+// Fields of the format used by ListCommitsDetails,
+// separated by a unit separator (0x1f).
+// The message is last so that it may contain unit separators.
+const (
+	_commitDetailFormat = "%H%x1f%h%x1f%P%x1f" +
+		"%an%x1f%ae%x1f%at%x1f" +
+		"%cn%x1f%ce%x1f%ct%x1f" +
+		"%B"
+	_commitDetailFields = 10
+)
+
 // ListCommitsDetails returns details about commits matched by the given range.
 func (r *Repository) ListCommitsDetails(ctx context.Context, commits CommitRange) ([]CommitDetail, error) {
-	lines, err := r.listCommitsFormat(ctx, commits, "%H %h %at %s")
+	records, err := r.listCommitsFormat(ctx, commits, _commitDetailFormat)
 	if err != nil {
 		return nil, err
 	}
 
-	details := make([]CommitDetail, len(lines))
-	for i, line := range lines {
-		hash, line, ok := strings.Cut(line, " ")
-		if !ok {
-			r.log.Warn("Bad rev-list output", "line", line, "error", "missing a hash")
-			continue
-		}
-
-		shortHash, line, ok := strings.Cut(line, " ")
-		if !ok {
-			r.log.Warn("Bad rev-list output", "line", line, "error", "missing a short hash")
+	details := make([]CommitDetail, 0, len(records))
+	for _, record := range records {
+		fields := strings.SplitN(record, "\x1f", _commitDetailFields)
+		if len(fields) != _commitDetailFields {
+			r.log.Warn("Bad rev-list output", "record", record, "error", "too few fields")
 			continue
 		}
 
-		epochstr, subject, ok := strings.Cut(line, " ")
-		if !ok {
-			r.log.Warn("Bad rev-list output", "line", line, "error", "missing an time")
+		authorTime, err := parseUnixTime(fields[5])
+		if err != nil {
+			r.log.Warn("Bad rev-list output", "record", record, "error", err)
 			continue
 		}
-		epoch, err := strconv.ParseInt(epochstr, 10, 64)
+		committerTime, err := parseUnixTime(fields[8])
 		if err != nil {
-			r.log.Warn("Bad rev-list output", "line", line, "error", err)
+			r.log.Warn("Bad rev-list output", "record", record, "error", err)
 			continue
 		}
 
-		details[i] = CommitDetail{
-			Hash:       Hash(hash),
-			ShortHash:  Hash(shortHash),
-			Subject:    subject,
-			AuthorDate: time.Unix(epoch, 0),
+		var parents []Hash
+		for _, p := range strings.Fields(fields[2]) {
+			parents = append(parents, Hash(p))
 		}
+
+		subject, body, _ := strings.Cut(strings.TrimSpace(fields[9]), "\n")
+		details = append(details, CommitDetail{
+			Hash:      Hash(fields[0]),
+			ShortHash: Hash(fields[1]),
+			Parents:   parents,
+			Author: Signature{
+				Name:  fields[3],
+				Email: fields[4],
+				Time:  authorTime,
+			},
+			Committer: Signature{
+				Name:  fields[6],
+				Email: fields[7],
+				Time:  committerTime,
+			},
+			Subject: strings.TrimSpace(subject),
+			Body:    strings.TrimSpace(body),
+		})
 	}
 
 	return details, nil
 }
 
+// ReadCommitDetail returns details about a single commit.
+func (r *Repository) ReadCommitDetail(ctx context.Context, commitish string) (*CommitDetail, error) {
+	details, err := r.ListCommitsDetails(ctx, CommitRangeFrom(Hash(commitish)).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(details) == 0 {
+		return nil, fmt.Errorf("commit not found: %v", commitish)
+	}
+	return &details[0], nil
+}
+
 // ListCommitsFormat lists commits matched by the given range,
 // formatted according to the given format string.
 //
+// Without a format, it lists one commit hash per line.
+// With a format, records are separated by null bytes
+// so that they may span multiple lines.
+//
 // See git-log(1) for details on the format string.
 func (r *Repository) listCommitsFormat(ctx context.Context, commits CommitRange, format string) ([]string, error) {
 	args := make([]string, 0, len(commits)+3)
 	args = append(args, "rev-list")
 	if format != "" {
-		args = append(args, "--format="+format, "--no-commit-header")
+		args = append(args, "--format="+format+"%x00", "--no-commit-header")
 	}
 	args = append(args, []string(commits)...)
 
@@ -111,8 +164,21 @@ func (r *Repository) listCommitsFormat(ctx context.Context, commits CommitRange,
 	// TODO: Return a string iterator
 	var lines []string
 	scanner := bufio.NewScanner(out)
+	if format != "" {
+		scanner.Split(splitNullByte)
+	}
 	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+		line := scanner.Text()
+		if format != "" {
+			// Each record is followed by a newline,
+			// so all records but the first start with one,
+			// and the output ends with one.
+			line = strings.TrimPrefix(line, "\n")
+			if line == "" {
+				continue
+			}
+		}
+		lines = append(lines, line)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -157,9 +223,13 @@ func CommitRangeFrom(from Hash) CommitRange {
 }
 
 // ExcludeFrom indicates that the listing should exclude
-// commits reachable from the given hash.
-func (r CommitRange) ExcludeFrom(hash Hash) CommitRange {
-	return append(r, "--not", string(hash))
+// commits reachable from any of the given hashes.
+func (r CommitRange) ExcludeFrom(hashes ...Hash) CommitRange {
+	r = append(r, "--not")
+	for _, h := range hashes {
+		r = append(r, string(h))
+	}
+	return r
 }
 
 // Limit sets the maximum number of commits to list.
@@ -173,6 +243,11 @@ func (r CommitRange) FirstParent() CommitRange {
 	return append(r, "--first-parent")
 }
 
+// NoMerges indicates that merge commits should not be listed.
+func (r CommitRange) NoMerges() CommitRange {
+	return append(r, "--no-merges")
+}
+
 // Reverse indicates that the commits should be listed in reverse order.
 func (r CommitRange) Reverse() CommitRange {
 	return append(r, "--reverse")
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// DirtyFiles returns the paths of tracked files
+// that have uncommitted changes in the index or the working tree.
+// Untracked files are not included.
+func (r *Repository) DirtyFiles(ctx context.Context) ([]string, error) {
+	out, err := r.gitCmd(ctx,
+		"status", "--porcelain", "-z", "--untracked-files=no",
+	).Output(r.exec)
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+
+	// Each entry is in the form "XY path",
+	// followed by "origPath" as a separate entry for renames and copies.
+	// See man git-status for details.
+	var files []string
+	entries := bytes.Split(out, []byte{0})
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		status, path := entry[:2], entry[3:]
+		files = append(files, string(path))
+		if status[0] == 'R' || status[0] == 'C' {
+			i++ // skip origPath
+		}
+	}
+
+	return files, nil
+}
+
+// IsClean reports whether the working tree and the index
+// have no uncommitted changes to tracked files.
+func (r *Repository) IsClean(ctx context.Context) (bool, error) {
+	files, err := r.DirtyFiles(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(files) == 0, nil
+}
@@ -0,0 +1,87 @@
+package git_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestIntegrationDirtyFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name: "Clean",
+			script: text.Dedent(`
+				git init
+				git add foo.txt
+				git commit -m 'Initial commit'
+
+				-- foo.txt --
+				foo
+
+				-- untracked.txt --
+				untracked
+			`),
+		},
+		{
+			name: "Dirty",
+			script: text.Dedent(`
+				git init
+				git add foo.txt old.txt
+				git commit -m 'Initial commit'
+
+				mv new-foo.txt foo.txt
+				git mv old.txt new.txt
+
+				-- foo.txt --
+				foo
+
+				-- old.txt --
+				old
+
+				-- new-foo.txt --
+				new foo
+			`),
+			want: []string{"foo.txt", "new.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fixture, err := gittest.LoadFixtureScript([]byte(
+				"as 'Test <test@example.com>'\n" +
+					"at '2024-08-07T09:00:00Z'\n" +
+					tt.script,
+			))
+			require.NoError(t, err)
+			t.Cleanup(fixture.Cleanup)
+
+			ctx := context.Background()
+			repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{
+				Log: logtest.New(t),
+			})
+			require.NoError(t, err)
+
+			files, err := repo.DirtyFiles(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, files)
+
+			clean, err := repo.IsClean(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want) == 0, clean)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a Git version number.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// Version reports the version of Git in use.
+func (r *Repository) Version(ctx context.Context) (Version, error) {
+	out, err := r.gitCmd(ctx, "version").OutputString(r.exec)
+	if err != nil {
+		return Version{}, fmt.Errorf("git version: %w", err)
+	}
+	return parseVersion(out)
+}
+
+// parseVersion parses the output of 'git version'.
+// The output is in the form:
+//
+//	git version 2.39.5
+//
+// Versions may have vendor-specific suffixes, e.g.
+//
+//	git version 2.39.3 (Apple Git-146)
+//	git version 2.45.1.windows.1
+func parseVersion(s string) (Version, error) {
+	rest, ok := strings.CutPrefix(s, "git version ")
+	if !ok {
+		return Version{}, fmt.Errorf("unexpected output: %q", s)
+	}
+	rest, _, _ = strings.Cut(rest, " ")
+
+	var (
+		v     Version
+		parts = strings.SplitN(rest, ".", 4)
+	)
+	for i, dst := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if i >= len(parts) {
+			break
+		}
+
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			// Release candidates have versions like "2.46.0-rc0".
+			digits, _, _ := strings.Cut(parts[i], "-")
+			n, err = strconv.Atoi(digits)
+			if err != nil {
+				return Version{}, fmt.Errorf("bad version %q: %w", rest, err)
+			}
+		}
+		*dst = n
+	}
+
+	return v, nil
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		give string
+		want Version
+	}{
+		{"git version 2.39.5", Version{2, 39, 5}},
+		{"git version 2.39.3 (Apple Git-146)", Version{2, 39, 3}},
+		{"git version 2.45.1.windows.1", Version{2, 45, 1}},
+		{"git version 2.46.0-rc0", Version{2, 46, 0}},
+		{"git version 3.0", Version{3, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := parseVersion(tt.give)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseVersionErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"version 2.39.5",
+		"git version two.three",
+	}
+
+	for _, give := range tests {
+		t.Run(give, func(t *testing.T) {
+			_, err := parseVersion(give)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	v := Version{2, 38, 1}
+	assert.True(t, v.AtLeast(Version{2, 38, 0}))
+	assert.True(t, v.AtLeast(Version{2, 38, 1}))
+	assert.True(t, v.AtLeast(Version{1, 99, 99}))
+	assert.False(t, v.AtLeast(Version{2, 38, 2}))
+	assert.False(t, v.AtLeast(Version{2, 39, 0}))
+	assert.False(t, v.AtLeast(Version{3, 0, 0}))
+}
@@ -38,6 +38,13 @@ func BeEmptyMapf[K comparable, V any](m map[K]V, format string, args ...any) {
 	}
 }
 
+// HaveKeyf panics if m does not contain the key k.
+func HaveKeyf[K comparable, V any](m map[K]V, k K, format string, args ...any) {
+	if _, ok := m[k]; !ok {
+		panicErrorf("%v\nmissing key: %v", fmt.Errorf(format, args...), k)
+	}
+}
+
 // NotBeEqualf panics if a == b.
 func NotBeEqualf[T comparable](a, b T, format string, args ...any) {
 	if a == b {
@@ -46,6 +53,18 @@ func NotBeEqualf[T comparable](a, b T, format string, args ...any) {
 	}
 }
 
+// BeOneOf panics if v is not one of the values in allowed.
+func BeOneOf[T comparable](v T, allowed []T, format string, args ...any) {
+	for _, a := range allowed {
+		if v == a {
+			return
+		}
+	}
+
+	panicErrorf("%v\nwant one of: %v\ngot: %v",
+		fmt.Errorf(format, args...), allowed, v)
+}
+
 // NotBeBlankf panics if s is empty or contains only whitespace.
 func NotBeBlankf(s string, format string, args ...any) {
 	s = strings.TrimSpace(s)
@@ -46,6 +46,16 @@ func TestBeEmptyMapf(t *testing.T) {
 	})
 }
 
+func TestHaveKeyf(t *testing.T) {
+	assert.PanicsWithError(t, "branch not found\nmissing key: b", func() {
+		HaveKeyf(map[string]int{"a": 1}, "b", "branch not found")
+	})
+
+	assert.NotPanics(t, func() {
+		HaveKeyf(map[string]int{"a": 1}, "a", "branch found")
+	})
+}
+
 func TestNotBeEqualf(t *testing.T) {
 	assert.Panics(t, func() {
 		NotBeEqualf(1, 1, "1 == 1")
@@ -56,6 +66,16 @@ func TestNotBeEqualf(t *testing.T) {
 	})
 }
 
+func TestBeOneOf(t *testing.T) {
+	assert.PanicsWithError(t, "unknown\nwant one of: [1 2 3]\ngot: 4", func() {
+		BeOneOf(4, []int{1, 2, 3}, "unknown")
+	})
+
+	assert.NotPanics(t, func() {
+		BeOneOf(2, []int{1, 2, 3}, "known")
+	})
+}
+
 func TestNotBeBlankf(t *testing.T) {
 	assert.Panics(t, func() {
 		NotBeBlankf("", "empty")
@@ -2,13 +2,13 @@ package spice
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
 	"sort"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"go.abhg.dev/gs/internal/forge"
 	"go.abhg.dev/gs/internal/git"
@@ -25,11 +25,16 @@ const _generatedBranchNameLimit = 32
 //
 // If the subject has more than 32 characters, it is truncated to 32 characters
 // at word boundaries.
+// A first word longer than that is cut at 32 characters.
+//
+// It returns an empty string if the subject has no letters or numbers.
 func GenerateBranchName(subject string) string {
 	words := strings.FieldsFunc(strings.ToLower(subject), func(r rune) bool {
 		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
 	})
-	must.NotBeEmptyf(words, "subject must not be empty")
+	if len(words) == 0 {
+		return ""
+	}
 
 	var name strings.Builder
 	for _, w := range words {
@@ -40,6 +45,15 @@ func GenerateBranchName(subject string) string {
 		}
 
 		if newLen > _generatedBranchNameLimit {
+			if name.Len() == 0 {
+				// Don't return an empty name for a long first word.
+				for _, r := range w {
+					if name.Len()+utf8.RuneLen(r) > _generatedBranchNameLimit {
+						break
+					}
+					name.WriteRune(r)
+				}
+			}
 			break
 		}
 
@@ -71,10 +85,19 @@ type LookupBranchResponse struct {
 	// This is nil if the branch hasn't been published yet.
 	Change forge.ChangeMetadata
 
+	// ChangeURL is the last known web URL of the published change.
+	// This is recorded when the branch is submitted,
+	// and may be empty for changes submitted by older versions.
+	ChangeURL string
+
 	// UpstreamBranch is the name of the upstream branch
 	// or an empty string if the branch is not tracking an upstream branch.
 	UpstreamBranch string
 
+	// UpstreamHash is the hash of the commit that was last pushed
+	// to the upstream branch, if known.
+	UpstreamHash git.Hash
+
 	// Head is the commit at the head of the branch.
 	Head git.Hash
 }
@@ -94,12 +117,32 @@ func (e *DeletedBranchError) Error() string {
 	return fmt.Sprintf("tracked branch %v was deleted out of band", e.Name)
 }
 
+// ErrTrunk indicates that the trunk branch was used
+// where a tracked branch was expected.
+//
+// Trunk is never tracked, so this error also matches [state.ErrNotExist]
+// for callers that don't need to tell the two apart.
+var ErrTrunk error = trunkError{}
+
+type trunkError struct{}
+
+func (trunkError) Error() string { return "branch is trunk" }
+
+func (trunkError) Is(target error) bool {
+	return target == state.ErrNotExist
+}
+
 // LookupBranch returns information about a branch tracked by gs.
 //
 // It returns [git.ErrNotExist] if the branch is nt known to the repository,
+// [ErrTrunk] if the branch is the trunk branch,
 // [state.ErrNotExist] if the branch is not tracked,
 // or a [DeletedBranchError] if the branch is tracked, but was deleted out of band.
 func (s *Service) LookupBranch(ctx context.Context, name string) (*LookupBranchResponse, error) {
+	if name == s.store.Trunk() {
+		return nil, fmt.Errorf("lookup %v: %w", name, ErrTrunk)
+	}
+
 	resp, storeErr := s.store.LookupBranch(ctx, name)
 	head, gitErr := s.repo.PeelToCommit(ctx, name)
 
@@ -116,6 +159,7 @@ func (s *Service) LookupBranch(ctx context.Context, name string) (*LookupBranchR
 			Base:           resp.Base,
 			BaseHash:       resp.BaseHash,
 			UpstreamBranch: resp.UpstreamBranch,
+			UpstreamHash:   resp.UpstreamHash,
 			Head:           head,
 		}
 
@@ -144,6 +188,7 @@ func (s *Service) LookupBranch(ctx context.Context, name string) (*LookupBranchR
 					)
 				} else {
 					out.Change = md
+					out.ChangeURL = resp.ChangeURL
 				}
 			}
 		}
@@ -187,6 +232,17 @@ func (s *Service) LookupBranch(ctx context.Context, name string) (*LookupBranchR
 // ForgetBranch stops tracking a branch,
 // updating the upstacks for it to point to its base.
 func (s *Service) ForgetBranch(ctx context.Context, name string) error {
+	return s.forgetBranch(ctx, name, false /* archive */)
+}
+
+// ArchiveBranch is like ForgetBranch,
+// but it keeps the branch's information in the store's archive
+// so that it can be restored later.
+func (s *Service) ArchiveBranch(ctx context.Context, name string) error {
+	return s.forgetBranch(ctx, name, true /* archive */)
+}
+
+func (s *Service) forgetBranch(ctx context.Context, name string, archive bool) error {
 	// This does not use LookupBranch because we don't care if the branch
 	// doesn't actually exist, we just want to update the upstacks.
 	branch, err := s.store.LookupBranch(ctx, name)
@@ -206,7 +262,12 @@ func (s *Service) ForgetBranch(ctx context.Context, name string) error {
 
 	update := state.UpdateRequest{
 		Message: fmt.Sprintf("untrack branch %q", name),
-		Deletes: []string{name},
+	}
+	if archive {
+		update.Message = fmt.Sprintf("archive branch %q", name)
+		update.Archives = []string{name}
+	} else {
+		update.Deletes = []string{name}
 	}
 	for _, candidate := range branchNames {
 		if candidate == name {
@@ -240,8 +301,7 @@ func (s *Service) ForgetBranch(ctx context.Context, name string) error {
 // This handles both, renaming the branch in the repository,
 // and updating the internal state to reflect the new name.
 func (s *Service) RenameBranch(ctx context.Context, oldName, newName string) error {
-	oldBranch, err := s.LookupBranch(ctx, oldName)
-	if err != nil {
+	if _, err := s.LookupBranch(ctx, oldName); err != nil {
 		return fmt.Errorf("lookup %v: %w", oldName, err)
 	}
 
@@ -263,46 +323,7 @@ func (s *Service) RenameBranch(ctx context.Context, oldName, newName string) err
 		return fmt.Errorf("rename branch: %w", err)
 	}
 
-	var (
-		changeForge    string
-		changeMetadata json.RawMessage
-	)
-	if md := oldBranch.Change; md != nil {
-		if f, ok := forge.Lookup(md.ForgeID()); ok {
-			changeForge = f.ID()
-			changeMetadata, err = f.MarshalChangeMetadata(md)
-			if err != nil {
-				return fmt.Errorf("marshal change metadata: %w", err)
-			}
-		}
-	}
-
-	update := state.UpdateRequest{
-		Message: fmt.Sprintf("rename %q to %q", oldName, newName),
-		Deletes: []string{oldName},
-		Upserts: []state.UpsertRequest{
-			{
-				Name:           newName,
-				Base:           oldBranch.Base,
-				BaseHash:       oldBranch.BaseHash,
-				ChangeForge:    changeForge,
-				ChangeMetadata: changeMetadata,
-				UpstreamBranch: oldBranch.UpstreamBranch,
-			},
-		},
-	}
-	for _, above := range aboves {
-		update.Upserts = append(update.Upserts, state.UpsertRequest{
-			Name: above,
-			Base: newName,
-		})
-	}
-
-	if err := s.store.UpdateBranch(ctx, &update); err != nil {
-		return fmt.Errorf("update state: %w", err)
-	}
-
-	return nil
+	return s.renameBranchState(ctx, oldName, newName, aboves)
 }
 
 // LoadBranchItem is a single branch returned by LoadBranches.
@@ -324,6 +345,9 @@ type LoadBranchItem struct {
 	// This is nil if the branch has not been published.
 	Change forge.ChangeMetadata
 
+	// ChangeURL is the last known web URL of the change, if any.
+	ChangeURL string
+
 	// UpstreamBranch is the name under which this branch
 	// was pushed to the upstream repository.
 	UpstreamBranch string
@@ -363,6 +387,7 @@ func (s *Service) LoadBranches(ctx context.Context) ([]LoadBranchItem, error) {
 			BaseHash:       resp.BaseHash,
 			UpstreamBranch: resp.UpstreamBranch,
 			Change:         resp.Change,
+			ChangeURL:      resp.ChangeURL,
 		})
 	}
 
@@ -474,6 +499,52 @@ func (s *Service) ListUpstack(ctx context.Context, start string) ([]string, erro
 	return upstacks, nil
 }
 
+// BranchNode is a branch in a tree of branches
+// returned by [Service.UpstackTree].
+type BranchNode struct {
+	// Name is the name of the branch.
+	Name string
+
+	// Children are the branches that have this branch as their base,
+	// sorted by name.
+	Children []*BranchNode
+}
+
+// UpstackTree returns the tree of branches upstack from the given branch,
+// rooted at that branch.
+//
+// The tree is built from a single pass over tracked branches,
+// so callers that need to walk the upstack repeatedly
+// should prefer this over calling ListAbove for each branch.
+func (s *Service) UpstackTree(ctx context.Context, start string) (*BranchNode, error) {
+	branchesByBase, err := s.branchesByBase(ctx) // base -> [branches]
+	if err != nil {
+		return nil, err
+	}
+
+	root := &BranchNode{Name: start}
+	seen := map[string]struct{}{start: {}}
+	remaining := []*BranchNode{root}
+	for len(remaining) > 0 {
+		var node *BranchNode
+		node, remaining = remaining[0], remaining[1:]
+
+		for _, name := range branchesByBase[node.Name] {
+			// Guard against corrupted state with a cycle in it.
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+
+			child := &BranchNode{Name: name}
+			node.Children = append(node.Children, child)
+			remaining = append(remaining, child)
+		}
+	}
+
+	return root, nil
+}
+
 // FindTop returns the topmost branches in each upstack chain
 // starting at the given branch.
 func (s *Service) FindTop(ctx context.Context, start string) ([]string, error) {
@@ -664,3 +735,74 @@ func (s *Service) ListStackLinear(ctx context.Context, start string) ([]string,
 
 	return slices.Concat(downstacks, upstacks), nil
 }
+
+// ResolveLiveBase returns the closest branch below the given branch,
+// following bases downstack,
+// that was not deleted and whose CR was not merged.
+// If multiple branches in the chain were merged or deleted,
+// all of them are skipped.
+// Returns trunk if there's no such branch,
+// or if the chain reaches a branch that isn't tracked.
+//
+// Whether a CR is merged is checked with the given forge repository.
+func (s *Service) ResolveLiveBase(ctx context.Context, remoteRepo forge.Repository, name string) (string, error) {
+	base, err := s.branchBase(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	trunk := s.store.Trunk()
+	seen := map[string]struct{}{name: {}}
+	for base != trunk {
+		if _, ok := seen[base]; ok {
+			return "", fmt.Errorf("%v: base chain has a cycle at %v", name, base)
+		}
+		seen[base] = struct{}{}
+
+		b, err := s.LookupBranch(ctx, base)
+		if err != nil {
+			var deletedErr *DeletedBranchError
+			if errors.As(err, &deletedErr) {
+				s.log.Debugf("%v: branch was deleted", base)
+				base = deletedErr.Base
+				continue
+			}
+
+			// Not tracked and doesn't exist.
+			// We can't know what was below it.
+			s.log.Debugf("%v: not a tracked branch: %v", base, err)
+			return trunk, nil
+		}
+
+		if b.Change == nil {
+			return base, nil
+		}
+
+		merged, err := remoteRepo.ChangeIsMerged(ctx, b.Change.ChangeID())
+		if err != nil {
+			return "", fmt.Errorf("check if %v is merged: %w", b.Change.ChangeID(), err)
+		}
+		if !merged {
+			return base, nil
+		}
+
+		s.log.Debugf("%v: CR %v was merged", base, b.Change.ChangeID())
+		base = b.Base
+	}
+
+	return base, nil
+}
+
+// branchBase returns the recorded base of a tracked branch,
+// even if the branch was deleted out of band.
+func (s *Service) branchBase(ctx context.Context, name string) (string, error) {
+	b, err := s.LookupBranch(ctx, name)
+	if err != nil {
+		var deletedErr *DeletedBranchError
+		if errors.As(err, &deletedErr) {
+			return deletedErr.Base, nil
+		}
+		return "", fmt.Errorf("lookup %v: %w", name, err)
+	}
+	return b.Base, nil
+}
@@ -3,8 +3,10 @@ package spice
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,6 +27,8 @@ func TestGenerateBranchName(t *testing.T) {
 		{"Hello, World!", "hello-world"},
 		{"Long message that should be truncated", "long-message-that-should-be"},
 		{"1234 5678", "1234-5678"},
+		{"!!!", ""},
+		{"Supercalifragilisticexpialidocious-ly speaking", "supercalifragilisticexpialidocio"},
 	}
 
 	for _, tt := range tests {
@@ -59,6 +63,10 @@ func TestService_LookupBranch_changeAssociation(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		mockRepo := NewMockGitRepository(mockCtrl)
 		mockStore := NewMockStore(mockCtrl)
+		mockStore.EXPECT().
+			Trunk().
+			Return("main").
+			AnyTimes()
 
 		mockStore.EXPECT().
 			Remote().
@@ -116,6 +124,10 @@ func TestService_LookupBranch_changeAssociation(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		mockRepo := NewMockGitRepository(mockCtrl)
 		mockStore := NewMockStore(mockCtrl)
+		mockStore.EXPECT().
+			Trunk().
+			Return("main").
+			AnyTimes()
 
 		mockStore.EXPECT().
 			Remote().
@@ -145,3 +157,240 @@ func TestService_LookupBranch_changeAssociation(t *testing.T) {
 		assert.Nil(t, resp.Change)
 	})
 }
+
+func TestService_LookupBranch_trunk(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	mockRepo := NewMockGitRepository(mockCtrl)
+	mockStore := NewMockStore(mockCtrl)
+	mockStore.EXPECT().
+		Trunk().
+		Return("main").
+		AnyTimes()
+	mockStore.EXPECT().
+		Remote().
+		Return("", git.ErrNotExist).
+		AnyTimes()
+
+	svc := NewService(ctx, mockRepo, mockStore, logtest.New(t))
+
+	_, err := svc.LookupBranch(ctx, "main")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTrunk)
+	assert.ErrorIs(t, err, state.ErrNotExist)
+}
+
+func TestService_UpstackTree(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	mockRepo := NewMockGitRepository(mockCtrl)
+	mockStore := NewMockStore(mockCtrl)
+	mockStore.EXPECT().
+		Trunk().
+		Return("main").
+		AnyTimes()
+
+	mockStore.EXPECT().
+		Remote().
+		Return("", git.ErrNotExist).
+		AnyTimes()
+
+	// main
+	// └─feat1
+	//   ├─feat2
+	//   │ └─feat4
+	//   └─feat3
+	// other
+	bases := map[string]string{
+		"feat1": "main",
+		"feat2": "feat1",
+		"feat3": "feat1",
+		"feat4": "feat2",
+		"other": "main",
+	}
+	names := make([]string, 0, len(bases))
+	for name, base := range bases {
+		names = append(names, name)
+		mockStore.EXPECT().
+			LookupBranch(gomock.Any(), name).
+			Return(&state.LookupResponse{Base: base}, nil).
+			AnyTimes()
+		mockRepo.EXPECT().
+			PeelToCommit(gomock.Any(), name).
+			Return(git.Hash("abc123"), nil).
+			AnyTimes()
+	}
+	mockStore.EXPECT().
+		ListBranches(gomock.Any()).
+		Return(names, nil).
+		AnyTimes()
+
+	svc := NewService(ctx, mockRepo, mockStore, logtest.New(t))
+
+	t.Run("Tree", func(t *testing.T) {
+		tree, err := svc.UpstackTree(ctx, "feat1")
+		require.NoError(t, err)
+
+		assert.Equal(t, &BranchNode{
+			Name: "feat1",
+			Children: []*BranchNode{
+				{
+					Name: "feat2",
+					Children: []*BranchNode{
+						{Name: "feat4"},
+					},
+				},
+				{Name: "feat3"},
+			},
+		}, tree)
+	})
+
+	t.Run("Leaf", func(t *testing.T) {
+		tree, err := svc.UpstackTree(ctx, "feat3")
+		require.NoError(t, err)
+		assert.Equal(t, &BranchNode{Name: "feat3"}, tree)
+	})
+}
+
+func TestService_ResolveLiveBase(t *testing.T) {
+	ctx := context.Background()
+
+	shamhubForge := &shamhub.Forge{Log: logtest.New(t)}
+	t.Cleanup(forge.Register(shamhubForge))
+
+	// main
+	// └─feat1 (#1)
+	//   └─feat2 (#2)
+	//     └─feat3 (#3)
+	//       └─feat4 (#4)
+	type branchInfo struct {
+		base   string
+		change int
+	}
+	branches := map[string]branchInfo{
+		"feat1": {base: "main", change: 1},
+		"feat2": {base: "feat1", change: 2},
+		"feat3": {base: "feat2", change: 3},
+		"feat4": {base: "feat3", change: 4},
+	}
+
+	tests := []struct {
+		name string
+
+		merged    []int    // CRs that were merged
+		deleted   []string // branches deleted out of band
+		untracked []string // branches that aren't tracked
+		noChange  []string // branches that weren't submitted
+
+		want string
+	}{
+		{name: "NothingMerged", want: "feat3"},
+		{name: "BaseMerged", merged: []int{3}, want: "feat2"},
+		{
+			name:   "ChainMerged",
+			merged: []int{2, 3},
+			want:   "feat1",
+		},
+		{
+			name:   "AllMerged",
+			merged: []int{1, 2, 3},
+			want:   "main",
+		},
+		{
+			name:   "LowerMergedOnly",
+			merged: []int{1},
+			want:   "feat3",
+		},
+		{
+			name:    "DeletedAndMerged",
+			merged:  []int{2},
+			deleted: []string{"feat3"},
+			want:    "feat1",
+		},
+		{
+			name:    "AllDeleted",
+			deleted: []string{"feat1", "feat2", "feat3"},
+			want:    "main",
+		},
+		{
+			name:     "NotSubmitted",
+			merged:   []int{3},
+			noChange: []string{"feat2"},
+			want:     "feat2",
+		},
+		{
+			name:      "Untracked",
+			merged:    []int{3},
+			untracked: []string{"feat2"},
+			want:      "main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockRepo := NewMockGitRepository(mockCtrl)
+			mockStore := NewMockStore(mockCtrl)
+			mockStore.EXPECT().
+				Trunk().
+				Return("main").
+				AnyTimes()
+			mockStore.EXPECT().
+				Remote().
+				Return("", git.ErrNotExist).
+				AnyTimes()
+
+			for name, info := range branches {
+				if slices.Contains(tt.untracked, name) {
+					mockStore.EXPECT().
+						LookupBranch(gomock.Any(), name).
+						Return(nil, state.ErrNotExist).
+						AnyTimes()
+				} else {
+					resp := &state.LookupResponse{Base: info.base}
+					if !slices.Contains(tt.noChange, name) {
+						resp.ChangeForge = shamhubForge.ID()
+						resp.ChangeMetadata = json.RawMessage(
+							fmt.Sprintf(`{"number": %d}`, info.change))
+					}
+					mockStore.EXPECT().
+						LookupBranch(gomock.Any(), name).
+						Return(resp, nil).
+						AnyTimes()
+				}
+
+				if slices.Contains(tt.deleted, name) {
+					mockRepo.EXPECT().
+						PeelToCommit(gomock.Any(), name).
+						Return(git.Hash(""), git.ErrNotExist).
+						AnyTimes()
+				} else {
+					mockRepo.EXPECT().
+						PeelToCommit(gomock.Any(), name).
+						Return(git.Hash("abc123"), nil).
+						AnyTimes()
+				}
+			}
+
+			svc := NewService(ctx, mockRepo, mockStore, logtest.New(t))
+			remoteRepo := &mergedChangesRepository{merged: tt.merged}
+			got, err := svc.ResolveLiveBase(ctx, remoteRepo, "feat4")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// mergedChangesRepository is a forge.Repository
+// that reports only the given ShamHub CRs as merged.
+type mergedChangesRepository struct {
+	forge.Repository // unused; panics if called
+
+	merged []int
+}
+
+func (r *mergedChangesRepository) ChangeIsMerged(_ context.Context, id forge.ChangeID) (bool, error) {
+	return slices.Contains(r.merged, int(id.(shamhub.ChangeID))), nil
+}
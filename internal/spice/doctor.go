@@ -0,0 +1,160 @@
+package spice
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+)
+
+// OrphanedBranch is a tracked branch whose base no longer exists.
+type OrphanedBranch struct {
+	// Name is the name of the branch.
+	Name string
+
+	// Base is the recorded base of the branch.
+	// A branch with this name no longer exists in the repository.
+	Base string
+
+	// NewBase is the nearest ancestor of the branch in the stack
+	// that still exists, or the trunk if there isn't one.
+	NewBase string
+
+	// NewBaseHash is the last known hash of NewBase
+	// recorded in the state of the deleted branches
+	// between the branch and NewBase.
+	//
+	// This is empty if the branch's base hash should be kept.
+	NewBaseHash git.Hash
+}
+
+// BranchStateReport lists tracked branches whose state refers to
+// branches that were deleted without git-spice's knowledge.
+type BranchStateReport struct {
+	// Deleted lists tracked branches that no longer exist
+	// in the repository, sorted by name.
+	Deleted []string
+
+	// Orphans lists tracked branches that still exist
+	// but whose base does not, sorted by name.
+	Orphans []OrphanedBranch
+}
+
+// Empty reports whether the report found no problems.
+func (r *BranchStateReport) Empty() bool {
+	return len(r.Deleted) == 0 && len(r.Orphans) == 0
+}
+
+// CheckBranchState cross-references tracked branches
+// with local branches in the repository,
+// and reports tracked branches that no longer exist,
+// and tracked branches whose base no longer exists.
+//
+// Use [Service.RepairBranchState] to fix the reported problems.
+func (s *Service) CheckBranchState(ctx context.Context) (*BranchStateReport, error) {
+	// LoadBranches and ListAbove can't be used here:
+	// they forget branches that don't exist in the repository.
+	tracked, err := s.store.ListBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tracked branches: %w", err)
+	}
+	slices.Sort(tracked)
+
+	localBranches, err := s.repo.LocalBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list local branches: %w", err)
+	}
+	local := make(map[string]struct{}, len(localBranches))
+	for _, b := range localBranches {
+		local[b.Name] = struct{}{}
+	}
+
+	trunk := s.store.Trunk()
+	states := make(map[string]*state.LookupResponse, len(tracked))
+	for _, name := range tracked {
+		resp, err := s.store.LookupBranch(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("lookup %v: %w", name, err)
+		}
+		states[name] = resp
+	}
+
+	var report BranchStateReport
+	for _, name := range tracked {
+		if _, ok := local[name]; !ok {
+			report.Deleted = append(report.Deleted, name)
+			continue
+		}
+
+		base := states[name].Base
+		if _, ok := local[base]; ok || base == trunk {
+			continue
+		}
+
+		// Follow the recorded bases of deleted branches
+		// until we find one that still exists.
+		// If we run out of recorded state, fall back to trunk.
+		orphan := OrphanedBranch{Name: name, Base: base}
+		seen := make(map[string]struct{})
+		newBase := base
+		for {
+			if _, ok := local[newBase]; ok || newBase == trunk {
+				break
+			}
+
+			st, ok := states[newBase]
+			if _, cycle := seen[newBase]; !ok || cycle {
+				newBase = trunk
+				orphan.NewBaseHash = "" // recorded for a different base
+				break
+			}
+			seen[newBase] = struct{}{}
+
+			newBase = st.Base
+			orphan.NewBaseHash = st.BaseHash
+		}
+		orphan.NewBase = newBase
+		report.Orphans = append(report.Orphans, orphan)
+	}
+
+	return &report, nil
+}
+
+// RepairBranchState fixes the problems found by [Service.CheckBranchState]
+// in a single update:
+// deleted branches are forgotten,
+// and orphaned branches are moved onto their new bases.
+//
+// The branches in the repository are not changed.
+// Orphaned branches may need to be restacked afterwards.
+func (s *Service) RepairBranchState(ctx context.Context, report *BranchStateReport) error {
+	if report.Empty() {
+		return nil
+	}
+
+	update := state.UpdateRequest{
+		Deletes: report.Deleted,
+	}
+
+	var msgs []string
+	if len(report.Deleted) > 0 {
+		msgs = append(msgs, "forget deleted "+strings.Join(report.Deleted, ", "))
+	}
+	for _, o := range report.Orphans {
+		update.Upserts = append(update.Upserts, state.UpsertRequest{
+			Name:     o.Name,
+			Base:     o.NewBase,
+			BaseHash: o.NewBaseHash,
+		})
+		msgs = append(msgs, fmt.Sprintf("move %v onto %v", o.Name, o.NewBase))
+	}
+	update.Message = "repair branch state: " + strings.Join(msgs, "; ")
+
+	if err := s.store.UpdateBranch(ctx, &update); err != nil {
+		return fmt.Errorf("update state: %w", err)
+	}
+	return nil
+}
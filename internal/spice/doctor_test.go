@@ -0,0 +1,100 @@
+package spice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestService_CheckBranchState(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	mockRepo := NewMockGitRepository(mockCtrl)
+	mockStore := NewMockStore(mockCtrl)
+	mockStore.EXPECT().
+		Trunk().
+		Return("main").
+		AnyTimes()
+	mockStore.EXPECT().
+		Remote().
+		Return("", git.ErrNotExist).
+		AnyTimes()
+
+	// main
+	// └─feat1
+	//   └─feat2 (deleted)
+	//     └─feat3 (deleted)
+	//       └─feat4
+	// gone (untracked, deleted)
+	// └─feat5
+	// loop1 (deleted) <-> loop2 (deleted)
+	// └─feat6
+	branches := map[string]*state.LookupResponse{
+		"feat1": {Base: "main", BaseHash: "aaa"},
+		"feat2": {Base: "feat1", BaseHash: "bbb"},
+		"feat3": {Base: "feat2", BaseHash: "ccc"},
+		"feat4": {Base: "feat3", BaseHash: "ddd"},
+		"feat5": {Base: "gone", BaseHash: "eee"},
+		"loop1": {Base: "loop2", BaseHash: "111"},
+		"loop2": {Base: "loop1", BaseHash: "222"},
+		"feat6": {Base: "loop1", BaseHash: "fff"},
+	}
+	names := make([]string, 0, len(branches))
+	for name, resp := range branches {
+		names = append(names, name)
+		mockStore.EXPECT().
+			LookupBranch(gomock.Any(), name).
+			Return(resp, nil).
+			AnyTimes()
+	}
+	mockStore.EXPECT().
+		ListBranches(gomock.Any()).
+		Return(names, nil).
+		AnyTimes()
+
+	mockRepo.EXPECT().
+		LocalBranches(gomock.Any()).
+		Return([]git.LocalBranch{
+			{Name: "main"},
+			{Name: "feat1"},
+			{Name: "feat4"},
+			{Name: "feat5"},
+			{Name: "feat6"},
+			{Name: "untracked"},
+		}, nil)
+
+	svc := NewService(ctx, mockRepo, mockStore, logtest.New(t))
+
+	report, err := svc.CheckBranchState(ctx)
+	require.NoError(t, err)
+	assert.False(t, report.Empty())
+
+	assert.Equal(t, []string{"feat2", "feat3", "loop1", "loop2"}, report.Deleted)
+	assert.Equal(t, []OrphanedBranch{
+		{Name: "feat4", Base: "feat3", NewBase: "feat1", NewBaseHash: "bbb"},
+		{Name: "feat5", Base: "gone", NewBase: "main"},
+		{Name: "feat6", Base: "loop1", NewBase: "main"},
+	}, report.Orphans)
+
+	mockStore.EXPECT().
+		UpdateBranch(gomock.Any(), &state.UpdateRequest{
+			Deletes: []string{"feat2", "feat3", "loop1", "loop2"},
+			Upserts: []state.UpsertRequest{
+				{Name: "feat4", Base: "feat1", BaseHash: "bbb"},
+				{Name: "feat5", Base: "main"},
+				{Name: "feat6", Base: "main"},
+			},
+			Message: "repair branch state: " +
+				"forget deleted feat2, feat3, loop1, loop2; " +
+				"move feat4 onto feat1; move feat5 onto main; move feat6 onto main",
+		}).
+		Return(nil)
+	require.NoError(t, svc.RepairBranchState(ctx, report))
+}
@@ -3,6 +3,7 @@ package spice
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/must"
@@ -25,6 +26,10 @@ type BranchOntoRequest struct {
 // It DOES NOT modify the upstack branches of the branch being moved.
 // As this involves a rebase operation,
 // the caller should be prepared to rescue the operation if it fails.
+//
+// It's an error to move a branch onto itself or its upstack,
+// as that would make the branch its own base.
+// This is reported before the repository is changed.
 func (s *Service) BranchOnto(ctx context.Context, req *BranchOntoRequest) error {
 	must.NotBeEqualf(req.Branch, s.store.Trunk(), "cannot move trunk")
 
@@ -33,6 +38,20 @@ func (s *Service) BranchOnto(ctx context.Context, req *BranchOntoRequest) error
 		return fmt.Errorf("lookup branch: %w", err)
 	}
 
+	if req.Onto != s.store.Trunk() {
+		upstacks, err := s.ListUpstack(ctx, req.Branch)
+		if err != nil {
+			return fmt.Errorf("list upstack: %w", err)
+		}
+		if req.Onto == req.Branch {
+			return fmt.Errorf("cannot move %v onto itself", req.Branch)
+		}
+		if slices.Contains(upstacks, req.Onto) {
+			return fmt.Errorf("cannot move %v onto %v: it is upstack of %v",
+				req.Branch, req.Onto, req.Branch)
+		}
+	}
+
 	var ontoHash git.Hash
 	if req.Onto == s.store.Trunk() {
 		ontoHash, err = s.repo.PeelToCommit(ctx, req.Onto)
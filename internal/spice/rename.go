@@ -0,0 +1,230 @@
+package spice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+)
+
+// DetectedRename is a tracked branch that was likely renamed
+// without git-spice's knowledge.
+type DetectedRename struct {
+	// OldName is the name of the tracked branch.
+	// A branch with this name no longer exists in the repository.
+	OldName string
+
+	// NewName is the untracked branch that OldName was likely renamed to.
+	NewName string
+}
+
+// DetectRenames looks for tracked branches that no longer exist
+// in the repository, and guesses which untracked branches
+// they were renamed to with plain git.
+//
+// An untracked branch is a rename candidate for a missing branch if:
+//
+//   - its head matches the last known head of the missing branch
+//     (the hash it was last pushed at, or the base hash
+//     recorded by branches based on it); or
+//   - it forked from the base of the missing branch
+//     at the last known base hash.
+//
+// Head matches are preferred over fork point matches.
+// Missing branches with zero or multiple candidates are skipped.
+//
+// The returned renames are sorted by old name.
+// Use [Service.RenameTrackedBranch] to apply them.
+func (s *Service) DetectRenames(ctx context.Context) ([]DetectedRename, error) {
+	tracked, err := s.store.ListBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tracked branches: %w", err)
+	}
+	slices.Sort(tracked)
+
+	localBranches, err := s.repo.LocalBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list local branches: %w", err)
+	}
+	local := make(map[string]struct{}, len(localBranches))
+	for _, b := range localBranches {
+		local[b.Name] = struct{}{}
+	}
+
+	type trackedBranch struct {
+		Name  string
+		State *state.LookupResponse
+	}
+	var (
+		missing []trackedBranch
+		states  []trackedBranch // all tracked branches
+	)
+	for _, name := range tracked {
+		resp, err := s.store.LookupBranch(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("lookup %v: %w", name, err)
+		}
+
+		b := trackedBranch{Name: name, State: resp}
+		states = append(states, b)
+		if _, ok := local[name]; !ok {
+			missing = append(missing, b)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	type candidate struct {
+		Name string
+		Head git.Hash
+	}
+	var candidates []candidate
+	for _, b := range localBranches {
+		if b.Name == s.store.Trunk() || slices.Contains(tracked, b.Name) {
+			continue
+		}
+
+		head, err := s.repo.PeelToCommit(ctx, b.Name)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %v: %w", b.Name, err)
+		}
+		candidates = append(candidates, candidate{Name: b.Name, Head: head})
+	}
+
+	var renames []DetectedRename
+	for _, m := range missing {
+		// Known heads of the missing branch.
+		var heads []git.Hash
+		if h := m.State.UpstreamHash; h != "" {
+			heads = append(heads, h)
+		}
+		for _, b := range states {
+			if b.State.Base == m.Name && b.State.BaseHash != "" {
+				heads = append(heads, b.State.BaseHash)
+			}
+		}
+
+		// Current head of the base of the missing branch.
+		// The base may be missing too, in which case
+		// only head matches are possible.
+		baseHead, err := s.repo.PeelToCommit(ctx, m.State.Base)
+		if err != nil && !errors.Is(err, git.ErrNotExist) {
+			return nil, fmt.Errorf("resolve %v: %w", m.State.Base, err)
+		}
+
+		var headMatches, forkMatches []int
+		for idx, c := range candidates {
+			if slices.Contains(heads, c.Head) {
+				headMatches = append(headMatches, idx)
+				continue
+			}
+
+			baseHash := m.State.BaseHash
+			if baseHead == "" || baseHash == "" || c.Head == baseHash {
+				continue
+			}
+			if !s.repo.IsAncestor(ctx, baseHash, c.Head) {
+				continue
+			}
+			forkPoint, err := s.repo.MergeBase(ctx, baseHead.String(), c.Head.String())
+			if err == nil && forkPoint == baseHash {
+				forkMatches = append(forkMatches, idx)
+			}
+		}
+
+		matches := headMatches
+		if len(matches) == 0 {
+			matches = forkMatches
+		}
+		if len(matches) != 1 {
+			if len(matches) > 1 {
+				s.log.Debugf("%v: multiple branches could be its new name. Skipping.", m.Name)
+			}
+			continue
+		}
+
+		idx := matches[0]
+		renames = append(renames, DetectedRename{
+			OldName: m.Name,
+			NewName: candidates[idx].Name,
+		})
+		candidates = slices.Delete(candidates, idx, idx+1)
+	}
+
+	return renames, nil
+}
+
+// RenameTrackedBranch moves the state of a tracked branch to a new name
+// without touching the repository.
+// Branches based on the old name are updated to use the new name.
+//
+// Use this when a branch was renamed without git-spice's knowledge.
+// To rename a branch in the repository as well, use [Service.RenameBranch].
+func (s *Service) RenameTrackedBranch(ctx context.Context, oldName, newName string) error {
+	if _, err := s.store.LookupBranch(ctx, newName); err == nil {
+		return fmt.Errorf("branch %v is already tracked", newName)
+	}
+
+	// ListAbove can't be used here: it forgets
+	// branches that don't exist in the repository,
+	// which includes oldName.
+	tracked, err := s.store.ListBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("list tracked branches: %w", err)
+	}
+	var aboves []string
+	for _, name := range tracked {
+		b, err := s.store.LookupBranch(ctx, name)
+		if err != nil {
+			return fmt.Errorf("lookup %v: %w", name, err)
+		}
+		if b.Base == oldName {
+			aboves = append(aboves, name)
+		}
+	}
+
+	return s.renameBranchState(ctx, oldName, newName, aboves)
+}
+
+// renameBranchState moves the state of tracked branch oldName to newName,
+// and updates the given branches based on oldName to use newName.
+// The repository is not changed.
+func (s *Service) renameBranchState(ctx context.Context, oldName, newName string, aboves []string) error {
+	oldBranch, err := s.store.LookupBranch(ctx, oldName)
+	if err != nil {
+		return fmt.Errorf("lookup %v: %w", oldName, err)
+	}
+
+	update := state.UpdateRequest{
+		Message: fmt.Sprintf("rename %q to %q", oldName, newName),
+		Deletes: []string{oldName},
+		Upserts: []state.UpsertRequest{
+			{
+				Name:           newName,
+				Base:           oldBranch.Base,
+				BaseHash:       oldBranch.BaseHash,
+				ChangeForge:    oldBranch.ChangeForge,
+				ChangeMetadata: oldBranch.ChangeMetadata,
+				ChangeURL:      oldBranch.ChangeURL,
+				UpstreamBranch: oldBranch.UpstreamBranch,
+				UpstreamHash:   oldBranch.UpstreamHash,
+			},
+		},
+	}
+	for _, above := range aboves {
+		update.Upserts = append(update.Upserts, state.UpsertRequest{
+			Name: above,
+			Base: newName,
+		})
+	}
+
+	if err := s.store.UpdateBranch(ctx, &update); err != nil {
+		return fmt.Errorf("update state: %w", err)
+	}
+
+	return nil
+}
@@ -23,6 +23,57 @@ type RestackResponse struct {
 //
 // Returns [ErrAlreadyRestacked] if the branch does not need to be restacked.
 func (s *Service) Restack(ctx context.Context, name string) (*RestackResponse, error) {
+	res, err := s.restackChain(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RestackResponse{Base: res.Base}, nil
+}
+
+// RestackChainResponse is the response to a chained restack operation.
+type RestackChainResponse struct {
+	// Base is the base branch of the first branch in the chain.
+	Base string
+
+	// Branches is the list of branches that were restacked,
+	// starting with the branch that was requested.
+	// Each branch is based on the one before it.
+	Branches []string
+}
+
+// _minUpdateRefsVersion is the first Git version
+// that supports 'git rebase --update-refs'.
+var _minUpdateRefsVersion = git.Version{Major: 2, Minor: 38}
+
+// RestackChain restacks the given branch on top of its base branch,
+// along with the linear chain of branches above it,
+// with a single rebase operation.
+//
+// A branch above is part of the chain if
+// it is the only branch based on the previous branch in the chain,
+// it is already restacked on top of that branch,
+// and include reports true for it.
+// These branches would otherwise need to be restacked one at a time
+// after the first branch was restacked,
+// each requiring a separate checkout.
+//
+// This falls back to restacking only the given branch
+// if the installed version of Git is too old.
+//
+// Returns [ErrAlreadyRestacked] if the branch does not need to be restacked.
+func (s *Service) RestackChain(
+	ctx context.Context,
+	name string,
+	include func(string) bool,
+) (*RestackChainResponse, error) {
+	return s.restackChain(ctx, name, include)
+}
+
+func (s *Service) restackChain(
+	ctx context.Context,
+	name string,
+	include func(string) bool,
+) (*RestackChainResponse, error) {
 	b, err := s.LookupBranch(ctx, name)
 	if err != nil {
 		return nil, err // includes ErrNotExist
@@ -43,6 +94,102 @@ func (s *Service) Restack(ctx context.Context, name string) (*RestackResponse, e
 	// We will proceed with the restack.
 
 	baseHash := restackErr.BaseHash
+	upstream := s.restackUpstream(ctx, name, b, baseHash)
+
+	chain := []string{name}
+	if include != nil {
+		chain, err = s.restackableChain(ctx, name, include)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Recorded base hashes of the branches in the chain
+	// from before the rebase.
+	oldBaseHashes := make([]git.Hash, len(chain))
+	oldBaseHashes[0] = b.BaseHash
+	for i, branch := range chain[1:] {
+		bs, err := s.store.LookupBranch(ctx, branch)
+		if err != nil {
+			return nil, fmt.Errorf("lookup %v: %w", branch, err)
+		}
+		oldBaseHashes[i+1] = bs.BaseHash
+	}
+
+	// Rebasing the top of the chain with --update-refs
+	// moves the branches below it along with it.
+	if err := s.repo.Rebase(ctx, git.RebaseRequest{
+		Onto:       baseHash.String(),
+		Upstream:   upstream.String(),
+		Branch:     chain[len(chain)-1],
+		Autostash:  true,
+		Quiet:      true,
+		UpdateRefs: len(chain) > 1,
+	}); err != nil {
+		return nil, fmt.Errorf("rebase: %w", err)
+		// TODO: detect conflicts in rebase,
+		// print message about "gs rebase continue"
+	}
+
+	upserts := []state.UpsertRequest{
+		{
+			Name:     name,
+			BaseHash: baseHash,
+			// Don't clobber a concurrent restack of the same branch.
+			ExpectBaseHash: b.BaseHash,
+		},
+	}
+	restacked := []string{name}
+	prevHead, err := s.repo.PeelToCommit(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %v: %w", name, err)
+	}
+	for i, branch := range chain[1:] {
+		head, err := s.repo.PeelToCommit(ctx, branch)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %v: %w", branch, err)
+		}
+
+		// Git won't update a branch that is checked out
+		// in another worktree.
+		// Leave it and the branches above it to a regular restack.
+		if !s.repo.IsAncestor(ctx, prevHead, head) {
+			s.log.Debugf("%v: not updated by rebase", branch)
+			break
+		}
+
+		upserts = append(upserts, state.UpsertRequest{
+			Name:           branch,
+			BaseHash:       prevHead,
+			ExpectBaseHash: oldBaseHashes[i+1],
+		})
+		restacked = append(restacked, branch)
+		prevHead = head
+	}
+
+	err = s.store.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: upserts,
+		Message: fmt.Sprintf("%s: restacked on %s", name, b.Base),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update branch information: %w", err)
+	}
+
+	return &RestackChainResponse{
+		Base:     b.Base,
+		Branches: restacked,
+	}, nil
+}
+
+// restackUpstream returns the commit from which the given branch
+// should be rebased onto baseHash.
+// This is normally the recorded base hash of the branch.
+func (s *Service) restackUpstream(
+	ctx context.Context,
+	name string,
+	b *LookupBranchResponse,
+	baseHash git.Hash,
+) git.Hash {
 	upstream := b.BaseHash
 
 	// Case:
@@ -78,34 +225,102 @@ func (s *Service) Restack(ctx context.Context, name string) (*RestackResponse, e
 		}
 	}
 
+	return upstream
+}
+
+// RestackOnto rebases the given branch onto the given commit,
+// and records that commit as the new base hash of the branch.
+// The base branch of the branch is not changed.
+//
+// This may be used to restack a branch onto a commit
+// that its base branch will eventually point to,
+// e.g. the remote-tracking branch for its base.
+//
+// Returns [ErrAlreadyRestacked] if the branch is already on top of onto.
+// The base hash is updated in this case as well.
+func (s *Service) RestackOnto(ctx context.Context, name string, onto git.Hash) error {
+	b, err := s.LookupBranch(ctx, name)
+	if err != nil {
+		return err // includes ErrNotExist
+	}
+
+	if s.repo.IsAncestor(ctx, onto, b.Head) {
+		if b.BaseHash != onto {
+			err := s.store.UpdateBranch(ctx, &state.UpdateRequest{
+				Upserts: []state.UpsertRequest{{
+					Name:     name,
+					BaseHash: onto,
+				}},
+				Message: fmt.Sprintf("%s: update base hash to %s", name, onto.Short()),
+			})
+			if err != nil {
+				return fmt.Errorf("update branch information: %w", err)
+			}
+		}
+		return ErrAlreadyRestacked
+	}
+
+	upstream := s.restackUpstream(ctx, name, b, onto)
 	if err := s.repo.Rebase(ctx, git.RebaseRequest{
-		Onto:      baseHash.String(),
+		Onto:      onto.String(),
 		Upstream:  upstream.String(),
 		Branch:    name,
 		Autostash: true,
 		Quiet:     true,
 	}); err != nil {
-		return nil, fmt.Errorf("rebase: %w", err)
-		// TODO: detect conflicts in rebase,
-		// print message about "gs rebase continue"
+		return fmt.Errorf("rebase: %w", err)
 	}
 
 	err = s.store.UpdateBranch(ctx, &state.UpdateRequest{
-		Upserts: []state.UpsertRequest{
-			{
-				Name:     name,
-				BaseHash: baseHash,
-			},
-		},
-		Message: fmt.Sprintf("%s: restacked on %s", name, b.Base),
+		Upserts: []state.UpsertRequest{{
+			Name:     name,
+			BaseHash: onto,
+			// Don't clobber a concurrent restack of the same branch.
+			ExpectBaseHash: b.BaseHash,
+		}},
+		Message: fmt.Sprintf("%s: restacked on %s", name, onto.Short()),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("update branch information: %w", err)
+		return fmt.Errorf("update branch information: %w", err)
 	}
 
-	return &RestackResponse{
-		Base: b.Base,
-	}, nil
+	return nil
+}
+
+// restackableChain returns the linear chain of branches
+// starting at name that can be restacked with a single rebase.
+// See RestackChain for details.
+func (s *Service) restackableChain(
+	ctx context.Context,
+	name string,
+	include func(string) bool,
+) ([]string, error) {
+	chain := []string{name}
+	for {
+		aboves, err := s.ListAbove(ctx, chain[len(chain)-1])
+		if err != nil {
+			return nil, fmt.Errorf("list branches above %v: %w", name, err)
+		}
+		if len(aboves) != 1 || !include(aboves[0]) {
+			break
+		}
+
+		above := aboves[0]
+		if err := s.VerifyRestacked(ctx, above); err != nil {
+			break
+		}
+		chain = append(chain, above)
+	}
+
+	if len(chain) > 1 {
+		version, err := s.repo.Version(ctx)
+		if err != nil || !version.AtLeast(_minUpdateRefsVersion) {
+			s.log.Debugf("Restacking branches one at a time: git %v does not support --update-refs (%v)", version, err)
+			return chain[:1], nil
+		}
+	}
+
+	return chain, nil
 }
 
 // BranchNeedsRestackError is returned by [Service.VerifyRestacked]
@@ -167,7 +382,12 @@ func (s *Service) VerifyRestacked(ctx context.Context, name string) error {
 		}
 		if err := s.store.UpdateBranch(ctx, &req); err != nil {
 			// This isn't a critical error. Just log it.
-			s.log.Warnf("failed to update state with new base hash: %v", err)
+			// Read-only stores are expected to reject this.
+			if errors.Is(err, state.ErrReadOnly) {
+				s.log.Debugf("not updating base hash of %v: %v", name, err)
+			} else {
+				s.log.Warnf("failed to update state with new base hash: %v", err)
+			}
 		}
 	}
 
@@ -46,6 +46,7 @@ type GitRepository interface {
 	RenameBranch(context.Context, git.RenameBranchRequest) error
 	DeleteBranch(context.Context, string, git.BranchDeleteOptions) error
 	HashAt(context.Context, string, string) (git.Hash, error)
+	Version(context.Context) (git.Version, error)
 }
 
 var _ GitRepository = (*git.Repository)(nil)
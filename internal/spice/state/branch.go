@@ -17,6 +17,11 @@ import (
 
 const _branchesDir = "branches"
 
+// _archiveDir holds the state of branches that are no longer tracked
+// but were archived instead of being deleted.
+// Entries are stored with the same layout as _branchesDir.
+const _archiveDir = "archive"
+
 type branchStateBase struct {
 	Name string `json:"name"`
 	Hash string `json:"hash"`
@@ -24,6 +29,7 @@ type branchStateBase struct {
 
 type branchUpstreamState struct {
 	Branch string `json:"branch,omitempty"`
+	Hash   string `json:"hash,omitempty"`
 }
 
 type branchChangeState struct {
@@ -57,6 +63,10 @@ type branchState struct {
 	Base     branchStateBase      `json:"base"`
 	Upstream *branchUpstreamState `json:"upstream,omitempty"`
 	Change   *branchChangeState   `json:"change,omitempty"`
+
+	// ChangeURL is the last known web URL of the change,
+	// cached so that it can be shown without querying the forge.
+	ChangeURL string `json:"changeURL,omitempty"`
 }
 
 // branchJSON returns the path to the JSON file for the given branch
@@ -65,6 +75,12 @@ func (s *Store) branchJSON(name string) string {
 	return path.Join(_branchesDir, name)
 }
 
+// archiveJSON returns the path to the JSON file
+// for the given archived branch relative to the store's root.
+func (s *Store) archiveJSON(name string) string {
+	return path.Join(_archiveDir, name)
+}
+
 // ErrNotExist indicates that a key that was expected to exist does not exist.
 var ErrNotExist = storage.ErrNotExist
 
@@ -85,9 +101,17 @@ type LookupResponse struct {
 	// ChangeForge is the forge that the change was published to.
 	ChangeForge string
 
+	// ChangeURL is the last known web URL of the published change,
+	// or an empty string if it isn't known.
+	ChangeURL string
+
 	// UpstreamBranch is the name of the upstream branch
 	// or an empty string if the branch is not tracking an upstream branch.
 	UpstreamBranch string
+
+	// UpstreamHash is the hash of the commit that was last pushed
+	// to the upstream branch, if known.
+	UpstreamHash git.Hash
 }
 
 // LookupBranch returns information about a tracked branch.
@@ -97,22 +121,27 @@ func (s *Store) LookupBranch(ctx context.Context, name string) (*LookupResponse,
 	if err != nil {
 		return nil, err
 	}
+	return state.lookupResponse(), nil
+}
 
+func (bs *branchState) lookupResponse() *LookupResponse {
 	res := &LookupResponse{
-		Base:     state.Base.Name,
-		BaseHash: git.Hash(state.Base.Hash),
+		Base:     bs.Base.Name,
+		BaseHash: git.Hash(bs.Base.Hash),
 	}
 
-	if change := state.Change; change != nil {
+	if change := bs.Change; change != nil {
 		res.ChangeMetadata = change.Change
 		res.ChangeForge = change.Forge
+		res.ChangeURL = bs.ChangeURL
 	}
 
-	if upstream := state.Upstream; upstream != nil {
+	if upstream := bs.Upstream; upstream != nil {
 		res.UpstreamBranch = upstream.Branch
+		res.UpstreamHash = git.Hash(upstream.Hash)
 	}
 
-	return res, nil
+	return res
 }
 
 func (s *Store) lookupBranchState(ctx context.Context, name string) (*branchState, error) {
@@ -142,6 +171,23 @@ type UpdateRequest struct {
 	// Deletes are requests to delete information about branches.
 	Deletes []string
 
+	// Archives are requests to stop tracking branches,
+	// but keep their information in an archive.
+	// Archived branches are not reported by ListBranches.
+	// Use ListArchived to list them, and Restore to track them again.
+	//
+	// Archiving a branch replaces any information
+	// archived for it previously.
+	Archives []string
+
+	// Restores are requests to track archived branches again
+	// with the information archived for them,
+	// removing them from the archive.
+	//
+	// It's an error to restore a branch that is not archived,
+	// or one that is already tracked.
+	Restores []string
+
 	// Message is a message specifying the reason for the update.
 	// This will be persisted in the Git commit message.
 	Message string
@@ -163,6 +209,15 @@ type UpsertRequest struct {
 	// Leave empty to keep the current base hash.
 	BaseHash git.Hash
 
+	// ExpectBaseHash, if set, is the base hash
+	// that the branch is expected to have in the store.
+	// If the stored base hash differs, the update fails
+	// with a [*BaseHashMismatchError].
+	//
+	// Use this to avoid losing updates made concurrently
+	// by another operation.
+	ExpectBaseHash git.Hash
+
 	// ChangeMetadata is arbitrary, forge-specific metadata
 	// recorded with the branch.
 	//
@@ -174,9 +229,38 @@ type UpsertRequest struct {
 	// If ChangeMetadata is set, this must also be set.
 	ChangeForge string
 
+	// ChangeURL is the web URL of the change.
+	//
+	// Leave empty to keep the current URL.
+	ChangeURL string
+
 	// UpstreamBranch is the name of the upstream branch to track.
 	// Leave empty to stop tracking an upstream branch.
 	UpstreamBranch string
+
+	// UpstreamHash is the hash of the commit
+	// that was last pushed to the upstream branch.
+	// The branch must have an upstream branch to set this.
+	//
+	// Leave empty to keep the current value.
+	UpstreamHash git.Hash
+}
+
+// BaseHashMismatchError is returned by [Store.UpdateBranch]
+// when a branch's base hash in the store does not match
+// [UpsertRequest.ExpectBaseHash].
+type BaseHashMismatchError struct {
+	// Branch is the name of the branch.
+	Branch string
+
+	// Want is the expected base hash,
+	// and Got is the base hash in the store.
+	Want, Got git.Hash
+}
+
+func (e *BaseHashMismatchError) Error() string {
+	return fmt.Sprintf("branch %v: base hash changed: expected %v, got %v",
+		e.Branch, e.Want.Short(), e.Got.Short())
 }
 
 // UpdateBranch upates the store with the parameters in the request.
@@ -203,6 +287,16 @@ func (s *Store) UpdateBranch(ctx context.Context, req *UpdateRequest) error {
 			b = &branchState{}
 		}
 
+		if want := req.ExpectBaseHash; want != "" {
+			if got := git.Hash(b.Base.Hash); got != want {
+				return &BaseHashMismatchError{
+					Branch: req.Name,
+					Want:   want,
+					Got:    got,
+				}
+			}
+		}
+
 		if req.Base != "" {
 			b.Base.Name = req.Base
 		}
@@ -217,13 +311,25 @@ func (s *Store) UpdateBranch(ctx context.Context, req *UpdateRequest) error {
 				Change: req.ChangeMetadata,
 			}
 		}
+		if req.ChangeURL != "" {
+			b.ChangeURL = req.ChangeURL
+		}
 
 		if req.UpstreamBranch != "" {
-			b.Upstream = &branchUpstreamState{
-				Branch: req.UpstreamBranch,
+			if b.Upstream == nil || b.Upstream.Branch != req.UpstreamBranch {
+				b.Upstream = &branchUpstreamState{
+					Branch: req.UpstreamBranch,
+				}
 			}
 		}
 
+		if req.UpstreamHash != "" {
+			if b.Upstream == nil {
+				return fmt.Errorf("branch %q (%d) has no upstream branch", req.Name, i)
+			}
+			b.Upstream.Hash = req.UpstreamHash.String()
+		}
+
 		if b.Base.Name == "" {
 			return fmt.Errorf("branch %q (%d) would have no base", req.Name, i)
 		}
@@ -234,9 +340,41 @@ func (s *Store) UpdateBranch(ctx context.Context, req *UpdateRequest) error {
 		})
 	}
 
-	deletes := make([]string, len(req.Deletes))
-	for i, name := range req.Deletes {
-		deletes[i] = s.branchJSON(name)
+	deletes := make([]string, 0, len(req.Deletes)+len(req.Archives))
+	for _, name := range req.Deletes {
+		deletes = append(deletes, s.branchJSON(name))
+	}
+
+	for i, name := range req.Archives {
+		b, err := s.lookupBranchState(ctx, name)
+		if err != nil {
+			return fmt.Errorf("archive [%d]: %w", i, err)
+		}
+
+		sets = append(sets, storage.SetRequest{
+			Key:   s.archiveJSON(name),
+			Value: b,
+		})
+		deletes = append(deletes, s.branchJSON(name))
+	}
+
+	for i, name := range req.Restores {
+		if _, err := s.lookupBranchState(ctx, name); err == nil {
+			return fmt.Errorf("restore [%d]: branch %q is already tracked", i, name)
+		} else if !errors.Is(err, ErrNotExist) {
+			return fmt.Errorf("restore [%d]: get branch: %w", i, err)
+		}
+
+		var b branchState
+		if err := s.db.Get(ctx, s.archiveJSON(name), &b); err != nil {
+			return fmt.Errorf("restore [%d]: get archived branch state: %w", i, err)
+		}
+
+		sets = append(sets, storage.SetRequest{
+			Key:   s.branchJSON(name),
+			Value: &b,
+		})
+		deletes = append(deletes, s.archiveJSON(name))
 	}
 
 	err := s.db.Update(ctx, storage.UpdateRequest{
@@ -250,3 +388,37 @@ func (s *Store) UpdateBranch(ctx context.Context, req *UpdateRequest) error {
 
 	return nil
 }
+
+// ListArchived reports the names of all archived branches.
+// The list is sorted in lexicographic order.
+func (s *Store) ListArchived(ctx context.Context) ([]string, error) {
+	branches, err := s.db.Keys(ctx, _archiveDir)
+	if err != nil {
+		return nil, fmt.Errorf("list archived branches: %w", err)
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// LookupArchived returns the information archived for a branch.
+// If the branch is not archived, [ErrNotExist] will be returned.
+func (s *Store) LookupArchived(ctx context.Context, name string) (*LookupResponse, error) {
+	var state branchState
+	if err := s.db.Get(ctx, s.archiveJSON(name), &state); err != nil {
+		return nil, fmt.Errorf("get archived branch state: %w", err)
+	}
+	return state.lookupResponse(), nil
+}
+
+// Restore starts tracking an archived branch again
+// with the information that was archived for it,
+// and removes it from the archive.
+//
+// If the branch is not archived, [ErrNotExist] will be returned.
+// It's an error to restore a branch that is already tracked.
+func (s *Store) Restore(ctx context.Context, name string) error {
+	return s.UpdateBranch(ctx, &UpdateRequest{
+		Restores: []string{name},
+		Message:  fmt.Sprintf("restore branch %q", name),
+	})
+}
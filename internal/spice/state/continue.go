@@ -86,6 +86,16 @@ func (s *Store) TakeContinuations(ctx context.Context, msg string) ([]Continuati
 	return conts, nil
 }
 
+// HasContinuations reports whether there are rebase continuations
+// recorded in the store without removing them.
+func (s *Store) HasContinuations(ctx context.Context) (bool, error) {
+	state, err := s.getRebaseContinueState(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(state.Continuations) > 0, nil
+}
+
 func (s *Store) getRebaseContinueState(ctx context.Context) (*rebaseContinueState, error) {
 	var state rebaseContinueState
 	if err := s.db.Get(ctx, _rebaseContinueJSON, &state); err != nil {
@@ -0,0 +1,186 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.abhg.dev/gs/internal/storage"
+)
+
+// exportDocument is the JSON document written by [Store.Export]
+// and read by [Store.Import].
+type exportDocument struct {
+	Repo     repoInfo                `json:"repo"`
+	Branches map[string]*branchState `json:"branches"`
+}
+
+// Export writes the repository information and all tracked branches
+// to w as a single JSON document.
+// The document may be loaded into another store with [Store.Import].
+func (s *Store) Export(ctx context.Context, w io.Writer) error {
+	var doc exportDocument
+	if err := s.db.Get(ctx, _repoJSON, &doc.Repo); err != nil {
+		return fmt.Errorf("get repo info: %w", err)
+	}
+
+	names, err := s.ListBranches(ctx)
+	if err != nil {
+		return err
+	}
+
+	doc.Branches = make(map[string]*branchState, len(names))
+	for _, name := range names {
+		b, err := s.lookupBranchState(ctx, name)
+		if err != nil {
+			return fmt.Errorf("get branch %q: %w", name, err)
+		}
+		doc.Branches[name] = b
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	return nil
+}
+
+// ErrWouldOverwrite indicates that an import was refused
+// because the store already tracks branches.
+var ErrWouldOverwrite = errors.New("store already tracks branches")
+
+// ImportOptions configures [Store.Import].
+type ImportOptions struct {
+	// Force allows importing into a store that already tracks branches.
+	// All tracked branches are replaced with the imported branches.
+	Force bool
+}
+
+// Import replaces the repository information and tracked branches
+// with those in a JSON document written by [Store.Export].
+//
+// The imported branches must form a tree rooted at the imported trunk.
+// Import returns [ErrWouldOverwrite] if the store already tracks branches,
+// unless [ImportOptions.Force] is set.
+// Archived branches and other state are left unchanged.
+func (s *Store) Import(ctx context.Context, r io.Reader, opts *ImportOptions) error {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+
+	var doc exportDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	info := doc.Repo
+	if err := info.Validate(); err != nil {
+		return fmt.Errorf("invalid repo info: %w", err)
+	}
+	if err := checkVersion(info.Version, _storeVersion); err != nil {
+		return err
+	}
+	if err := validateBranchTree(info.Trunk, doc.Branches); err != nil {
+		return err
+	}
+
+	existing, err := s.ListBranches(ctx)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 && !opts.Force {
+		return fmt.Errorf("%w: %v", ErrWouldOverwrite, strings.Join(existing, ", "))
+	}
+
+	names := make([]string, 0, len(doc.Branches))
+	for name := range doc.Branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sets := make([]storage.SetRequest, 0, len(names)+1)
+	sets = append(sets, storage.SetRequest{Key: _repoJSON, Value: info})
+	for _, name := range names {
+		sets = append(sets, storage.SetRequest{
+			Key:   s.branchJSON(name),
+			Value: doc.Branches[name],
+		})
+	}
+
+	var deletes []string
+	for _, name := range existing {
+		if _, ok := doc.Branches[name]; !ok {
+			deletes = append(deletes, s.branchJSON(name))
+		}
+	}
+
+	err = s.db.Update(ctx, storage.UpdateRequest{
+		Sets:    sets,
+		Deletes: deletes,
+		Message: fmt.Sprintf("import %d branches", len(names)),
+	})
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	// Documents exported by older versions of gs
+	// are upgraded in place after they're written.
+	if err := migrateStore(ctx, s.db, s.log, &info, _migrations, _storeVersion); err != nil {
+		return err
+	}
+
+	s.trunk = info.Trunk
+	s.remote = info.Remote
+	return nil
+}
+
+// validateBranchTree verifies that every branch has a base
+// that is either the trunk or another branch in the set,
+// and that following bases from any branch eventually reaches the trunk.
+func validateBranchTree(trunk string, branches map[string]*branchState) error {
+	names := make([]string, 0, len(branches))
+	for name, b := range branches {
+		if name == "" {
+			return errors.New("branch name is empty")
+		}
+		if name == trunk {
+			return fmt.Errorf("trunk branch (%q) is not allowed", name)
+		}
+		if b == nil || b.Base.Name == "" {
+			return fmt.Errorf("branch %q has no base", name)
+		}
+		if _, ok := branches[b.Base.Name]; !ok && b.Base.Name != trunk {
+			return fmt.Errorf("branch %q: base %q is not tracked", name, b.Base.Name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names) // for deterministic error messages
+
+	// Branches known to reach the trunk.
+	rooted := make(map[string]struct{}, len(branches))
+	for _, name := range names {
+		var path []string
+		seen := make(map[string]struct{})
+		for cur := name; cur != trunk; cur = branches[cur].Base.Name {
+			if _, ok := rooted[cur]; ok {
+				break
+			}
+			if _, ok := seen[cur]; ok {
+				return fmt.Errorf("branch %q: cycle in bases: %v", name, strings.Join(append(path, cur), " -> "))
+			}
+			seen[cur] = struct{}{}
+			path = append(path, cur)
+		}
+
+		for _, p := range path {
+			rooted[p] = struct{}{}
+		}
+	}
+
+	return nil
+}
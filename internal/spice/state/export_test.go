@@ -0,0 +1,186 @@
+package state_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/storage"
+)
+
+func TestStoreExportImport(t *testing.T) {
+	ctx := context.Background()
+
+	src := newTestStore(t, "main", "origin")
+	require.NoError(t, src.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{
+				Name:           "foo",
+				Base:           "main",
+				BaseHash:       "abcdef",
+				ChangeForge:    "shamhub",
+				ChangeMetadata: json.RawMessage(`{"number": 1}`),
+				ChangeURL:      "https://example.com/1",
+				UpstreamBranch: "foo",
+			},
+			{Name: "bar", Base: "foo", BaseHash: "123456"},
+		},
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, src.Export(ctx, &buf))
+
+	t.Run("Empty", func(t *testing.T) {
+		dst := newTestStore(t, "trunk", "")
+		require.NoError(t, dst.Import(ctx, bytes.NewReader(buf.Bytes()), nil))
+
+		assert.Equal(t, "main", dst.Trunk())
+		remote, err := dst.Remote()
+		require.NoError(t, err)
+		assert.Equal(t, "origin", remote)
+
+		names, err := dst.ListBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bar", "foo"}, names)
+
+		foo, err := dst.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "main", foo.Base)
+		assert.Equal(t, "abcdef", string(foo.BaseHash))
+		assert.Equal(t, "shamhub", foo.ChangeForge)
+		assert.JSONEq(t, `{"number": 1}`, string(foo.ChangeMetadata))
+		assert.Equal(t, "https://example.com/1", foo.ChangeURL)
+		assert.Equal(t, "foo", foo.UpstreamBranch)
+
+		bar, err := dst.LookupBranch(ctx, "bar")
+		require.NoError(t, err)
+		assert.Equal(t, "foo", bar.Base)
+
+		// Exporting again yields the same document.
+		var got bytes.Buffer
+		require.NoError(t, dst.Export(ctx, &got))
+		assert.JSONEq(t, buf.String(), got.String())
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		dst := newTestStore(t, "main", "")
+		require.NoError(t, dst.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{
+				{Name: "foo", Base: "main"},
+				{Name: "qux", Base: "main"},
+			},
+		}))
+
+		err := dst.Import(ctx, bytes.NewReader(buf.Bytes()), nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, state.ErrWouldOverwrite)
+		assert.ErrorContains(t, err, "foo, qux")
+
+		// Nothing changed.
+		names, err := dst.ListBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo", "qux"}, names)
+
+		require.NoError(t, dst.Import(ctx, bytes.NewReader(buf.Bytes()), &state.ImportOptions{
+			Force: true,
+		}))
+
+		names, err = dst.ListBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bar", "foo"}, names)
+
+		foo, err := dst.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "abcdef", string(foo.BaseHash))
+	})
+}
+
+func TestStoreImport_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    string
+		wantErr string
+	}{
+		{
+			name:    "NotJSON",
+			give:    `not json`,
+			wantErr: "decode",
+		},
+		{
+			name:    "NoTrunk",
+			give:    `{"repo": {}, "branches": {}}`,
+			wantErr: "trunk branch name is empty",
+		},
+		{
+			name:    "NewerVersion",
+			give:    `{"repo": {"trunk": "main", "version": 1000}}`,
+			wantErr: "state version 1000 is newer",
+		},
+		{
+			name: "TrunkTracked",
+			give: `{
+				"repo": {"trunk": "main"},
+				"branches": {"main": {"base": {"name": "foo"}}}
+			}`,
+			wantErr: `trunk branch ("main") is not allowed`,
+		},
+		{
+			name: "NoBase",
+			give: `{
+				"repo": {"trunk": "main"},
+				"branches": {"foo": {"base": {}}}
+			}`,
+			wantErr: `branch "foo" has no base`,
+		},
+		{
+			name: "UntrackedBase",
+			give: `{
+				"repo": {"trunk": "main"},
+				"branches": {"foo": {"base": {"name": "bar"}}}
+			}`,
+			wantErr: `branch "foo": base "bar" is not tracked`,
+		},
+		{
+			name: "Cycle",
+			give: `{
+				"repo": {"trunk": "main"},
+				"branches": {
+					"a": {"base": {"name": "main"}},
+					"b": {"base": {"name": "c"}},
+					"c": {"base": {"name": "d"}},
+					"d": {"base": {"name": "b"}}
+				}
+			}`,
+			wantErr: `branch "b": cycle in bases: b -> c -> d -> b`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t, "main", "")
+
+			err := store.Import(context.Background(), strings.NewReader(tt.give), nil)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func newTestStore(t *testing.T, trunk, remote string) *state.Store {
+	t.Helper()
+
+	store, err := state.InitStore(context.Background(), state.InitStoreRequest{
+		DB:     storage.NewDB(storage.NewMemBackend()),
+		Trunk:  trunk,
+		Remote: remote,
+		Log:    logtest.New(t),
+	})
+	require.NoError(t, err)
+	return store
+}
@@ -0,0 +1,111 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/storage"
+)
+
+// _storeVersion is the version of the state store schema
+// written by this version of gs.
+//
+// Bump this and add an entry to _migrations
+// whenever the layout of the stored state changes.
+const _storeVersion = 1
+
+// ErrUnsupportedVersion indicates that the store was written
+// by a newer version of gs than the one reading it.
+var ErrUnsupportedVersion = errors.New("unsupported state version")
+
+// migration upgrades the store to a specific schema version.
+type migration struct {
+	// Version is the store version after this migration is applied.
+	Version int
+
+	// Description is a short, human-readable description
+	// of what the migration does.
+	// It's used in the commit message for the migration.
+	Description string
+
+	// Migrate returns the changes to apply to the store.
+	// The runner adds the updated repository information
+	// and the commit message to the request.
+	//
+	// May be nil if the migration only bumps the version.
+	Migrate func(ctx context.Context, db DB) (storage.UpdateRequest, error)
+}
+
+// _migrations lists all schema migrations in version order.
+// The last entry must have Version equal to _storeVersion.
+var _migrations = []migration{
+	{
+		Version:     1,
+		Description: "record state version",
+	},
+}
+
+// migrateStore upgrades the store described by info to the target version
+// by applying all migrations newer than the store's current version.
+// Each migration is committed separately along with the new version
+// so that an interrupted upgrade resumes where it left off.
+//
+// info is updated in place to reflect the new version.
+func migrateStore(
+	ctx context.Context,
+	db DB,
+	log *log.Logger,
+	info *repoInfo,
+	migrations []migration,
+	target int,
+) error {
+	if err := checkVersion(info.Version, target); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= info.Version {
+			continue
+		}
+		if m.Version > target {
+			break
+		}
+
+		var req storage.UpdateRequest
+		if m.Migrate != nil {
+			var err error
+			req, err = m.Migrate(ctx, db)
+			if err != nil {
+				return fmt.Errorf("migrate to version %d: %w", m.Version, err)
+			}
+		}
+
+		newInfo := *info
+		newInfo.Version = m.Version
+		req.Sets = append(req.Sets, storage.SetRequest{
+			Key:   _repoJSON,
+			Value: newInfo,
+		})
+		req.Message = fmt.Sprintf("migrate state to version %d: %s", m.Version, m.Description)
+		if err := db.Update(ctx, req); err != nil {
+			return fmt.Errorf("migrate to version %d: %w", m.Version, err)
+		}
+
+		log.Debugf("Upgraded state to version %d: %s", m.Version, m.Description)
+		*info = newInfo
+	}
+
+	return nil
+}
+
+// checkVersion reports an error if a store at the given version
+// cannot be upgraded to the target version.
+func checkVersion(version, target int) error {
+	if version > target {
+		return fmt.Errorf("%w: state version %d is newer than supported version %d; upgrade gs",
+			ErrUnsupportedVersion, version, target)
+	}
+	return nil
+}
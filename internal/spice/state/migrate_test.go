@@ -0,0 +1,136 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/storage"
+)
+
+func TestMigrations_ordered(t *testing.T) {
+	require.NotEmpty(t, _migrations)
+
+	for i, m := range _migrations {
+		assert.Equal(t, i+1, m.Version, "migration %d", i)
+		assert.NotEmpty(t, m.Description, "migration %d", i)
+	}
+	assert.Equal(t, _storeVersion, _migrations[len(_migrations)-1].Version)
+}
+
+func TestMigrateStore(t *testing.T) {
+	ctx := context.Background()
+
+	migrations := []migration{
+		{Version: 1, Description: "first"},
+		{
+			Version:     2,
+			Description: "add foo",
+			Migrate: func(context.Context, DB) (storage.UpdateRequest, error) {
+				return storage.UpdateRequest{
+					Sets: []storage.SetRequest{{Key: "foo", Value: "bar"}},
+				}, nil
+			},
+		},
+		{Version: 3, Description: "third"},
+	}
+
+	t.Run("FromScratch", func(t *testing.T) {
+		db := &recordingDB{DB: storage.NewDB(storage.NewMemBackend())}
+		info := repoInfo{Trunk: "main"}
+
+		require.NoError(t, migrateStore(ctx, db, logtest.New(t), &info, migrations, 3))
+		assert.Equal(t, 3, info.Version)
+		assert.Equal(t, []string{
+			"migrate state to version 1: first",
+			"migrate state to version 2: add foo",
+			"migrate state to version 3: third",
+		}, db.messages)
+
+		var got repoInfo
+		require.NoError(t, db.Get(ctx, _repoJSON, &got))
+		assert.Equal(t, repoInfo{Trunk: "main", Version: 3}, got)
+
+		var foo string
+		require.NoError(t, db.Get(ctx, "foo", &foo))
+		assert.Equal(t, "bar", foo)
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		db := &recordingDB{DB: storage.NewDB(storage.NewMemBackend())}
+		info := repoInfo{Trunk: "main", Version: 2}
+
+		require.NoError(t, migrateStore(ctx, db, logtest.New(t), &info, migrations, 3))
+		assert.Equal(t, 3, info.Version)
+		assert.Equal(t, []string{"migrate state to version 3: third"}, db.messages)
+	})
+
+	t.Run("StopsAtTarget", func(t *testing.T) {
+		db := &recordingDB{DB: storage.NewDB(storage.NewMemBackend())}
+		info := repoInfo{Trunk: "main"}
+
+		require.NoError(t, migrateStore(ctx, db, logtest.New(t), &info, migrations, 1))
+		assert.Equal(t, 1, info.Version)
+		assert.Len(t, db.messages, 1)
+	})
+
+	t.Run("UpToDate", func(t *testing.T) {
+		db := &recordingDB{DB: storage.NewDB(storage.NewMemBackend())}
+		info := repoInfo{Trunk: "main", Version: 3}
+
+		require.NoError(t, migrateStore(ctx, db, logtest.New(t), &info, migrations, 3))
+		assert.Empty(t, db.messages)
+	})
+
+	t.Run("Newer", func(t *testing.T) {
+		db := &recordingDB{DB: storage.NewDB(storage.NewMemBackend())}
+		info := repoInfo{Trunk: "main", Version: 4}
+
+		err := migrateStore(ctx, db, logtest.New(t), &info, migrations, 3)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+		assert.ErrorContains(t, err, "state version 4 is newer than supported version 3")
+		assert.Empty(t, db.messages)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		db := &recordingDB{DB: storage.NewDB(storage.NewMemBackend())}
+		info := repoInfo{Trunk: "main"}
+
+		failing := []migration{
+			{Version: 1, Description: "first"},
+			{
+				Version:     2,
+				Description: "broken",
+				Migrate: func(context.Context, DB) (storage.UpdateRequest, error) {
+					return storage.UpdateRequest{}, errors.New("great sadness")
+				},
+			},
+		}
+
+		err := migrateStore(ctx, db, logtest.New(t), &info, failing, 2)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "migrate to version 2: great sadness")
+
+		// The first migration was committed.
+		assert.Equal(t, 1, info.Version)
+		var got repoInfo
+		require.NoError(t, db.Get(ctx, _repoJSON, &got))
+		assert.Equal(t, 1, got.Version)
+	})
+}
+
+// recordingDB is a DB that records the messages of all updates.
+type recordingDB struct {
+	DB
+
+	messages []string
+}
+
+func (r *recordingDB) Update(ctx context.Context, req storage.UpdateRequest) error {
+	r.messages = append(r.messages, req.Message)
+	return r.DB.Update(ctx, req)
+}
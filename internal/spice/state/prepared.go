@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"slices"
+	"strings"
+	"time"
 
 	"go.abhg.dev/gs/internal/storage"
 )
@@ -14,15 +17,29 @@ import (
 //
 // This is used by 'branch submit' command to recover
 // change metadata in case of failure in submitting.
+//
+// Information is stored at "<name>..<key>" inside this directory,
+// where key identifies the submission attempt.
+// ".." cannot appear in a branch name,
+// so this never conflicts with another branch.
+// Older versions stored it at "<name>" without a key.
 const _preparedDir = "prepared"
 
+// _preparedKeySep separates the branch name from the key
+// in the path of prepared branch information.
+const _preparedKeySep = ".."
+
 type preparedBranchState struct {
-	Subject string `json:"subject"`
-	Body    string `json:"body"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SavedAt time.Time `json:"savedAt"`
 }
 
-func (s *Store) preparedBranchJSON(name string) string {
-	return path.Join(_preparedDir, name)
+func (s *Store) preparedBranchJSON(name, key string) string {
+	if key == "" {
+		return path.Join(_preparedDir, name)
+	}
+	return path.Join(_preparedDir, name+_preparedKeySep+key)
 }
 
 // PreparedBranch is a branch that is ready to be submitted.
@@ -30,6 +47,19 @@ type PreparedBranch struct {
 	// Name is the name of the branch.
 	Name string
 
+	// Key identifies the submission attempt
+	// that the information was recorded for.
+	// For example, this may be the hash of the branch head.
+	//
+	// Information for different keys of the same branch
+	// is stored separately.
+	// This is empty for information saved by older versions.
+	Key string
+
+	// SavedAt is the time at which the information was saved.
+	// This is zero for information saved by older versions.
+	SavedAt time.Time
+
 	// Subject is the subject of the change that was recorded.
 	Subject string
 
@@ -39,17 +69,18 @@ type PreparedBranch struct {
 
 // SavePreparedBranch saves information about a branch that is ready for
 // submission.
-// This information may be retrieved later with TakePreparedBranch
+// This information may be retrieved later with LoadPreparedBranch
 // to recover change metadata in case of failure in submitting.
-// If the branch is already saved, it will be overwritten.
+// If the branch is already saved with the same key, it will be overwritten.
 // Use ClearPreparedBranch to remove the saved information.
 func (s *Store) SavePreparedBranch(ctx context.Context, b *PreparedBranch) error {
 	state := preparedBranchState{
 		Subject: b.Subject,
 		Body:    b.Body,
+		SavedAt: time.Now(),
 	}
 
-	err := s.db.Set(ctx, s.preparedBranchJSON(b.Name), state,
+	err := s.db.Set(ctx, s.preparedBranchJSON(b.Name, b.Key), state,
 		fmt.Sprintf("%v: save prepared branch", b.Name))
 	if err != nil {
 		return fmt.Errorf("set prepared branch state: %w", err)
@@ -59,14 +90,42 @@ func (s *Store) SavePreparedBranch(ctx context.Context, b *PreparedBranch) error
 }
 
 // LoadPreparedBranch retrieves metadata about a branch submission
-// that was previously saved with SavePreparedBranch.
-// If there's no information saved, it returns nil.
+// that was previously saved with SavePreparedBranch with the same key.
+// If there's nothing saved for the key,
+// the information most recently saved for the branch
+// under any other key is returned instead.
+// Callers should compare the Key of the result with their own
+// to find out if it was saved for a different attempt.
+// If there's no information saved for the branch, it returns nil.
 //
 // It may be used to recover from past failures in submitting a branch
 // so users do not have to re-enter the change metadata.
-func (s *Store) LoadPreparedBranch(ctx context.Context, name string) (*PreparedBranch, error) {
+func (s *Store) LoadPreparedBranch(ctx context.Context, name, key string) (*PreparedBranch, error) {
+	if b, err := s.loadPreparedBranch(ctx, name, key); err != nil || b != nil {
+		return b, err
+	}
+
+	keys, err := s.preparedBranchKeys(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *PreparedBranch
+	for _, k := range keys {
+		b, err := s.loadPreparedBranch(ctx, name, k)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil && (latest == nil || b.SavedAt.After(latest.SavedAt)) {
+			latest = b
+		}
+	}
+	return latest, nil
+}
+
+func (s *Store) loadPreparedBranch(ctx context.Context, name, key string) (*PreparedBranch, error) {
 	var state preparedBranchState
-	if err := s.db.Get(ctx, s.preparedBranchJSON(name), &state); err != nil {
+	if err := s.db.Get(ctx, s.preparedBranchJSON(name, key), &state); err != nil {
 		if errors.Is(err, storage.ErrNotExist) {
 			return nil, nil
 		}
@@ -75,17 +134,76 @@ func (s *Store) LoadPreparedBranch(ctx context.Context, name string) (*PreparedB
 
 	return &PreparedBranch{
 		Name:    name,
+		Key:     key,
 		Subject: state.Subject,
 		Body:    state.Body,
+		SavedAt: state.SavedAt,
 	}, nil
 }
 
-// ClearPreparedBranch removes the information saved about a branch
-// that was previously saved with SavePreparedBranch.
+// preparedBranchKeys returns the keys under which information
+// is saved for the given branch.
+// Information saved by older versions is reported with an empty key.
+func (s *Store) preparedBranchKeys(ctx context.Context, name string) ([]string, error) {
+	legacyPath := s.preparedBranchJSON(name, "")
+	entries, err := s.db.Keys(ctx, path.Dir(legacyPath))
+	if err != nil {
+		return nil, fmt.Errorf("list prepared branches: %w", err)
+	}
+
+	base := path.Base(legacyPath)
+	var keys []string
+	for _, entry := range entries {
+		if entry == base {
+			keys = append(keys, "")
+		} else if key, ok := strings.CutPrefix(entry, base+_preparedKeySep); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// ListPreparedBranches returns the names of branches
+// with information saved by SavePreparedBranch.
+// These are branches that failed to submit.
+// The list is sorted in lexicographic order.
+func (s *Store) ListPreparedBranches(ctx context.Context) ([]string, error) {
+	entries, err := s.db.Keys(ctx, _preparedDir)
+	if err != nil {
+		return nil, fmt.Errorf("list prepared branches: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name, _, _ := strings.Cut(entry, _preparedKeySep)
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+// ClearPreparedBranch removes all information saved about a branch
+// that was previously saved with SavePreparedBranch,
+// regardless of the key it was saved with.
 // This is a no-op if the branch information isn't saved anymore.
 func (s *Store) ClearPreparedBranch(ctx context.Context, name string) error {
-	err := s.db.Delete(ctx, s.preparedBranchJSON(name),
-		fmt.Sprintf("%v: clear prepared branch", name))
+	keys, err := s.preparedBranchKeys(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	deletes := make([]string, len(keys))
+	for i, key := range keys {
+		deletes[i] = s.preparedBranchJSON(name, key)
+	}
+
+	err = s.db.Update(ctx, storage.UpdateRequest{
+		Deletes: deletes,
+		Message: fmt.Sprintf("%v: clear prepared branch", name),
+	})
 	if err != nil {
 		return fmt.Errorf("delete prepared branch state: %w", err)
 	}
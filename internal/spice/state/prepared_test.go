@@ -0,0 +1,147 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/storage"
+)
+
+func TestPreparedBranch(t *testing.T) {
+	ctx := context.Background()
+	db := storage.NewDB(storage.NewMemBackend())
+
+	_, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "main",
+	})
+	require.NoError(t, err)
+
+	store, err := state.OpenStore(ctx, db, logtest.New(t))
+	require.NoError(t, err)
+
+	require.NoError(t, store.SavePreparedBranch(ctx, &state.PreparedBranch{
+		Name:    "feature",
+		Key:     "abc",
+		Subject: "First attempt",
+	}))
+	require.NoError(t, store.SavePreparedBranch(ctx, &state.PreparedBranch{
+		Name:    "feature",
+		Key:     "def",
+		Subject: "Second attempt",
+	}))
+
+	t.Run("by key", func(t *testing.T) {
+		got, err := store.LoadPreparedBranch(ctx, "feature", "abc")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", got.Key)
+		assert.Equal(t, "First attempt", got.Subject)
+		assert.False(t, got.SavedAt.IsZero())
+
+		got, err = store.LoadPreparedBranch(ctx, "feature", "def")
+		require.NoError(t, err)
+		assert.Equal(t, "Second attempt", got.Subject)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		// Falls back to the most recently saved information.
+		got, err := store.LoadPreparedBranch(ctx, "feature", "123")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "def", got.Key)
+		assert.Equal(t, "Second attempt", got.Subject)
+
+		got, err = store.LoadPreparedBranch(ctx, "other", "123")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		require.NoError(t, store.SavePreparedBranch(ctx, &state.PreparedBranch{
+			Name:    "user/feature",
+			Key:     "abc",
+			Subject: "Nested",
+		}))
+
+		got, err := store.ListPreparedBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature", "user/feature"}, got)
+
+		require.NoError(t, store.ClearPreparedBranch(ctx, "user/feature"))
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		require.NoError(t, store.SavePreparedBranch(ctx, &state.PreparedBranch{
+			Name:    "feature2",
+			Key:     "abc",
+			Subject: "Unrelated",
+		}))
+
+		require.NoError(t, store.ClearPreparedBranch(ctx, "feature"))
+
+		names, err := store.ListPreparedBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature2"}, names)
+
+		for _, key := range []string{"abc", "def"} {
+			got, err := store.LoadPreparedBranch(ctx, "feature", key)
+			require.NoError(t, err)
+			assert.Nil(t, got, "key %v", key)
+		}
+
+		got, err := store.LoadPreparedBranch(ctx, "feature2", "abc")
+		require.NoError(t, err)
+		assert.Equal(t, "Unrelated", got.Subject)
+	})
+}
+
+func TestPreparedBranch_legacy(t *testing.T) {
+	ctx := context.Background()
+	db := storage.NewDB(storage.NewMemBackend())
+
+	_, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "main",
+	})
+	require.NoError(t, err)
+
+	// Older versions stored prepared branches by name only.
+	require.NoError(t, db.Set(ctx, "prepared/feature", map[string]string{
+		"subject": "Old attempt",
+		"body":    "Old body",
+	}, "save legacy prepared branch"))
+
+	store, err := state.OpenStore(ctx, db, logtest.New(t))
+	require.NoError(t, err)
+
+	names, err := store.ListPreparedBranches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature"}, names)
+
+	got, err := store.LoadPreparedBranch(ctx, "feature", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, &state.PreparedBranch{
+		Name:    "feature",
+		Subject: "Old attempt",
+		Body:    "Old body",
+	}, got)
+
+	// Information for the key takes precedence.
+	require.NoError(t, store.SavePreparedBranch(ctx, &state.PreparedBranch{
+		Name:    "feature",
+		Key:     "abc",
+		Subject: "New attempt",
+	}))
+	got, err = store.LoadPreparedBranch(ctx, "feature", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "New attempt", got.Subject)
+
+	require.NoError(t, store.ClearPreparedBranch(ctx, "feature"))
+	got, err = store.LoadPreparedBranch(ctx, "feature", "abc")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+
+	"go.abhg.dev/gs/internal/storage"
 )
 
 const _repoJSON = "repo"
@@ -11,6 +13,11 @@ const _repoJSON = "repo"
 type repoInfo struct {
 	Trunk  string `json:"trunk"`
 	Remote string `json:"remote"`
+
+	// Version is the schema version of the store.
+	// Stores written before versioning was introduced
+	// have no version recorded, and are treated as version 0.
+	Version int `json:"version,omitempty"`
 }
 
 func (i *repoInfo) Validate() error {
@@ -56,3 +63,67 @@ func (s *Store) SetRemote(ctx context.Context, remote string) error {
 
 	return nil
 }
+
+// RenameTrunk changes the trunk branch configured for the repository,
+// and moves branches based on the old trunk onto the new trunk.
+//
+// All changes are made in a single update,
+// so the store never refers to a mix of the old and new trunk.
+// It's an error to rename the trunk to a tracked branch.
+func (s *Store) RenameTrunk(ctx context.Context, newTrunk string) error {
+	if newTrunk == "" {
+		return errors.New("trunk branch name is empty")
+	}
+
+	var info repoInfo
+	if err := s.db.Get(ctx, _repoJSON, &info); err != nil {
+		return fmt.Errorf("get repo info: %w", err)
+	}
+	oldTrunk := info.Trunk
+	if oldTrunk == newTrunk {
+		return nil
+	}
+
+	if _, err := s.lookupBranchState(ctx, newTrunk); err == nil {
+		return fmt.Errorf("branch %q is tracked; untrack it first", newTrunk)
+	} else if !errors.Is(err, ErrNotExist) {
+		return fmt.Errorf("get branch: %w", err)
+	}
+
+	info.Trunk = newTrunk
+	if err := info.Validate(); err != nil {
+		return fmt.Errorf("would corrupt state: %w", err)
+	}
+	sets := []storage.SetRequest{{Key: _repoJSON, Value: info}}
+
+	branches, err := s.ListBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	for _, name := range branches {
+		b, err := s.lookupBranchState(ctx, name)
+		if err != nil {
+			return fmt.Errorf("get branch %q: %w", name, err)
+		}
+		if b.Base.Name != oldTrunk {
+			continue
+		}
+
+		b.Base.Name = newTrunk
+		sets = append(sets, storage.SetRequest{
+			Key:   s.branchJSON(name),
+			Value: b,
+		})
+	}
+
+	err = s.db.Update(ctx, storage.UpdateRequest{
+		Sets:    sets,
+		Message: fmt.Sprintf("rename trunk %q to %q", oldTrunk, newTrunk),
+	})
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	s.trunk = newTrunk
+	return nil
+}
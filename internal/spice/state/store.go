@@ -78,12 +78,19 @@ func InitStore(ctx context.Context, req InitStoreRequest) (*Store, error) {
 		remote: req.Remote,
 		log:    logger,
 	}
-	if err := db.Get(ctx, _repoJSON, new(repoInfo)); err == nil {
+	var existing repoInfo
+	if err := db.Get(ctx, _repoJSON, &existing); err == nil {
 		if req.Reset {
 			if err := db.Clear(ctx, "reset store"); err != nil {
 				return nil, fmt.Errorf("clear store: %w", err)
 			}
 		} else {
+			// Retained branches must be upgraded
+			// before the new version is recorded.
+			if err := migrateStore(ctx, db, logger, &existing, _migrations, _storeVersion); err != nil {
+				return nil, err
+			}
+
 			// If we're not resetting,
 			// ensure that the trunk branch is not tracked.
 			_, err := store.LookupBranch(ctx, req.Trunk)
@@ -95,8 +102,9 @@ func InitStore(ctx context.Context, req InitStoreRequest) (*Store, error) {
 	}
 
 	info := repoInfo{
-		Trunk:  req.Trunk,
-		Remote: req.Remote,
+		Trunk:   req.Trunk,
+		Remote:  req.Remote,
+		Version: _storeVersion,
 	}
 	if err := db.Set(ctx, _repoJSON, info, "initialize store"); err != nil {
 		return nil, fmt.Errorf("put repo state: %w", err)
@@ -111,6 +119,11 @@ var ErrUninitialized = errors.New("store not initialized")
 // OpenStore opens the Store for the given Git repository.
 //
 // It returns [ErrUninitialized] if the repository is not initialized.
+//
+// If the store was written by an older version of gs,
+// it is upgraded to the current schema version.
+// It returns [ErrUnsupportedVersion] if the store was written
+// by a newer version of gs.
 func OpenStore(ctx context.Context, db DB, logger *log.Logger) (*Store, error) {
 	if logger == nil {
 		logger = log.New(io.Discard)
@@ -128,6 +141,10 @@ func OpenStore(ctx context.Context, db DB, logger *log.Logger) (*Store, error) {
 		return nil, fmt.Errorf("corrupt state: %w", err)
 	}
 
+	if err := migrateStore(ctx, db, logger, &info, _migrations, _storeVersion); err != nil {
+		return nil, err
+	}
+
 	return &Store{
 		db:     db,
 		trunk:  info.Trunk,
@@ -135,3 +152,46 @@ func OpenStore(ctx context.Context, db DB, logger *log.Logger) (*Store, error) {
 		log:    logger,
 	}, nil
 }
+
+// ErrReadOnly is returned when attempting to modify a read-only store.
+var ErrReadOnly = errors.New("store is read-only")
+
+// ReadOnly returns a copy of the store that cannot be modified.
+// All operations that would change the stored state
+// fail with [ErrReadOnly] on the returned store.
+//
+// Use this for operations that must never have side effects.
+func (s *Store) ReadOnly() *Store {
+	ro := *s
+	ro.db = readOnlyDB{ro.db}
+	return &ro
+}
+
+// readOnlyDB is a DB that rejects all writes.
+type readOnlyDB struct{ db DB }
+
+var _ DB = readOnlyDB{}
+
+func (r readOnlyDB) Get(ctx context.Context, k string, v any) error {
+	return r.db.Get(ctx, k, v)
+}
+
+func (r readOnlyDB) Keys(ctx context.Context, dir string) ([]string, error) {
+	return r.db.Keys(ctx, dir)
+}
+
+func (readOnlyDB) Set(context.Context, string, any, string) error {
+	return ErrReadOnly
+}
+
+func (readOnlyDB) Delete(context.Context, string, string) error {
+	return ErrReadOnly
+}
+
+func (readOnlyDB) Update(context.Context, storage.UpdateRequest) error {
+	return ErrReadOnly
+}
+
+func (readOnlyDB) Clear(context.Context, string) error {
+	return ErrReadOnly
+}
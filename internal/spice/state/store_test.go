@@ -72,6 +72,34 @@ func TestStore(t *testing.T) {
 		assert.JSONEq(t, `{"id": 43}`, string(res.ChangeMetadata))
 	})
 
+	t.Run("change URL", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:      "foo",
+				ChangeURL: "https://example.com/change/43",
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err := store.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/change/43", res.ChangeURL)
+
+		// Updates that don't set a URL keep the current one.
+		err = store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:           "foo",
+				ChangeForge:    "shamhub",
+				ChangeMetadata: json.RawMessage(`{"id": 43}`),
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err = store.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/change/43", res.ChangeURL)
+	})
+
 	t.Run("name with slash", func(t *testing.T) {
 		err := store.UpdateBranch(ctx, &state.UpdateRequest{
 			Upserts: []state.UpsertRequest{{
@@ -91,4 +119,352 @@ func TestStore(t *testing.T) {
 		assert.Equal(t, "shamhub", res.ChangeForge)
 		assert.JSONEq(t, `{"id": 44}`, string(res.ChangeMetadata))
 	})
+
+	t.Run("expect base hash", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:           "bar/baz",
+				BaseHash:       "fedcba",
+				ExpectBaseHash: "abcdef",
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err := store.LookupBranch(ctx, "bar/baz")
+		require.NoError(t, err)
+		assert.Equal(t, "fedcba", string(res.BaseHash))
+	})
+
+	t.Run("expect base hash mismatch", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:           "bar/baz",
+				BaseHash:       "123abc",
+				ExpectBaseHash: "abcdef",
+			}},
+		})
+		var mismatchErr *state.BaseHashMismatchError
+		require.ErrorAs(t, err, &mismatchErr)
+		assert.Equal(t, "bar/baz", mismatchErr.Branch)
+		assert.Equal(t, "abcdef", string(mismatchErr.Want))
+		assert.Equal(t, "fedcba", string(mismatchErr.Got))
+
+		// The stored hash is unchanged.
+		res, err := store.LookupBranch(ctx, "bar/baz")
+		require.NoError(t, err)
+		assert.Equal(t, "fedcba", string(res.BaseHash))
+	})
+
+	t.Run("upstream hash", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:         "foo",
+				UpstreamHash: "abc123",
+			}},
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "has no upstream branch")
+
+		err = store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:           "foo",
+				UpstreamBranch: "foo",
+				UpstreamHash:   "abc123",
+			}},
+		})
+		require.NoError(t, err)
+
+		// Updating the upstream branch with the same name
+		// keeps the upstream hash.
+		err = store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:           "foo",
+				UpstreamBranch: "foo",
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err := store.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "foo", res.UpstreamBranch)
+		assert.Equal(t, "abc123", string(res.UpstreamHash))
+
+		// Changing the upstream branch forgets it.
+		err = store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:           "foo",
+				UpstreamBranch: "foo-renamed",
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err = store.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "foo-renamed", res.UpstreamBranch)
+		assert.Empty(t, res.UpstreamHash)
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Archives: []string{"bar/baz"},
+		})
+		require.NoError(t, err)
+
+		_, err = store.LookupBranch(ctx, "bar/baz")
+		assert.ErrorIs(t, err, state.ErrNotExist)
+
+		branches, err := store.ListBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo"}, branches)
+
+		archived, err := store.ListArchived(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bar/baz"}, archived)
+
+		res, err := store.LookupArchived(ctx, "bar/baz")
+		require.NoError(t, err)
+		assert.Equal(t, "main", res.Base)
+		assert.Equal(t, "fedcba", string(res.BaseHash))
+		assert.JSONEq(t, `{"id": 44}`, string(res.ChangeMetadata))
+
+		require.NoError(t, store.Restore(ctx, "bar/baz"))
+
+		res, err = store.LookupBranch(ctx, "bar/baz")
+		require.NoError(t, err)
+		assert.Equal(t, "main", res.Base)
+		assert.Equal(t, "fedcba", string(res.BaseHash))
+		assert.JSONEq(t, `{"id": 44}`, string(res.ChangeMetadata))
+
+		archived, err = store.ListArchived(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, archived)
+	})
+
+	t.Run("restore not archived", func(t *testing.T) {
+		err := store.Restore(ctx, "nope")
+		assert.ErrorIs(t, err, state.ErrNotExist)
+	})
+
+	t.Run("restore tracked", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Archives: []string{"bar/baz"},
+		})
+		require.NoError(t, err)
+
+		err = store.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name: "bar/baz",
+				Base: "foo",
+			}},
+		})
+		require.NoError(t, err)
+
+		err = store.Restore(ctx, "bar/baz")
+		assert.ErrorContains(t, err, "already tracked")
+	})
+
+	t.Run("restore with upserts", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Archives: []string{"bar/baz"},
+		})
+		require.NoError(t, err)
+
+		err = store.UpdateBranch(ctx, &state.UpdateRequest{
+			Restores: []string{"bar/baz"},
+			Upserts: []state.UpsertRequest{{
+				Name: "qux",
+				Base: "bar/baz",
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err := store.LookupBranch(ctx, "bar/baz")
+		require.NoError(t, err)
+		assert.Equal(t, "foo", res.Base)
+
+		res, err = store.LookupBranch(ctx, "qux")
+		require.NoError(t, err)
+		assert.Equal(t, "bar/baz", res.Base)
+
+		archived, err := store.ListArchived(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, archived)
+	})
+
+	t.Run("archive untracked", func(t *testing.T) {
+		err := store.UpdateBranch(ctx, &state.UpdateRequest{
+			Archives: []string{"nope"},
+		})
+		assert.ErrorIs(t, err, state.ErrNotExist)
+	})
+}
+
+func TestStoreRenameTrunk(t *testing.T) {
+	ctx := context.Background()
+	db := storage.NewDB(storage.NewMemBackend())
+
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "master",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{Name: "foo", Base: "master", BaseHash: "abcdef"},
+			{Name: "bar", Base: "foo", BaseHash: "123456"},
+		},
+	}))
+
+	t.Run("tracked", func(t *testing.T) {
+		err := store.RenameTrunk(ctx, "foo")
+		assert.ErrorContains(t, err, `branch "foo" is tracked`)
+		assert.Equal(t, "master", store.Trunk())
+	})
+
+	require.NoError(t, store.RenameTrunk(ctx, "main"))
+	assert.Equal(t, "main", store.Trunk())
+
+	foo, err := store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "main", foo.Base)
+	assert.Equal(t, "abcdef", string(foo.BaseHash))
+
+	bar, err := store.LookupBranch(ctx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", bar.Base)
+
+	// The change persists.
+	reopened, err := state.OpenStore(ctx, db, logtest.New(t))
+	require.NoError(t, err)
+	assert.Equal(t, "main", reopened.Trunk())
+}
+
+func TestStoreReadOnly(t *testing.T) {
+	ctx := context.Background()
+	db := storage.NewDB(storage.NewMemBackend())
+
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "main",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: []state.UpsertRequest{{
+			Name:     "foo",
+			Base:     "main",
+			BaseHash: "abcdef",
+		}},
+	}))
+
+	ro := store.ReadOnly()
+
+	t.Run("read", func(t *testing.T) {
+		assert.Equal(t, "main", ro.Trunk())
+
+		res, err := ro.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "main", res.Base)
+
+		names, err := ro.ListBranches(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo"}, names)
+	})
+
+	t.Run("update branch", func(t *testing.T) {
+		err := ro.UpdateBranch(ctx, &state.UpdateRequest{
+			Upserts: []state.UpsertRequest{{
+				Name:     "foo",
+				BaseHash: "123456",
+			}},
+			Deletes: []string{"bar"},
+		})
+		assert.ErrorIs(t, err, state.ErrReadOnly)
+
+		res, err := store.LookupBranch(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "abcdef", string(res.BaseHash))
+	})
+
+	t.Run("set remote", func(t *testing.T) {
+		err := ro.SetRemote(ctx, "origin")
+		assert.ErrorIs(t, err, state.ErrReadOnly)
+	})
+
+	t.Run("rename trunk", func(t *testing.T) {
+		err := ro.RenameTrunk(ctx, "trunk")
+		assert.ErrorIs(t, err, state.ErrReadOnly)
+		assert.Equal(t, "main", ro.Trunk())
+	})
+
+	t.Run("prepared branch", func(t *testing.T) {
+		err := ro.SavePreparedBranch(ctx, &state.PreparedBranch{
+			Name:    "foo",
+			Subject: "Add foo",
+		})
+		assert.ErrorIs(t, err, state.ErrReadOnly)
+	})
+
+	t.Run("original store is writable", func(t *testing.T) {
+		require.NoError(t, store.UpdateBranch(ctx, &state.UpdateRequest{
+			Deletes: []string{"foo"},
+		}))
+	})
+}
+
+func TestOpenStore_versions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Unversioned", func(t *testing.T) {
+		db := storage.NewDB(storage.NewMemBackend())
+		require.NoError(t, db.Set(ctx, "repo", map[string]any{
+			"trunk": "main",
+		}, "old store"))
+
+		store, err := state.OpenStore(ctx, db, logtest.New(t))
+		require.NoError(t, err)
+		assert.Equal(t, "main", store.Trunk())
+
+		var info map[string]any
+		require.NoError(t, db.Get(ctx, "repo", &info))
+		assert.NotZero(t, info["version"])
+		assert.Equal(t, "main", info["trunk"])
+	})
+
+	t.Run("Newer", func(t *testing.T) {
+		db := storage.NewDB(storage.NewMemBackend())
+		require.NoError(t, db.Set(ctx, "repo", map[string]any{
+			"trunk":   "main",
+			"version": 1000,
+		}, "future store"))
+
+		_, err := state.OpenStore(ctx, db, logtest.New(t))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, state.ErrUnsupportedVersion)
+
+		// Re-initializing without a reset must not clobber the state.
+		_, err = state.InitStore(ctx, state.InitStoreRequest{
+			DB:    db,
+			Trunk: "main",
+		})
+		assert.ErrorIs(t, err, state.ErrUnsupportedVersion)
+
+		var info map[string]any
+		require.NoError(t, db.Get(ctx, "repo", &info))
+		assert.EqualValues(t, 1000, info["version"])
+	})
+
+	t.Run("InitRecordsVersion", func(t *testing.T) {
+		db := storage.NewDB(storage.NewMemBackend())
+		_, err := state.InitStore(ctx, state.InitStoreRequest{
+			DB:    db,
+			Trunk: "main",
+		})
+		require.NoError(t, err)
+
+		var info map[string]any
+		require.NoError(t, db.Get(ctx, "repo", &info))
+		assert.NotZero(t, info["version"])
+	})
 }
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/storage"
+)
+
+// _submitDefaultsJSON holds options applied to every submission
+// in the repository unless overridden on the command line.
+// This is stored separately from the repository information
+// so that it survives re-initializing the repository.
+const _submitDefaultsJSON = "submit-defaults"
+
+type submitDefaultsState struct {
+	Reviewers []string `json:"reviewers,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+}
+
+// SubmitDefaults are options for new change requests
+// used when they aren't provided on the command line.
+type SubmitDefaults struct {
+	// Reviewers are users or teams to request reviews from.
+	Reviewers []string
+
+	// Labels are labels to add to the change request.
+	Labels []string
+}
+
+// LoadSubmitDefaults returns the submit defaults saved for the repository.
+// If none are saved, it returns an empty SubmitDefaults.
+func (s *Store) LoadSubmitDefaults(ctx context.Context) (*SubmitDefaults, error) {
+	var state submitDefaultsState
+	if err := s.db.Get(ctx, _submitDefaultsJSON, &state); err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return &SubmitDefaults{}, nil
+		}
+		return nil, fmt.Errorf("get submit defaults: %w", err)
+	}
+
+	return &SubmitDefaults{
+		Reviewers: state.Reviewers,
+		Labels:    state.Labels,
+	}, nil
+}
+
+// SaveSubmitDefaults replaces the submit defaults saved for the repository.
+// Saving empty defaults is the same as clearing them.
+func (s *Store) SaveSubmitDefaults(ctx context.Context, d *SubmitDefaults) error {
+	if len(d.Reviewers) == 0 && len(d.Labels) == 0 {
+		return s.ClearSubmitDefaults(ctx)
+	}
+
+	state := submitDefaultsState{
+		Reviewers: d.Reviewers,
+		Labels:    d.Labels,
+	}
+	if err := s.db.Set(ctx, _submitDefaultsJSON, state, "save submit defaults"); err != nil {
+		return fmt.Errorf("set submit defaults: %w", err)
+	}
+
+	return nil
+}
+
+// ClearSubmitDefaults removes the submit defaults saved for the repository.
+// This is a no-op if none are saved.
+func (s *Store) ClearSubmitDefaults(ctx context.Context) error {
+	if err := s.db.Delete(ctx, _submitDefaultsJSON, "clear submit defaults"); err != nil {
+		return fmt.Errorf("delete submit defaults: %w", err)
+	}
+	return nil
+}
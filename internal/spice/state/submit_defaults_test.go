@@ -0,0 +1,80 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/storage"
+)
+
+func TestSubmitDefaults(t *testing.T) {
+	ctx := context.Background()
+	db := storage.NewDB(storage.NewMemBackend())
+
+	_, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "main",
+	})
+	require.NoError(t, err)
+
+	store, err := state.OpenStore(ctx, db, logtest.New(t))
+	require.NoError(t, err)
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := store.LoadSubmitDefaults(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &state.SubmitDefaults{}, got)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		require.NoError(t, store.SaveSubmitDefaults(ctx, &state.SubmitDefaults{
+			Reviewers: []string{"alice", "org/team"},
+			Labels:    []string{"needs-review"},
+		}))
+
+		got, err := store.LoadSubmitDefaults(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &state.SubmitDefaults{
+			Reviewers: []string{"alice", "org/team"},
+			Labels:    []string{"needs-review"},
+		}, got)
+	})
+
+	t.Run("survives reinit", func(t *testing.T) {
+		_, err := state.InitStore(ctx, state.InitStoreRequest{
+			DB:    db,
+			Trunk: "main",
+		})
+		require.NoError(t, err)
+
+		got, err := store.LoadSubmitDefaults(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "org/team"}, got.Reviewers)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		require.NoError(t, store.ClearSubmitDefaults(ctx))
+
+		got, err := store.LoadSubmitDefaults(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &state.SubmitDefaults{}, got)
+
+		// Clearing again is a no-op.
+		require.NoError(t, store.ClearSubmitDefaults(ctx))
+	})
+
+	t.Run("save empty clears", func(t *testing.T) {
+		require.NoError(t, store.SaveSubmitDefaults(ctx, &state.SubmitDefaults{
+			Labels: []string{"x"},
+		}))
+		require.NoError(t, store.SaveSubmitDefaults(ctx, &state.SubmitDefaults{}))
+
+		got, err := store.LoadSubmitDefaults(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &state.SubmitDefaults{}, got)
+	})
+}
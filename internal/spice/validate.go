@@ -0,0 +1,131 @@
+package spice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+)
+
+// StackProblem is a problem with a branch
+// found by [Service.ValidateStack].
+type StackProblem struct {
+	// Branch is the name of the branch with the problem.
+	Branch string
+
+	// Err describes the problem.
+	Err error
+}
+
+// InvalidStackError is returned by [Service.ValidateStack]
+// with all problems found in the stack.
+type InvalidStackError struct {
+	// Problems is a list of problems in the order
+	// in which the branches were passed to ValidateStack.
+	// There may be more than one problem per branch.
+	Problems []StackProblem
+}
+
+func (e *InvalidStackError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Branch + ": " + p.Err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors for all problems in the stack.
+func (e *InvalidStackError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p.Err
+	}
+	return errs
+}
+
+// ValidateStack verifies that the given branches
+// are in a good state for an operation that rewrites them:
+// all of them are tracked and exist in the repository,
+// their bases resolve to trunk without cycles,
+// and they don't need to be restacked.
+//
+// Rather than stopping at the first problem,
+// it returns an [InvalidStackError] describing every problem found.
+// Other errors indicate a failure to check the branches.
+func (s *Service) ValidateStack(ctx context.Context, branches []string) error {
+	var problems []StackProblem
+	for _, name := range branches {
+		errs, err := s.validateStackBranch(ctx, name)
+		if err != nil {
+			return fmt.Errorf("validate %v: %w", name, err)
+		}
+		for _, err := range errs {
+			problems = append(problems, StackProblem{Branch: name, Err: err})
+		}
+	}
+
+	if len(problems) > 0 {
+		return &InvalidStackError{Problems: problems}
+	}
+	return nil
+}
+
+// validateStackBranch reports the problems found with a single branch.
+// The returned error is non-nil only if the branch could not be checked.
+func (s *Service) validateStackBranch(ctx context.Context, name string) (problems []error, _ error) {
+	if name == s.store.Trunk() {
+		return []error{ErrTrunk}, nil
+	}
+
+	b, err := s.LookupBranch(ctx, name)
+	if err != nil {
+		var deletedErr *DeletedBranchError
+		switch {
+		case errors.As(err, &deletedErr):
+			return []error{deletedErr}, nil
+		case errors.Is(err, git.ErrNotExist):
+			return []error{errors.New("branch does not exist")}, nil
+		case errors.Is(err, state.ErrNotExist):
+			return []error{errors.New("branch is not tracked")}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	if err := s.verifyBaseChain(ctx, name, b.Base); err != nil {
+		// Restacking is meaningless without a valid base.
+		return []error{err}, nil
+	}
+
+	if err := s.VerifyRestacked(ctx, name); err != nil {
+		problems = append(problems, err)
+	}
+
+	return problems, nil
+}
+
+// verifyBaseChain follows the bases of a branch down to trunk,
+// returning an error if a base isn't tracked or if there's a cycle.
+func (s *Service) verifyBaseChain(ctx context.Context, name, base string) error {
+	trunk := s.store.Trunk()
+	seen := map[string]struct{}{name: {}}
+	for base != trunk {
+		if _, ok := seen[base]; ok {
+			return fmt.Errorf("base chain has a cycle at %v", base)
+		}
+		seen[base] = struct{}{}
+
+		b, err := s.store.LookupBranch(ctx, base)
+		if err != nil {
+			if errors.Is(err, state.ErrNotExist) {
+				return fmt.Errorf("base %v is not tracked", base)
+			}
+			return fmt.Errorf("lookup %v: %w", base, err)
+		}
+		base = b.Base
+	}
+	return nil
+}
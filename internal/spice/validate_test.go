@@ -0,0 +1,125 @@
+package spice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/logtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestService_ValidateStack(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	mockRepo := NewMockGitRepository(mockCtrl)
+	mockStore := NewMockStore(mockCtrl)
+	mockStore.EXPECT().
+		Trunk().
+		Return("main").
+		AnyTimes()
+
+	mockStore.EXPECT().
+		Remote().
+		Return("", git.ErrNotExist).
+		AnyTimes()
+
+	// main
+	// └─ok
+	//   └─stale (needs restack)
+	// orphan (based on untracked)
+	// cycle1 <-> cycle2
+	// deleted (tracked, but not in the repository)
+	type branch struct {
+		base      string
+		exists    bool
+		restacked bool
+	}
+	branches := map[string]branch{
+		"ok":      {base: "main", exists: true, restacked: true},
+		"stale":   {base: "ok", exists: true, restacked: false},
+		"orphan":  {base: "untracked", exists: true, restacked: true},
+		"cycle1":  {base: "cycle2", exists: true, restacked: true},
+		"cycle2":  {base: "cycle1", exists: true, restacked: true},
+		"deleted": {base: "main", exists: false},
+	}
+	mockRepo.EXPECT().
+		PeelToCommit(gomock.Any(), "main").
+		Return(git.Hash("main"), nil).
+		AnyTimes()
+	for name, b := range branches {
+		mockStore.EXPECT().
+			LookupBranch(gomock.Any(), name).
+			Return(&state.LookupResponse{Base: b.base, BaseHash: git.Hash(b.base)}, nil).
+			AnyTimes()
+
+		if !b.exists {
+			mockRepo.EXPECT().
+				PeelToCommit(gomock.Any(), name).
+				Return(git.ZeroHash, git.ErrNotExist).
+				AnyTimes()
+			continue
+		}
+
+		mockRepo.EXPECT().
+			PeelToCommit(gomock.Any(), name).
+			Return(git.Hash(name), nil).
+			AnyTimes()
+		mockRepo.EXPECT().
+			IsAncestor(gomock.Any(), git.Hash(b.base), git.Hash(name)).
+			Return(b.restacked).
+			AnyTimes()
+	}
+	for _, name := range []string{"untracked", "missing"} {
+		mockStore.EXPECT().
+			LookupBranch(gomock.Any(), name).
+			Return(nil, state.ErrNotExist).
+			AnyTimes()
+	}
+	mockRepo.EXPECT().
+		PeelToCommit(gomock.Any(), "untracked").
+		Return(git.Hash("untracked"), nil).
+		AnyTimes()
+	mockRepo.EXPECT().
+		PeelToCommit(gomock.Any(), "missing").
+		Return(git.ZeroHash, git.ErrNotExist).
+		AnyTimes()
+
+	svc := NewService(ctx, mockRepo, mockStore, logtest.New(t))
+
+	t.Run("Valid", func(t *testing.T) {
+		require.NoError(t, svc.ValidateStack(ctx, []string{"ok"}))
+	})
+
+	t.Run("AllProblems", func(t *testing.T) {
+		err := svc.ValidateStack(ctx, []string{
+			"main", "ok", "stale", "orphan", "cycle1", "deleted", "untracked", "missing",
+		})
+		require.Error(t, err)
+
+		var invalidErr *InvalidStackError
+		require.True(t, errors.As(err, &invalidErr))
+
+		got := make(map[string]string)
+		for _, p := range invalidErr.Problems {
+			got[p.Branch] = p.Err.Error()
+		}
+		assert.Equal(t, map[string]string{
+			"main":      "branch is trunk",
+			"stale":     "branch needs to be restacked on top of ok",
+			"orphan":    "base untracked is not tracked",
+			"cycle1":    "base chain has a cycle at cycle1",
+			"deleted":   "tracked branch deleted was deleted out of band",
+			"untracked": "branch is not tracked",
+			"missing":   "branch does not exist",
+		}, got)
+
+		assert.ErrorIs(t, err, ErrTrunk)
+		assert.ErrorAs(t, err, new(*BranchNeedsRestackError))
+	})
+}
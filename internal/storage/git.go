@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
@@ -28,6 +29,7 @@ type GitRepository interface {
 	MakeTree(ctx context.Context, ents []git.TreeEntry) (git.Hash, error)
 
 	SetRef(ctx context.Context, req git.SetRefRequest) error
+	IsAncestor(ctx context.Context, a, b git.Hash) bool
 }
 
 var _ GitRepository = (*git.Repository)(nil)
@@ -35,10 +37,16 @@ var _ GitRepository = (*git.Repository)(nil)
 // GitBackend implements a storage backend using a Git repository
 // reference as the storage medium.
 type GitBackend struct {
-	repo GitRepository
-	ref  string
-	sig  git.Signature
-	log  *log.Logger
+	// mu serializes writes made through this backend
+	// so that concurrent writers in the same process
+	// don't race to update the reference.
+	mu sync.Mutex
+
+	repo    GitRepository
+	ref     string
+	seenRef string
+	sig     git.Signature
+	log     *log.Logger
 }
 
 var _ Backend = (*GitBackend)(nil)
@@ -49,6 +57,13 @@ type GitConfig struct {
 	Ref                     string        // required
 	AuthorName, AuthorEmail string        // required
 
+	// SeenRef, if set, is a reference that records
+	// the last commit written to Ref by this backend.
+	//
+	// Verify uses it to detect if Ref was rewritten
+	// by something other than this backend.
+	SeenRef string
+
 	Log *log.Logger
 }
 
@@ -60,8 +75,9 @@ func NewGitBackend(cfg GitConfig) *GitBackend {
 	}
 
 	return &GitBackend{
-		repo: cfg.Repo,
-		ref:  cfg.Ref,
+		repo:    cfg.Repo,
+		ref:     cfg.Ref,
+		seenRef: cfg.SeenRef,
 		sig: git.Signature{
 			Name:  cfg.AuthorName,
 			Email: cfg.AuthorEmail,
@@ -128,6 +144,9 @@ func (g *GitBackend) Get(ctx context.Context, key string, v interface{}) error {
 
 // Clear removes all keys from the store.
 func (g *GitBackend) Clear(ctx context.Context, msg string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	prevCommit, err := g.repo.PeelToCommit(ctx, g.ref)
 	if err != nil {
 		prevCommit = "" // not initialized
@@ -159,6 +178,7 @@ func (g *GitBackend) Clear(ctx context.Context, msg string) error {
 		return fmt.Errorf("update ref: %w", err)
 	}
 
+	g.markSeen(ctx, newCommit)
 	return nil
 }
 
@@ -183,6 +203,9 @@ func (g *GitBackend) Update(ctx context.Context, req UpdateRequest) error {
 		setBlobs[i] = blobHash
 	}
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	var updateErr error
 	for range 5 {
 		var prevTree git.Hash
@@ -243,8 +266,103 @@ func (g *GitBackend) Update(ctx context.Context, req UpdateRequest) error {
 			continue
 		}
 
+		g.markSeen(ctx, newCommit)
 		return nil
 	}
 
 	return fmt.Errorf("set ref: %w", updateErr)
 }
+
+// markSeen records that the backend wrote the given commit to the ref.
+func (g *GitBackend) markSeen(ctx context.Context, commit git.Hash) {
+	if g.seenRef == "" {
+		return
+	}
+
+	if err := g.repo.SetRef(ctx, git.SetRefRequest{
+		Ref:  g.seenRef,
+		Hash: commit,
+	}); err != nil {
+		// Verify will report a false positive next time,
+		// but this isn't worth failing the write over.
+		g.log.Warn("could not record data ref", "err", err)
+	}
+}
+
+// AcceptCurrent records the current commit of the data reference
+// as written by the backend,
+// so that Verify stops reporting it as rewritten.
+// If the reference doesn't exist, Verify will succeed
+// until the backend writes to it again.
+func (g *GitBackend) AcceptCurrent(ctx context.Context) error {
+	if g.seenRef == "" {
+		return nil
+	}
+
+	current, err := g.repo.PeelToCommit(ctx, g.ref)
+	if err != nil {
+		// The reference was deleted.
+		// Forget the last commit we saw.
+		current = git.ZeroHash
+	}
+
+	if err := g.repo.SetRef(ctx, git.SetRefRequest{
+		Ref:  g.seenRef,
+		Hash: current,
+	}); err != nil {
+		return fmt.Errorf("update %v: %w", g.seenRef, err)
+	}
+	return nil
+}
+
+// RewrittenError is returned by Verify
+// if the data reference was rewritten
+// by something other than the backend.
+type RewrittenError struct {
+	// Ref is the data reference.
+	Ref string
+
+	// Seen is the last commit written to Ref by the backend.
+	Seen git.Hash
+
+	// Current is the commit Ref currently points to.
+	// This is empty if the reference was deleted.
+	Current git.Hash
+}
+
+func (e *RewrittenError) Error() string {
+	if e.Current == "" {
+		return fmt.Sprintf("%v was deleted: last known commit was %v", e.Ref, e.Seen.Short())
+	}
+	return fmt.Sprintf("%v was rewritten: %v is not based on last known commit %v",
+		e.Ref, e.Current.Short(), e.Seen.Short())
+}
+
+// Verify checks that the history of the data reference
+// still includes the last commit written to it by the backend.
+// If not, the data was rewritten, rolled back, or deleted
+// outside of the backend, and it returns a [RewrittenError].
+//
+// Verify always succeeds if the backend was not configured with a SeenRef
+// or it hasn't written anything yet.
+func (g *GitBackend) Verify(ctx context.Context) error {
+	if g.seenRef == "" {
+		return nil
+	}
+
+	seen, err := g.repo.PeelToCommit(ctx, g.seenRef)
+	if err != nil {
+		return nil // nothing written yet
+	}
+
+	current, err := g.repo.PeelToCommit(ctx, g.ref)
+	if err != nil {
+		return &RewrittenError{Ref: g.ref, Seen: seen}
+	}
+
+	if current != seen && !g.repo.IsAncestor(ctx, seen, current) {
+		return &RewrittenError{Ref: g.ref, Seen: seen, Current: current}
+	}
+
+	return nil
+}
@@ -39,3 +39,69 @@ func TestGitBackendUpdateNoChanges(t *testing.T) {
 	assert.Equal(t, start, end,
 		"there should be no changes in the repository")
 }
+
+func TestGitBackendVerify(t *testing.T) {
+	ctx := context.Background()
+	repo, err := git.Init(ctx, t.TempDir(), git.InitOptions{
+		Log: logtest.New(t),
+	})
+	require.NoError(t, err)
+
+	backend := NewGitBackend(GitConfig{
+		Repo:        repo,
+		Ref:         "refs/data",
+		SeenRef:     "refs/data-seen",
+		AuthorName:  "Test Author",
+		AuthorEmail: "test@example.com",
+		Log:         logtest.New(t),
+	})
+	db := NewDB(backend)
+
+	// Nothing written yet.
+	require.NoError(t, backend.Verify(ctx))
+
+	require.NoError(t, db.Set(ctx, "foo", "bar", "first"))
+	first, err := repo.PeelToCommit(ctx, "refs/data")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Set(ctx, "foo", "baz", "second"))
+	second, err := repo.PeelToCommit(ctx, "refs/data")
+	require.NoError(t, err)
+	require.NoError(t, backend.Verify(ctx))
+
+	t.Run("RolledBack", func(t *testing.T) {
+		require.NoError(t, repo.SetRef(ctx, git.SetRefRequest{
+			Ref:  "refs/data",
+			Hash: first,
+		}))
+
+		var rewrittenErr *RewrittenError
+		require.ErrorAs(t, backend.Verify(ctx), &rewrittenErr)
+		assert.Equal(t, &RewrittenError{
+			Ref:     "refs/data",
+			Seen:    second,
+			Current: first,
+		}, rewrittenErr)
+
+		require.NoError(t, backend.AcceptCurrent(ctx))
+		assert.NoError(t, backend.Verify(ctx))
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		require.NoError(t, repo.SetRef(ctx, git.SetRefRequest{
+			Ref:  "refs/data",
+			Hash: git.ZeroHash,
+		}))
+
+		var rewrittenErr *RewrittenError
+		require.ErrorAs(t, backend.Verify(ctx), &rewrittenErr)
+		assert.Empty(t, rewrittenErr.Current)
+
+		require.NoError(t, backend.AcceptCurrent(ctx))
+		assert.NoError(t, backend.Verify(ctx))
+
+		// Writing again starts a new history.
+		require.NoError(t, db.Set(ctx, "foo", "qux", "third"))
+		assert.NoError(t, backend.Verify(ctx))
+	})
+}
@@ -48,6 +48,19 @@ type Graph[T any] struct {
 // Options configure the rendering of the tree.
 type Options[T any] struct {
 	Style *Style[T]
+
+	// Reverse renders the tree top-down instead:
+	// parent first, then children.
+	//
+	// For example, the tree in the package documentation
+	// would look like this:
+	//
+	//	main
+	//	┣━┳□ feat1
+	//	┃ ┗━□ feat1.1
+	//	┗━┳□ feat2
+	//	  ┗━□ feat2.1
+	Reverse bool
 }
 
 // Style configures the visual appearance of the tree.
@@ -84,7 +97,13 @@ func Write[T any](w io.Writer, g Graph[T], opts Options[T]) error {
 		style: opts.Style,
 	}
 	for _, root := range g.Roots {
-		if err := tw.writeTree(root, nil, nil); err != nil {
+		var err error
+		if opts.Reverse {
+			err = tw.writeTreeReversed(root, nil, nil)
+		} else {
+			err = tw.writeTree(root, nil, nil)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -105,6 +124,10 @@ const (
 	_horizontalUp  boxRune = '┻'
 	_verticalRight boxRune = '┣'
 	_downRight     boxRune = '┏'
+
+	// Used only by reversed trees.
+	_horizontalDown boxRune = '┳'
+	_upRight        boxRune = '┗'
 )
 
 type boxRune rune
@@ -115,7 +138,8 @@ func (b boxRune) String() string {
 
 func (b boxRune) Valid() bool {
 	switch b {
-	case _vertical, _horizontal, _horizontalUp, _verticalRight, _downRight:
+	case _vertical, _horizontal, _horizontalUp, _verticalRight, _downRight,
+		_horizontalDown, _upRight:
 		return true
 	default:
 		return false
@@ -124,7 +148,7 @@ func (b boxRune) Valid() bool {
 
 func (b boxRune) HasLeft() bool {
 	switch b {
-	case _horizontal, _horizontalUp:
+	case _horizontal, _horizontalUp, _horizontalDown:
 		return true
 	default:
 		return false
@@ -133,7 +157,8 @@ func (b boxRune) HasLeft() bool {
 
 func (b boxRune) HasRight() bool {
 	switch b {
-	case _horizontal, _downRight, _verticalRight, _horizontalUp:
+	case _horizontal, _downRight, _verticalRight, _horizontalUp,
+		_horizontalDown, _upRight:
 		return true
 	default:
 		return false
@@ -142,7 +167,7 @@ func (b boxRune) HasRight() bool {
 
 func (b boxRune) HasUp() bool {
 	switch b {
-	case _vertical, _horizontalUp, _verticalRight:
+	case _vertical, _horizontalUp, _verticalRight, _upRight:
 		return true
 	default:
 		return false
@@ -151,7 +176,7 @@ func (b boxRune) HasUp() bool {
 
 func (b boxRune) HasDown() bool {
 	switch b {
-	case _vertical, _verticalRight, _downRight:
+	case _vertical, _verticalRight, _downRight, _horizontalDown:
 		return true
 	default:
 		return false
@@ -250,6 +275,95 @@ func (tw *treeWriter[T]) pipes(path []int, joint string, marker string) {
 	_, _ = tw.w.WriteString(style.Render(joint) + marker)
 }
 
+// writeTreeReversed renders a subtree of the tree rooted at node
+// top-down: the node first, then its children.
+//
+// lasts[i] reports whether the node at depth i+1
+// on the path from the root to the current node
+// is the last child of its parent.
+// An empty lasts means the current node is the root.
+//
+// nodes[i] is the node for lasts[i], as an index into the Values slice.
+func (tw *treeWriter[T]) writeTreeReversed(nodeIdx int, lasts []bool, pathNodeIxes []int) error {
+	// Infinite loops are possible.
+	for _, n := range pathNodeIxes {
+		if n == nodeIdx {
+			return &CycleError{Nodes: append(slices.Clone(pathNodeIxes), n)}
+		}
+	}
+
+	nodeValue := tw.g.Values[nodeIdx]
+	children := tw.g.Edges(nodeValue)
+
+	// In the following:
+	//  ━┳□ feat1
+	//   ┗━□ feat1.1
+	// If the node has children,
+	// it needs a downwards joint for the branch below,
+	// and the pipe continues through the rest of its text.
+	titlePrefix := tw.style.NodeMarker(nodeValue).String() + " "
+	bodyPrefix := strings.Repeat(" ", lipgloss.Width(titlePrefix))
+	if len(children) > 0 {
+		titlePrefix = tw.style.Joint.Render(string(_horizontalDown)) + titlePrefix
+		bodyPrefix = tw.style.Joint.Render(string(_vertical)) + bodyPrefix
+	}
+
+	// The last child has no siblings below it,
+	// so it needs no connecting pipe.
+	titleJoint := string(_verticalRight) + string(_horizontal)
+	bodyJoint := string(_vertical) + " "
+	if len(lasts) > 0 && lasts[len(lasts)-1] {
+		titleJoint = string(_upRight) + string(_horizontal)
+		bodyJoint = "  "
+	}
+
+	lines := strings.Split(tw.g.View(nodeValue), "\n")
+	for idx, line := range lines {
+		// The text may be multi-line.
+		// Only the first line has a title marker.
+		if idx == 0 {
+			tw.pipesReversed(lasts, titleJoint, titlePrefix)
+		} else {
+			tw.pipesReversed(lasts, bodyJoint, bodyPrefix)
+		}
+
+		_, _ = tw.w.WriteString(line)
+		_, _ = tw.w.WriteString("\n")
+		tw.lineNum++
+	}
+
+	for i, child := range children {
+		last := i == len(children)-1
+		if err := tw.writeTreeReversed(child, append(lasts, last), append(pathNodeIxes, nodeIdx)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tw *treeWriter[T]) pipesReversed(lasts []bool, joint string, marker string) {
+	if len(lasts) == 0 {
+		return
+	}
+
+	style := tw.style.Joint
+
+	// Ancestors that have siblings below them
+	// need connecting pipes.
+	for _, last := range lasts[:len(lasts)-1] {
+		if last {
+			_, _ = tw.w.WriteString("  ")
+		} else {
+			_, _ = tw.w.WriteString(
+				style.Render(string(_vertical) + " "),
+			)
+		}
+	}
+
+	_, _ = tw.w.WriteString(style.Render(joint) + marker)
+}
+
 // CycleError is returned when a cycle is detected in the tree.
 type CycleError struct {
 	// Nodes that form the cycle.
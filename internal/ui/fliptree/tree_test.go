@@ -32,10 +32,11 @@ func plainStyle() *Style[string] {
 
 func TestWrite(t *testing.T) {
 	type testCase struct {
-		Name   string              `yaml:"name"`
-		Roots  []string            `yaml:"roots"`
-		Graph  map[string][]string `yaml:"graph"`
-		Values map[string]string   `yaml:"values,omitempty"`
+		Name    string              `yaml:"name"`
+		Roots   []string            `yaml:"roots"`
+		Graph   map[string][]string `yaml:"graph"`
+		Values  map[string]string   `yaml:"values,omitempty"`
+		Reverse bool                `yaml:"reverse,omitempty"`
 
 		Want string `yaml:"want"`
 	}
@@ -96,7 +97,8 @@ func TestWrite(t *testing.T) {
 
 			var sb strings.Builder
 			err := Write(&sb, g, Options[string]{
-				Style: plainStyle(),
+				Style:   plainStyle(),
+				Reverse: tt.Reverse,
 			})
 			require.NoError(t, err)
 
@@ -202,9 +204,12 @@ func testWriteProperty(t *rapid.T) {
 		Edges:  func(n string) []int { return edges[n] },
 	}
 
+	reverse := rapid.Bool().Draw(t, "reverse")
+
 	var out strings.Builder
 	if err := Write(&out, g, Options[string]{
-		Style: plainStyle(),
+		Style:   plainStyle(),
+		Reverse: reverse,
 	}); err != nil {
 		t.Skip(err)
 	}
@@ -267,7 +272,15 @@ func testWriteProperty(t *rapid.T) {
 				}
 
 				above := boxRune(lineAbove[runeIdx])
-				if !above.Valid() || !above.HasDown() {
+				ok := above.Valid() && above.HasDown()
+
+				// In reversed trees, the first child of a root
+				// attaches to the root's text.
+				if reverse && !above.Valid() && unicode.IsPrint(rune(above)) {
+					ok = true
+				}
+
+				if !ok {
 					t.Errorf("%d:%d:joint %q wants attachment above, got: %q", lineNo, colNo, r, above)
 				}
 			}
@@ -158,6 +158,11 @@ func (a *OpenEditor) Update(msg tea.Msg) tea.Cmd {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, a.KeyMap.Edit) && a.Editor.Command != "":
+			if err := execedit.Resolve(a.Editor.Command); err != nil {
+				a.err = err
+				return tea.Quit
+			}
+
 			ext := strings.TrimPrefix(a.Editor.Ext, ".")
 
 			tmpFile, err := osutil.TempFilePath("", "*."+ext)
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.abhg.dev/gs/internal/git"
+)
+
+// _defaultIssueRegex matches issue numbers in branch names
+// like "issue-123-fix".
+// This may be overridden with 'git config spice.submit.issueRegex'.
+const _defaultIssueRegex = `issue-(\d+)`
+
+// branchIssue extracts an issue number from the name of a branch
+// with the regular expression configured with
+// 'git config spice.submit.issueRegex'.
+//
+// It returns an empty string if the branch name doesn't reference an issue.
+func branchIssue(ctx context.Context, repo *git.Repository, branch string) (string, error) {
+	pattern, err := repo.ConfigValue(ctx, "spice.submit.issueRegex")
+	if err != nil {
+		if !errors.Is(err, git.ErrNotExist) {
+			return "", fmt.Errorf("read spice.submit.issueRegex: %w", err)
+		}
+		pattern = _defaultIssueRegex
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("bad spice.submit.issueRegex: %w", err)
+	}
+
+	return matchIssue(re, branch), nil
+}
+
+// matchIssue returns the issue number matched by re in a branch name.
+// The first capturing group holds the issue number,
+// or the entire match if there are no groups.
+func matchIssue(re *regexp.Regexp, branch string) string {
+	m := re.FindStringSubmatch(branch)
+	switch {
+	case m == nil:
+		return ""
+	case len(m) > 1:
+		return m[1]
+	default:
+		return m[0]
+	}
+}
+
+// appendIssueLink adds a line to body that closes the given issue
+// when the change is merged.
+// The body is returned unchanged if it already references the issue.
+func appendIssueLink(body, issue string) string {
+	ref := regexp.MustCompile(`#` + regexp.QuoteMeta(issue) + `\b`)
+	if ref.MatchString(body) {
+		return body
+	}
+
+	body = strings.TrimRight(body, "\n")
+	if body != "" {
+		body += "\n\n"
+	}
+	return body + "Closes #" + issue + "\n"
+}
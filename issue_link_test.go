@@ -0,0 +1,82 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchIssue(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		branch  string
+		want    string
+	}{
+		{
+			name:    "Default",
+			pattern: _defaultIssueRegex,
+			branch:  "issue-123-fix",
+			want:    "123",
+		},
+		{
+			name:    "DefaultNested",
+			pattern: _defaultIssueRegex,
+			branch:  "alice/issue-42",
+			want:    "42",
+		},
+		{
+			name:    "NoMatch",
+			pattern: _defaultIssueRegex,
+			branch:  "fix-the-thing",
+		},
+		{
+			name:    "NoGroup",
+			pattern: `\d+`,
+			branch:  "gh-567-cleanup",
+			want:    "567",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := regexp.MustCompile(tt.pattern)
+			assert.Equal(t, tt.want, matchIssue(re, tt.branch))
+		})
+	}
+}
+
+func TestAppendIssueLink(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "Empty",
+			want: "Closes #123\n",
+		},
+		{
+			name: "Body",
+			body: "Fixes the thing.\n",
+			want: "Fixes the thing.\n\nCloses #123\n",
+		},
+		{
+			name: "AlreadyReferenced",
+			body: "Fixes #123.",
+			want: "Fixes #123.",
+		},
+		{
+			name: "OtherIssue",
+			body: "Related to #1234.",
+			want: "Related to #1234.\n\nCloses #123\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, appendIssueLink(tt.body, "123"))
+		})
+	}
+}
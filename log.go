@@ -45,9 +45,23 @@ var (
 			SetString("◀")
 )
 
+// changeLink renders a CR for display,
+// including its web URL if it's known.
+func changeLink(id forge.ChangeID, url string) string {
+	if url == "" {
+		return id.String()
+	}
+	return fmt.Sprintf("%v: %v", id, url)
+}
+
 // branchLogCmd is the shared implementation of logShortCmd and logLongCmd.
 type branchLogCmd struct {
-	All bool `short:"a" long:"all" help:"Show all tracked branches, not just the current stack."`
+	All   bool `short:"a" long:"all" xor:"scope" help:"Show all tracked branches, not just the current stack."`
+	Stack bool `long:"stack" xor:"scope" help:"Show only the current stack. This is the default."`
+
+	Reverse bool `long:"reverse" help:"Show the trunk at the top with branches below their bases."`
+	Depth   int  `long:"depth" placeholder:"N" help:"Show only branches up to N levels above and below the current branch."`
+	URLs    bool `name:"urls" help:"Show the URLs of change requests next to their IDs."`
 }
 
 type branchLogOptions struct {
@@ -65,8 +79,25 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 		return err
 	}
 
+	// Listing branches must never modify the state.
+	store = store.ReadOnly()
+	svc = spice.NewService(ctx, repo, store, log)
+
+	switch {
+	case cmd.Depth < 0:
+		return errors.New("--depth must not be negative")
+	case cmd.Depth > 0 && cmd.All:
+		return errors.New("--depth cannot be used with --all")
+	}
+
 	currentBranch, err := repo.CurrentBranch(ctx)
 	if err != nil {
+		if cmd.Stack {
+			return fmt.Errorf("--stack requires a current branch: %w", err)
+		}
+		if cmd.Depth > 0 {
+			return fmt.Errorf("--depth requires a current branch: %w", err)
+		}
 		currentBranch = "" // may be detached
 	}
 
@@ -81,6 +112,9 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 		Base     string
 		ChangeID forge.ChangeID
 
+		// ChangeURL is the cached URL of the CR, if known.
+		ChangeURL string
+
 		Commits []git.CommitDetail
 		Aboves  []int
 	}
@@ -94,6 +128,7 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 		}
 		if branch.Change != nil {
 			info.ChangeID = branch.Change.ChangeID()
+			info.ChangeURL = branch.ChangeURL
 		}
 
 		if opts.Commits {
@@ -135,23 +170,38 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 		infos[baseIdx].Aboves = append(infos[baseIdx].Aboves, idx)
 	}
 
+	// With --depth, the bottom-most visible branch
+	// may not be the trunk.
+	rootIdx := trunkIdx
 	isVisible := func(*branchInfo) bool { return true }
 	if !cmd.All && currentBranch != "" {
 		visible := make(map[int]struct{})
 		currentBranchIdx := infoIdxByName[currentBranch]
 
 		// Add the upstacks of the current branch to the visible set.
-		for unseen := []int{currentBranchIdx}; len(unseen) > 0; {
-			idx := unseen[len(unseen)-1]
+		type upstackItem struct{ idx, depth int }
+		for unseen := []upstackItem{{currentBranchIdx, 0}}; len(unseen) > 0; {
+			item := unseen[len(unseen)-1]
 			unseen = unseen[:len(unseen)-1]
 
-			visible[idx] = struct{}{}
-			unseen = append(unseen, infos[idx].Aboves...)
+			visible[item.idx] = struct{}{}
+			if cmd.Depth > 0 && item.depth >= cmd.Depth {
+				continue
+			}
+			for _, above := range infos[item.idx].Aboves {
+				unseen = append(unseen, upstackItem{above, item.depth + 1})
+			}
 		}
 
 		// Add the downstack of the current branch to the visible set.
+		depth := 0
 		for idx, ok := currentBranchIdx, true; ok; idx, ok = infoIdxByName[infos[idx].Base] {
 			visible[idx] = struct{}{}
+			if cmd.Depth > 0 && depth >= cmd.Depth {
+				rootIdx = idx
+				break
+			}
+			depth++
 		}
 
 		isVisible = func(info *branchInfo) bool {
@@ -170,7 +220,7 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 
 	var s strings.Builder
 	err = fliptree.Write(&s, fliptree.Graph[*branchInfo]{
-		Roots:  []int{trunkIdx},
+		Roots:  []int{rootIdx},
 		Values: infos,
 		View: func(b *branchInfo) string {
 			var o strings.Builder
@@ -181,7 +231,11 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 			}
 
 			if b.ChangeID != nil {
-				_, _ = fmt.Fprintf(&o, " (%v)", b.ChangeID)
+				if cmd.URLs {
+					_, _ = fmt.Fprintf(&o, " (%v)", changeLink(b.ChangeID, b.ChangeURL))
+				} else {
+					_, _ = fmt.Fprintf(&o, " (%v)", b.ChangeID)
+				}
 			}
 
 			if restackErr := new(spice.BranchNeedsRestackError); errors.As(svc.VerifyRestacked(ctx, b.Name), &restackErr) {
@@ -202,7 +256,7 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 				(&widget.CommitSummary{
 					ShortHash:  commit.ShortHash,
 					Subject:    commit.Subject,
-					AuthorDate: commit.AuthorDate,
+					AuthorDate: commit.Author.Time,
 				}).Render(&o, commitStyle)
 			}
 
@@ -217,7 +271,10 @@ func (cmd *branchLogCmd) run(ctx context.Context, opts *branchLogOptions) (err e
 			}
 			return aboves
 		},
-	}, fliptree.Options[*branchInfo]{Style: treeStyle})
+	}, fliptree.Options[*branchInfo]{
+		Style:   treeStyle,
+		Reverse: cmd.Reverse,
+	})
 	if err != nil {
 		return fmt.Errorf("write tree: %w", err)
 	}
@@ -16,6 +16,15 @@ func (*logLongCmd) Help() string {
 		Only branches that are upstack and downstack from the current
 		branch are shown.
 		Use with the -a/--all flag to show all tracked branches.
+		If the current branch can't be determined (e.g. detached HEAD),
+		all tracked branches are shown unless --stack is used.
+
+		Use --depth to show only branches up to the given number of levels
+		above and below the current branch.
+		Use --reverse to show the trunk at the top
+		with branches below their bases.
+		Use --urls to show the URLs of change requests
+		recorded when they were last submitted.
 	`)
 }
 
@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -16,7 +17,9 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/mattn/go-isatty"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/gitea"
 	"go.abhg.dev/gs/internal/forge/github"
+	"go.abhg.dev/gs/internal/forge/gitlab"
 	"go.abhg.dev/gs/internal/komplete"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/ui"
@@ -40,6 +43,8 @@ func main() {
 
 	// Register supported forges.
 	forge.Register(&github.Forge{Log: logger})
+	forge.Register(&gitlab.Forge{Log: logger})
+	forge.Register(&gitea.Forge{Log: logger})
 
 	styles := log.DefaultStyles()
 	styles.Levels[log.DebugLevel] = ui.NewStyle().SetString("DBG").Bold(true)
@@ -216,10 +221,30 @@ func main() {
 	}
 
 	if err := kctx.Run(shorthands); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			logger.Errorf("gs: %v", exitErr.Err)
+			os.Exit(exitErr.Code)
+		}
 		logger.Fatalf("gs: %v", err)
 	}
 }
 
+// exitError is an error that makes gs exit with a specific status code
+// instead of the default status code of 1.
+type exitError struct {
+	Code int
+	Err  error
+}
+
+func (e *exitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.Err
+}
+
 type shorthand struct {
 	Expanded []string
 	Command  *kong.Node
@@ -237,6 +262,15 @@ type globalOptions struct {
 	// Flags that are accessed directly:
 
 	Prompt bool `name:"prompt" negatable:"" default:"${defaultPrompt}" help:"Whether to prompt for missing information"`
+	Strict bool `help:"Fail instead of warning if the git-spice data was changed outside of git-spice"`
+
+	// RepoPath is the working tree of the repository to operate on.
+	// If empty, the current directory is used.
+	//
+	// Unlike -C, this does not change the working directory,
+	// so paths in other arguments are resolved relative to
+	// the current directory.
+	RepoPath string `name:"repo" type:"existingdir" placeholder:"PATH" help:"Operate on the Git working tree at PATH instead of the current directory" predictor:"dirs"`
 }
 
 type mainCmd struct {
@@ -27,7 +27,7 @@ func (*rebaseAbortCmd) Help() string {
 }
 
 func (cmd *rebaseAbortCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, opts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
@@ -34,7 +34,7 @@ func (cmd *rebaseContinueCmd) Run(
 	opts *globalOptions,
 	parser *kong.Kong,
 ) error {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, opts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
@@ -46,7 +46,8 @@ func (cmd *rebaseContinueCmd) Run(
 		return err
 	}
 
-	if _, err := repo.RebaseState(ctx); err != nil {
+	rebaseState, err := repo.RebaseState(ctx)
+	if err != nil {
 		if !errors.Is(err, git.ErrNoRebase) {
 			return fmt.Errorf("get rebase state: %w", err)
 		}
@@ -56,7 +57,33 @@ func (cmd *rebaseContinueCmd) Run(
 	// Finish the ongoing rebase.
 	if err := repo.RebaseContinue(ctx); err != nil {
 		var rebaseErr *git.RebaseInterruptError
-		if errors.As(err, &rebaseErr) {
+		if !errors.As(err, &rebaseErr) {
+			return err
+		}
+
+		// A git-spice operation may rebase several branches
+		// with a single rebase (e.g. a chain of branches in a restack).
+		// If that operation is still in progress,
+		// report the conflict as the operation would
+		// so that it's aborted with 'gs rebase abort'.
+		chained := rebaseState.UpdateRefs && rebaseErr.Kind == git.RebaseInterruptConflict
+		if chained {
+			pending, perr := store.HasContinuations(ctx)
+			if perr != nil {
+				return errors.Join(err, fmt.Errorf("get rebase continuations: %w", perr))
+			}
+			chained = pending
+		}
+
+		if chained {
+			var msg strings.Builder
+			fmt.Fprintf(&msg, "There was a conflict while rebasing.\n")
+			fmt.Fprintf(&msg, "Resolve the conflict and run:\n")
+			fmt.Fprintf(&msg, "  gs rebase continue\n")
+			fmt.Fprintf(&msg, "Or abort the operation with:\n")
+			fmt.Fprintf(&msg, "  gs rebase abort\n")
+			log.Error(msg.String())
+		} else {
 			var msg strings.Builder
 			fmt.Fprintf(&msg, "There are more conflicts to resolve.\n")
 			fmt.Fprintf(&msg, "Resolve them and run the following command again:\n")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/forge"
@@ -11,23 +12,40 @@ import (
 	"go.abhg.dev/gs/internal/secret"
 )
 
+// openRemoteRepository opens the repository at the given remote
+// with the forge that hosts it.
+//
+// The forge is detected from the remote URL
+// unless forgeID is non-empty.
 func openRemoteRepository(
 	ctx context.Context,
 	log *log.Logger,
 	stash secret.Stash,
 	gitRepo *git.Repository,
 	remote string,
+	forgeID string,
 ) (forge.Repository, error) {
 	remoteURL, err := gitRepo.RemoteURL(ctx, remote)
 	if err != nil {
 		return nil, fmt.Errorf("get remote URL: %w", err)
 	}
 
-	f, ok := forge.MatchForgeURL(remoteURL)
-	if !ok {
-		log.Error("Could not guess repository from remote URL", "url", remoteURL)
-		log.Error("Are you sure the remote identifies a supported Git host?")
-		return nil, errors.New("unsupported Git remote URL")
+	var f forge.Forge
+	if forgeID != "" {
+		var ok bool
+		f, ok = forge.Lookup(forgeID)
+		if !ok {
+			log.Errorf("Forge ID must be one of: %s", strings.Join(forge.IDs(), ", "))
+			return nil, fmt.Errorf("unknown forge: %s", forgeID)
+		}
+	} else {
+		var ok bool
+		f, ok = forge.MatchForgeURL(remoteURL)
+		if !ok {
+			log.Error("Could not guess repository from remote URL", "url", remoteURL)
+			log.Error("Are you sure the remote identifies a supported Git host?")
+			return nil, errors.New("unsupported Git remote URL")
+		}
 	}
 
 	tok, err := f.LoadAuthenticationToken(stash)
@@ -3,4 +3,11 @@ package main
 type repoCmd struct {
 	Init repoInitCmd `cmd:"" aliases:"i" help:"Initialize a repository"`
 	Sync repoSyncCmd `cmd:"" aliases:"s" help:"Pull latest changes from the remote"`
+
+	Doctor repoDoctorCmd `cmd:"" help:"Find and repair state for branches deleted with plain git"`
+	Export repoExportCmd `cmd:"" help:"Write gs state to stdout"`
+	Import repoImportCmd `cmd:"" help:"Load gs state written by 'repo export'"`
+
+	Retrunk        repoRetrunkCmd        `cmd:"" help:"Rename the trunk branch"`
+	SubmitDefaults repoSubmitDefaultsCmd `cmd:"" name:"submit-defaults" help:"Save reviewers and labels for new change requests"`
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+type repoDoctorCmd struct {
+	Fix bool `help:"Repair problems without prompting"`
+}
+
+func (*repoDoctorCmd) Help() string {
+	return text.Dedent(`
+		Checks the state stored by gs for tracked branches
+		that were deleted with plain git,
+		and for tracked branches whose base was deleted.
+
+		If problems are found, a prompt will ask to repair them:
+		deleted branches are forgotten,
+		and branches based on them are moved onto
+		the nearest surviving branch below them, or the trunk.
+		Use --fix to repair without prompting.
+		Branches that were moved may need to be restacked afterwards.
+	`)
+}
+
+func (cmd *repoDoctorCmd) Run(
+	ctx context.Context,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	_, _, svc, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	report, err := svc.CheckBranchState(ctx)
+	if err != nil {
+		return fmt.Errorf("check branch state: %w", err)
+	}
+	if report.Empty() {
+		log.Info("No problems found")
+		return nil
+	}
+
+	for _, name := range report.Deleted {
+		log.Warnf("%v: tracked branch no longer exists", name)
+	}
+	for _, o := range report.Orphans {
+		log.Warnf("%v: base %v no longer exists; can be moved onto %v", o.Name, o.Base, o.NewBase)
+	}
+
+	fix := cmd.Fix
+	if !fix {
+		if !opts.Prompt {
+			log.Info("Use --fix to repair")
+			return nil
+		}
+
+		prompt := ui.NewConfirm().
+			WithTitle("Repair branch state?").
+			WithDescription("Deleted branches will be forgotten, and branches based on them will be moved").
+			WithValue(&fix)
+		if err := ui.Run(prompt); err != nil {
+			return fmt.Errorf("prompt: %w", err)
+		}
+		if !fix {
+			return nil
+		}
+	}
+
+	if err := svc.RepairBranchState(ctx, report); err != nil {
+		return fmt.Errorf("repair branch state: %w", err)
+	}
+	for _, o := range report.Orphans {
+		log.Infof("%v: moved onto %v", o.Name, o.NewBase)
+	}
+	log.Infof("Repaired branch state")
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type repoExportCmd struct{}
+
+func (*repoExportCmd) Help() string {
+	return text.Dedent(`
+		Writes the repository's trunk, remote, and all tracked branches
+		to stdout as a single JSON document.
+
+		Use this to back up gs state before risky operations,
+		or to move it to another clone with 'gs repo import'.
+	`)
+}
+
+func (*repoExportCmd) Run(
+	ctx context.Context,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	_, store, _, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	return store.Export(ctx, os.Stdout)
+}
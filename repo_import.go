@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type repoImportCmd struct {
+	File  string `arg:"" type:"path" help:"File written by 'gs repo export'. Use '-' to read from stdin."`
+	Force bool   `help:"Replace branches that are already tracked"`
+}
+
+func (*repoImportCmd) Help() string {
+	return text.Dedent(`
+		Loads the trunk, remote, and tracked branches
+		from a file written by 'gs repo export'.
+
+		The imported branches must form a tree based on the trunk.
+		Importing into a repository that already tracks branches
+		is refused unless --force is given,
+		in which case all tracked branches are replaced.
+	`)
+}
+
+func (cmd *repoImportCmd) Run(
+	ctx context.Context,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	_, store, _, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if cmd.File != "-" {
+		f, err := os.Open(cmd.File)
+		if err != nil {
+			return fmt.Errorf("open: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	err = store.Import(ctx, r, &state.ImportOptions{Force: cmd.Force})
+	if err != nil {
+		if errors.Is(err, state.ErrWouldOverwrite) {
+			log.Error("Use --force to replace them")
+		}
+		return fmt.Errorf("import: %w", err)
+	}
+
+	branches, err := store.ListBranches(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infof("Imported %d branches", len(branches))
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
@@ -40,7 +41,7 @@ func (*repoInitCmd) Help() string {
 }
 
 func (cmd *repoInitCmd) Run(ctx context.Context, log *log.Logger, globalOpts *globalOptions) error {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, globalOpts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
@@ -79,7 +80,15 @@ func (cmd *repoInitCmd) Run(ctx context.Context, log *log.Logger, globalOpts *gl
 		},
 	}
 
-	if cmd.Remote == "" {
+	if cmd.Remote != "" {
+		remotes, err := repo.ListRemotes(ctx)
+		if err != nil {
+			return fmt.Errorf("list remotes: %w", err)
+		}
+		if !slices.Contains(remotes, cmd.Remote) {
+			return fmt.Errorf("remote %q does not exist", cmd.Remote)
+		}
+	} else {
 		cmd.Remote, err = guesser.GuessRemote(ctx, repo)
 		if err != nil {
 			return fmt.Errorf("guess remote: %w", err)
@@ -115,24 +124,30 @@ func (cmd *repoInitCmd) Run(ctx context.Context, log *log.Logger, globalOpts *gl
 
 const (
 	_dataRef     = "refs/spice/data"
+	_dataSeenRef = "refs/spice/data-seen"
 	_authorName  = "git-spice"
 	_authorEmail = "git-spice@localhost"
 )
 
 func newRepoStorage(repo storage.GitRepository, log *log.Logger) *storage.DB {
-	return storage.NewDB(storage.NewGitBackend(storage.GitConfig{
+	return storage.NewDB(newRepoBackend(repo, log))
+}
+
+func newRepoBackend(repo storage.GitRepository, log *log.Logger) *storage.GitBackend {
+	return storage.NewGitBackend(storage.GitConfig{
 		Repo:        repo,
 		Ref:         _dataRef,
+		SeenRef:     _dataSeenRef,
 		AuthorName:  _authorName,
 		AuthorEmail: _authorEmail,
 		Log:         log,
-	}))
+	})
 }
 
 func openRepo(ctx context.Context, log *log.Logger, opts *globalOptions) (
 	*git.Repository, *state.Store, *spice.Service, error,
 ) {
-	repo, err := git.Open(ctx, ".", git.OpenOptions{
+	repo, err := git.Open(ctx, opts.RepoPath, git.OpenOptions{
 		Log: log,
 	})
 	if err != nil {
@@ -159,7 +174,12 @@ func ensureStore(
 	log *log.Logger,
 	opts *globalOptions,
 ) (*state.Store, error) {
-	db := newRepoStorage(repo, log)
+	backend := newRepoBackend(repo, log)
+	if err := verifyStorage(ctx, backend, log, opts); err != nil {
+		return nil, err
+	}
+
+	db := storage.NewDB(backend)
 	store, err := state.OpenStore(ctx, db, log)
 	if err == nil {
 		return store, nil
@@ -178,6 +198,42 @@ func ensureStore(
 	return nil, fmt.Errorf("open store: %w", err)
 }
 
+// verifyStorage checks that the git-spice data wasn't rewritten
+// outside of git-spice, e.g. by a force push or a reset of the data ref.
+// This warns about it, or fails with --strict.
+//
+// The warning is reported only once:
+// the current state is accepted afterwards.
+func verifyStorage(
+	ctx context.Context,
+	backend *storage.GitBackend,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	err := backend.Verify(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var rewrittenErr *storage.RewrittenError
+	if !errors.As(err, &rewrittenErr) {
+		return fmt.Errorf("verify storage: %w", err)
+	}
+
+	if opts.Strict {
+		log.Error("The git-spice data was changed outside of git-spice.")
+		log.Error("Run without --strict to accept the changes.")
+		return fmt.Errorf("verify storage: %w", err)
+	}
+
+	log.Warnf("The git-spice data was changed outside of git-spice: %v", err)
+	log.Warn("Information about tracked branches may have been lost or reverted.")
+	if err := backend.AcceptCurrent(ctx); err != nil {
+		log.Warn("Could not accept current data", "error", err)
+	}
+	return nil
+}
+
 func ensureRemote(
 	ctx context.Context,
 	repo spice.GitRepository,
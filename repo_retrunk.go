@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type repoRetrunkCmd struct {
+	Remote bool   `help:"Also change the base of open change requests to the new trunk"`
+	Trunk  string `arg:"" help:"New name of the trunk branch"`
+}
+
+func (*repoRetrunkCmd) Help() string {
+	return text.Dedent(`
+		Use this when the trunk branch is renamed,
+		for example, from "master" to "main".
+		The local trunk branch is renamed to the new name
+		unless a branch with that name already exists.
+		Branches based on the old trunk are moved onto the new trunk.
+
+		Use --remote to also change the base of open Change Requests
+		that target the old trunk.
+	`)
+}
+
+func (cmd *repoRetrunkCmd) Run(
+	ctx context.Context,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	repo, store, svc, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	oldTrunk := store.Trunk()
+	if cmd.Trunk == oldTrunk {
+		log.Infof("%v is already the trunk branch", oldTrunk)
+		return nil
+	}
+
+	if _, err := store.LookupBranch(ctx, cmd.Trunk); err == nil {
+		return fmt.Errorf("%v is a tracked branch: untrack it first", cmd.Trunk)
+	}
+
+	// Connect to the forge before changing anything
+	// so that we don't stop halfway if that fails.
+	var remoteRepo forge.Repository
+	if cmd.Remote {
+		remote, err := store.Remote()
+		if err != nil {
+			return fmt.Errorf("get remote: %w", err)
+		}
+
+		remoteRepo, err = openRemoteRepository(ctx, log, secretStash, repo, remote, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case repo.BranchExists(ctx, cmd.Trunk):
+		// The branch was already renamed or fetched.
+		// Leave the old trunk alone.
+		log.Debugf("Branch %v already exists. Not renaming %v.", cmd.Trunk, oldTrunk)
+
+	case repo.BranchExists(ctx, oldTrunk):
+		if err := repo.RenameBranch(ctx, git.RenameBranchRequest{
+			OldName: oldTrunk,
+			NewName: cmd.Trunk,
+		}); err != nil {
+			return fmt.Errorf("rename %v: %w", oldTrunk, err)
+		}
+
+	default:
+		return fmt.Errorf("neither %v nor %v exist", oldTrunk, cmd.Trunk)
+	}
+
+	// Branches above the old trunk will be moved onto the new trunk.
+	aboves, err := svc.ListAbove(ctx, oldTrunk)
+	if err != nil {
+		return fmt.Errorf("list branches above %v: %w", oldTrunk, err)
+	}
+
+	if err := store.RenameTrunk(ctx, cmd.Trunk); err != nil {
+		return fmt.Errorf("rename trunk: %w", err)
+	}
+	log.Infof("Changed trunk from %v to %v", oldTrunk, cmd.Trunk)
+
+	if remoteRepo == nil {
+		return nil
+	}
+
+	for _, name := range aboves {
+		branch, err := svc.LookupBranch(ctx, name)
+		if err != nil {
+			return fmt.Errorf("lookup branch %v: %w", name, err)
+		}
+		if branch.Change == nil {
+			continue
+		}
+
+		changeID := branch.Change.ChangeID()
+		change, err := remoteRepo.FindChangeByID(ctx, changeID)
+		if err != nil {
+			return fmt.Errorf("find change %v: %w", changeID, err)
+		}
+		if change.State != forge.ChangeOpen {
+			continue
+		}
+
+		if err := remoteRepo.EditChange(ctx, changeID, forge.EditChangeOptions{
+			Base: cmd.Trunk,
+		}); err != nil {
+			return fmt.Errorf("change base of %v: %w", changeID, err)
+		}
+		log.Infof("%v: Changed base of %v to %v", name, changeID, cmd.Trunk)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type repoSubmitDefaultsCmd struct {
+	Reviewers []string `name:"reviewer" placeholder:"NAME" help:"Request reviews on new change requests from this user or team (org/team). May be repeated."`
+	Labels    []string `name:"label" placeholder:"NAME" help:"Add this label to new change requests. May be repeated."`
+	Clear     bool     `help:"Remove all saved defaults"`
+}
+
+func (*repoSubmitDefaultsCmd) Help() string {
+	return text.Dedent(`
+		Saves reviewers and labels to use for new Change Requests
+		submitted from this repository,
+		so that they don't have to be passed to every submit.
+		Without any flags, the saved defaults are printed.
+
+		Use --reviewer and --label to set them.
+		Each replaces the saved value of the same kind,
+		leaving the other unchanged.
+		Use --clear to remove all saved defaults.
+
+		Flags passed to submit commands take precedence:
+		--reviewers replaces the default reviewers,
+		and --label the default labels.
+	`)
+}
+
+func (cmd *repoSubmitDefaultsCmd) Run(
+	ctx context.Context,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	if cmd.Clear && (len(cmd.Reviewers) > 0 || len(cmd.Labels) > 0) {
+		return errors.New("--clear cannot be used with --reviewer or --label")
+	}
+
+	_, store, _, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Clear {
+		if err := store.ClearSubmitDefaults(ctx); err != nil {
+			return err
+		}
+		log.Infof("Cleared submit defaults")
+		return nil
+	}
+
+	defaults, err := store.LoadSubmitDefaults(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Reviewers) == 0 && len(cmd.Labels) == 0 {
+		if len(defaults.Reviewers) == 0 && len(defaults.Labels) == 0 {
+			log.Infof("No submit defaults saved")
+			return nil
+		}
+
+		if len(defaults.Reviewers) > 0 {
+			fmt.Fprintf(os.Stdout, "reviewers: %s\n", strings.Join(defaults.Reviewers, ", "))
+		}
+		if len(defaults.Labels) > 0 {
+			fmt.Fprintf(os.Stdout, "labels: %s\n", strings.Join(defaults.Labels, ", "))
+		}
+		return nil
+	}
+
+	if len(cmd.Reviewers) > 0 {
+		defaults.Reviewers = cmd.Reviewers
+	}
+	if len(cmd.Labels) > 0 {
+		defaults.Labels = cmd.Labels
+	}
+	if err := store.SaveSubmitDefaults(ctx, defaults); err != nil {
+		return err
+	}
+
+	log.Infof("Saved submit defaults")
+	return nil
+}
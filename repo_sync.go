@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"runtime"
 	"slices"
 	"sync"
 
@@ -13,11 +12,15 @@ import (
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
 	"go.abhg.dev/gs/internal/text"
 	"go.abhg.dev/gs/internal/ui"
 )
 
 type repoSyncCmd struct {
+	DetectRenames bool `name:"detect-renames" help:"Look for tracked branches that were renamed with plain git and track them under their new names"`
+	RestackAll    bool `name:"restack-all" help:"Restack all tracked branches after syncing, continuing past branches with conflicts"`
+
 	// TODO: flag to not delete merged branches?
 	// TODO: flag to auto-restack current stack
 }
@@ -30,6 +33,27 @@ func (*repoSyncCmd) Help() string {
 		The repository must have a remote associated for syncing.
 		A prompt will ask for one if the repository
 		was not initialized with a remote.
+
+		Change Requests are checked concurrently,
+		making at most 4 requests to the forge at a time.
+		Change this with 'git config spice.forge.concurrency'.
+
+		Use --detect-renames to look for tracked branches
+		that were renamed outside git-spice, e.g. with 'git branch -m'.
+		Such branches are matched to untracked branches
+		by their last known head or fork point,
+		and a prompt will ask to track them under their new names.
+
+		In a shallow clone, the complete history of trunk is fetched
+		only if updating trunk fails without it.
+
+		Use --restack-all to restack every tracked branch after syncing.
+		Linear runs of branches are restacked with a single rebase
+		when Git supports it, avoiding a checkout for each branch.
+		Branches that can't be restacked because of conflicts
+		are left as they were along with the branches above them,
+		and reported at the end so that they can be restacked
+		with 'gs upstack restack'.
 	`)
 }
 
@@ -50,6 +74,14 @@ func (cmd *repoSyncCmd) Run(
 		return err
 	}
 
+	// This must happen before anything else looks at tracked branches
+	// as branches that don't exist are forgotten when they're loaded.
+	if cmd.DetectRenames {
+		if err := cmd.detectRenames(ctx, log, svc, opts); err != nil {
+			return err
+		}
+	}
+
 	currentBranch, err := repo.CurrentBranch(ctx)
 	if err != nil {
 		if !errors.Is(err, git.ErrDetachedHead) {
@@ -136,7 +168,19 @@ func (cmd *repoSyncCmd) Run(
 				},
 			}
 			if err := repo.Fetch(ctx, opts); err != nil {
-				return fmt.Errorf("fetch: %w", err)
+				// A shallow clone may be missing history
+				// needed to update trunk.
+				// Fetch all of it and try again.
+				shallow, shallowErr := repo.IsShallow(ctx)
+				if shallowErr != nil || !shallow {
+					return fmt.Errorf("fetch: %w", err)
+				}
+
+				log.Infof("%v: could not update shallow clone. Fetching complete history.", trunk)
+				opts.Unshallow = true
+				if err := repo.Fetch(ctx, opts); err != nil {
+					return fmt.Errorf("fetch: %w", err)
+				}
 			}
 		} else {
 			// (2b): Trunk has unpushed local commits
@@ -187,12 +231,60 @@ func (cmd *repoSyncCmd) Run(
 		}
 	}
 
-	remoteRepo, err := openRemoteRepository(ctx, log, secretStash, repo, remote)
+	remoteRepo, err := openRemoteRepository(ctx, log, secretStash, repo, remote, "")
 	if err != nil {
 		return err
 	}
 
-	return cmd.deleteMergedBranches(ctx, log, remote, svc, repo, remoteRepo, opts)
+	if err := cmd.deleteMergedBranches(ctx, log, remote, svc, repo, remoteRepo, opts); err != nil {
+		return err
+	}
+
+	if cmd.RestackAll {
+		return cmd.restackAll(ctx, log, repo, store, svc)
+	}
+	return nil
+}
+
+// detectRenames finds tracked branches that were renamed out of band
+// and tracks them under their new names after confirmation.
+func (cmd *repoSyncCmd) detectRenames(
+	ctx context.Context,
+	log *log.Logger,
+	svc *spice.Service,
+	opts *globalOptions,
+) error {
+	renames, err := svc.DetectRenames(ctx)
+	if err != nil {
+		return fmt.Errorf("detect renamed branches: %w", err)
+	}
+
+	for _, rename := range renames {
+		if !opts.Prompt {
+			log.Warnf("%v: looks like it was renamed to %v. Skipping...", rename.OldName, rename.NewName)
+			continue
+		}
+
+		var shouldRename bool
+		prompt := ui.NewConfirm().
+			WithTitle(fmt.Sprintf("Track %v as %v?", rename.OldName, rename.NewName)).
+			WithDescription(fmt.Sprintf("%v no longer exists, but it looks like it was renamed to %v", rename.OldName, rename.NewName)).
+			WithValue(&shouldRename)
+		if err := ui.Run(prompt); err != nil {
+			log.Warn("Skipping branch", "branch", rename.OldName, "error", err)
+			continue
+		}
+		if !shouldRename {
+			continue
+		}
+
+		if err := svc.RenameTrackedBranch(ctx, rename.OldName, rename.NewName); err != nil {
+			return fmt.Errorf("rename %v: %w", rename.OldName, err)
+		}
+		log.Infof("%v: renamed from %v", rename.NewName, rename.OldName)
+	}
+
+	return nil
 }
 
 func (cmd *repoSyncCmd) deleteMergedBranches(
@@ -247,13 +339,18 @@ func (cmd *repoSyncCmd) deleteMergedBranches(
 	// For the second, we need to find recently merged PRs with that branch
 	// name, and match the remote head SHA to the branch head SHA.
 	//
-	// We'll try to do these checks concurrently.
+	// We'll try to do these checks concurrently,
+	// bounded by the configured forge concurrency.
+	limit, err := loadForgeLimiter(ctx, repo)
+	if err != nil {
+		return err
+	}
 
 	submittedch := make(chan *submittedBranch)
 	trachedch := make(chan *trackedBranch)
 
 	var wg sync.WaitGroup
-	for range min(runtime.GOMAXPROCS(0), len(knownBranches)) {
+	for range min(limit.Size(), len(knownBranches)) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -272,7 +369,11 @@ func (cmd *repoSyncCmd) deleteMergedBranches(
 
 					// TODO: Once we're recording GraphQL IDs in the store,
 					// we can combine all submitted PRs into one query.
-					merged, err := remoteRepo.ChangeIsMerged(ctx, b.Change)
+					var merged bool
+					err := limit.Do(ctx, func() (err error) {
+						merged, err = remoteRepo.ChangeIsMerged(ctx, b.Change)
+						return err
+					})
 					if err != nil {
 						log.Error("Failed to query CR status", "change", b.Change, "error", err)
 						continue
@@ -286,9 +387,13 @@ func (cmd *repoSyncCmd) deleteMergedBranches(
 						continue
 					}
 
-					changes, err := remoteRepo.FindChangesByBranch(ctx, b.Name, forge.FindChangesOptions{
-						Limit: 3,
-						State: forge.ChangeMerged,
+					var changes []*forge.FindChangeItem
+					err := limit.Do(ctx, func() (err error) {
+						changes, err = remoteRepo.FindChangesByBranch(ctx, b.Name, forge.FindChangesOptions{
+							Limit: 3,
+							State: forge.ChangeMerged,
+						})
+						return err
 					})
 					if err != nil {
 						log.Error("Failed to list changes", "branch", b.Name, "error", err)
@@ -386,13 +491,63 @@ func (cmd *repoSyncCmd) deleteMergedBranches(
 		}
 	}
 
+	// Move branches based on merged branches
+	// onto the nearest base that wasn't merged.
+	// If several branches in a stack were merged,
+	// this moves their upstack only once
+	// instead of once for each deleted branch.
+	var restoreTo string // branch or commit to return to after moving
+	for _, b := range knownBranches {
+		if !slices.Contains(branchesToDelete, b.Base) ||
+			slices.Contains(branchesToDelete, b.Name) {
+			continue
+		}
+
+		base, err := svc.ResolveLiveBase(ctx, remoteRepo, b.Name)
+		if err != nil {
+			log.Warn("Could not find a base", "branch", b.Name, "error", err)
+			continue
+		}
+		if base == b.Base {
+			continue
+		}
+
+		// Moving branches checks them out.
+		if restoreTo == "" {
+			restoreTo, err = currentBranchOrHead(ctx, repo)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := (&upstackOntoCmd{
+			Branch: b.Name,
+			Onto:   base,
+		}).Run(ctx, log, opts); err != nil {
+			return svc.RebaseRescue(ctx, spice.RebaseRescueRequest{
+				Err:     err,
+				Command: []string{"repo", "sync"},
+				Branch:  b.Name,
+				Message: fmt.Sprintf("interrupted: %v: move onto %v", b.Name, base),
+			})
+		}
+	}
+	if restoreTo != "" {
+		if err := repo.Checkout(ctx, restoreTo); err != nil {
+			return fmt.Errorf("checkout %v: %w", restoreTo, err)
+		}
+	}
+
 	// TODO:
 	// Should the branches be deleted in any particular order?
 	// (e.g. from the bottom of the stack up)
 	for _, branch := range branchesToDelete {
+		// Merged branches are archived so that
+		// they can be restored if the merge is reverted.
 		err := (&branchDeleteCmd{
-			Branch: branch,
-			Force:  true,
+			Branch:  branch,
+			Force:   true,
+			archive: true,
 		}).Run(ctx, log, opts)
 		if err != nil {
 			return fmt.Errorf("delete branch %v: %w", branch, err)
@@ -415,3 +570,122 @@ func (cmd *repoSyncCmd) deleteMergedBranches(
 	// operation first.
 	return nil
 }
+
+// restackAll restacks all tracked branches for --restack-all.
+//
+// Unlike 'upstack restack', a conflict doesn't stop the operation:
+// the rebase is aborted, and the branches above the conflicting branch
+// are skipped while the remaining stacks are restacked.
+// Branches that were not restacked are reported at the end.
+func (cmd *repoSyncCmd) restackAll(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+) error {
+	// Rebases check out the branches they operate on,
+	// so remember what to return to.
+	restoreTo, err := currentBranchOrHead(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	trunk := store.Trunk()
+	upstacks, err := svc.ListUpstack(ctx, trunk)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+
+	// failed lists branches that had conflicts,
+	// and skipped maps all branches that weren't restacked to a reason,
+	// including the branches above failed branches.
+	var failed []string
+	skipped := make(map[string]string)
+	restacked := make(map[string]struct{}, len(upstacks))
+	include := func(branch string) bool {
+		_, ok := skipped[branch]
+		return !ok
+	}
+	for _, branch := range upstacks {
+		if branch == trunk {
+			continue
+		}
+		if _, ok := restacked[branch]; ok {
+			continue
+		}
+
+		b, err := svc.LookupBranch(ctx, branch)
+		if err != nil {
+			return fmt.Errorf("lookup %v: %w", branch, err)
+		}
+		if reason, ok := skipped[b.Base]; ok {
+			skipped[branch] = reason
+			continue
+		}
+
+		res, err := svc.RestackChain(ctx, branch, include)
+		if err != nil {
+			var rebaseErr *git.RebaseInterruptError
+			switch {
+			case errors.Is(err, spice.ErrAlreadyRestacked):
+				continue
+			case errors.As(err, &rebaseErr):
+				if err := repo.RebaseAbort(ctx); err != nil {
+					return fmt.Errorf("abort restack of %v: %w", branch, err)
+				}
+				log.Warnf("%v: conflict restacking on %v", branch, b.Base)
+				failed = append(failed, branch)
+				skipped[branch] = fmt.Sprintf("conflict restacking on %v", b.Base)
+				continue
+			default:
+				return fmt.Errorf("restack %v: %w", branch, err)
+			}
+		}
+
+		base := res.Base
+		for _, name := range res.Branches {
+			restacked[name] = struct{}{}
+			log.Infof("%v: restacked on %v", name, base)
+			base = name
+		}
+	}
+
+	if err := repo.Checkout(ctx, restoreTo); err != nil {
+		return fmt.Errorf("checkout %v: %w", restoreTo, err)
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	log.Errorf("Some branches could not be restacked:")
+	for _, branch := range upstacks {
+		if reason, ok := skipped[branch]; ok {
+			if !slices.Contains(failed, branch) {
+				reason = "above a branch that could not be restacked"
+			}
+			log.Errorf("  - %v: %v", branch, reason)
+		}
+	}
+	log.Errorf("Restack them with 'gs upstack restack --branch <name>'.")
+	return fmt.Errorf("%d branch(es) had conflicts", len(failed))
+}
+
+// currentBranchOrHead returns the name of the current branch,
+// or the hash of HEAD if it's detached.
+func currentBranchOrHead(ctx context.Context, repo *git.Repository) (string, error) {
+	name, err := repo.CurrentBranch(ctx)
+	if err == nil {
+		return name, nil
+	}
+	if !errors.Is(err, git.ErrDetachedHead) {
+		return "", fmt.Errorf("get current branch: %w", err)
+	}
+
+	head, err := repo.PeelToCommit(ctx, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("resolve HEAD: %w", err)
+	}
+	return head.String(), nil
+}
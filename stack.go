@@ -4,4 +4,6 @@ type stackCmd struct {
 	Submit  stackSubmitCmd  `cmd:"" aliases:"s" help:"Submit a stack"`
 	Restack stackRestackCmd `cmd:"" aliases:"r" help:"Restack a stack"`
 	Edit    stackEditCmd    `cmd:"" aliases:"e" help:"Edit the order of branches in a stack"`
+	Fold    stackFoldCmd    `cmd:"" aliases:"f" help:"Fold all branches in a stack into the bottom-most branch"`
+	Export  stackExportCmd  `cmd:"" help:"Write the stack's branch graph as JSON or DOT"`
 }
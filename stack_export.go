@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type stackExportCmd struct {
+	Format string `enum:"json,dot" default:"json" placeholder:"FORMAT" help:"Output format: json or dot"`
+}
+
+func (*stackExportCmd) Help() string {
+	return text.Dedent(`
+		Writes the branches in the current stack
+		and the relationships between them to stdout.
+		Run this from the trunk to export all tracked branches.
+
+		Use --format=json (the default) for a JSON document
+		listing each branch with its base and Change Request, if any.
+		Use --format=dot for a Graphviz graph
+		with an edge from each base to the branches above it.
+	`)
+}
+
+// stackExportBranch is a branch in the output of 'stack export'.
+type stackExportBranch struct {
+	Name      string `json:"name"`
+	Base      string `json:"base"`
+	Change    string `json:"change,omitempty"`
+	ChangeURL string `json:"changeURL,omitempty"`
+}
+
+// stackExport is the output of 'stack export --format=json'.
+type stackExport struct {
+	Trunk    string              `json:"trunk"`
+	Branches []stackExportBranch `json:"branches"`
+}
+
+func (cmd *stackExportCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
+	repo, store, svc, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	currentBranch, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("get current branch: %w", err)
+	}
+
+	stack, err := svc.ListStack(ctx, currentBranch)
+	if err != nil {
+		return fmt.Errorf("list stack: %w", err)
+	}
+
+	allBranches, err := svc.LoadBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("load branches: %w", err)
+	}
+	branchesByName := make(map[string]spice.LoadBranchItem, len(allBranches))
+	for _, b := range allBranches {
+		branchesByName[b.Name] = b
+	}
+
+	trunk := store.Trunk()
+	export := stackExport{
+		Trunk:    trunk,
+		Branches: make([]stackExportBranch, 0, len(stack)),
+	}
+	for _, name := range stack {
+		b, ok := branchesByName[name]
+		if !ok {
+			continue // trunk
+		}
+
+		eb := stackExportBranch{
+			Name: b.Name,
+			Base: b.Base,
+		}
+		if b.Change != nil {
+			eb.Change = b.Change.ChangeID().String()
+			eb.ChangeURL = b.ChangeURL
+		}
+		export.Branches = append(export.Branches, eb)
+	}
+
+	switch cmd.Format {
+	case "dot":
+		return writeStackDOT(os.Stdout, &export)
+	default:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(export)
+	}
+}
+
+// writeStackDOT writes the exported stack as a Graphviz digraph
+// with edges from each base to the branches above it.
+func writeStackDOT(w io.Writer, export *stackExport) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "digraph stack {")
+	_, _ = fmt.Fprintln(bw, "\trankdir=BT;")
+	_, _ = fmt.Fprintf(bw, "\t%q [shape=box];\n", export.Trunk)
+
+	for _, b := range export.Branches {
+		label := b.Name
+		if b.Change != "" {
+			label += "\n" + b.Change
+		}
+		attrs := []string{fmt.Sprintf("label=%q", label)}
+		if b.ChangeURL != "" {
+			attrs = append(attrs, fmt.Sprintf("URL=%q", b.ChangeURL))
+		}
+		_, _ = fmt.Fprintf(bw, "\t%q [%v];\n", b.Name, strings.Join(attrs, ", "))
+	}
+
+	for _, b := range export.Branches {
+		_, _ = fmt.Fprintf(bw, "\t%q -> %q;\n", b.Base, b.Name)
+	}
+
+	_, _ = fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
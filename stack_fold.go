@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+type stackFoldCmd struct{}
+
+func (*stackFoldCmd) Help() string {
+	return text.Dedent(`
+		All branches in the current stack are folded
+		into the bottom-most branch of the stack,
+		leaving a single branch with all their commits.
+		The other branches are deleted.
+
+		The stack must be linear: each branch may have at most
+		one branch above it.
+		All branches in the stack must be restacked first.
+
+		A prompt will ask to close the open Change Requests
+		of the branches that were folded away.
+		Without prompts, they are left open.
+	`)
+}
+
+func (cmd *stackFoldCmd) Run(
+	ctx context.Context,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+) error {
+	repo, store, svc, err := openRepo(ctx, log, opts)
+	if err != nil {
+		return err
+	}
+
+	currentBranch, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("get current branch: %w", err)
+	}
+	if currentBranch == store.Trunk() {
+		return errors.New("cannot fold a stack from trunk: check out a branch in the stack")
+	}
+
+	stack, err := svc.ListStackLinear(ctx, currentBranch)
+	if err != nil {
+		var nonLinearErr *spice.NonLinearStackError
+		if errors.As(err, &nonLinearErr) {
+			log.Errorf("%v has %d branches above it: %v",
+				nonLinearErr.Branch, len(nonLinearErr.Aboves),
+				strings.Join(nonLinearErr.Aboves, ", "))
+			return errors.New("only linear stacks can be folded")
+		}
+		return fmt.Errorf("list stack: %w", err)
+	}
+	if len(stack) < 2 {
+		log.Infof("%v: stack has only one branch. Nothing to fold.", currentBranch)
+		return nil
+	}
+
+	if err := validateStack(ctx, log, svc, stack); err != nil {
+		return fmt.Errorf("cannot fold stack: %w", err)
+	}
+
+	bottom, top := stack[0], stack[len(stack)-1]
+	folded := stack[1:]
+
+	// Change Requests of the branches that are folded away.
+	type foldedChange struct {
+		Branch string
+		ID     forge.ChangeID
+	}
+	var changes []foldedChange
+	for _, name := range folded {
+		b, err := svc.LookupBranch(ctx, name)
+		if err != nil {
+			return fmt.Errorf("lookup %v: %w", name, err)
+		}
+		if b.Change != nil {
+			changes = append(changes, foldedChange{
+				Branch: name,
+				ID:     b.Change.ChangeID(),
+			})
+		}
+	}
+
+	var closeChanges bool
+	if len(changes) > 0 && opts.Prompt {
+		ids := make([]string, len(changes))
+		for i, c := range changes {
+			ids[i] = c.ID.String()
+		}
+
+		closeChanges = true
+		prompt := ui.NewConfirm().
+			WithTitlef("Close %d CR(s) of folded branches?", len(changes)).
+			WithDescriptionf("The following CRs will be closed: %v", strings.Join(ids, ", ")).
+			WithValue(&closeChanges)
+		if err := ui.Run(prompt); err != nil {
+			return fmt.Errorf("run prompt: %w", err)
+		}
+	}
+
+	// Connect to the forge before changing anything
+	// so that we don't stop halfway if that fails.
+	var remoteRepo forge.Repository
+	if closeChanges {
+		remote, err := store.Remote()
+		if err != nil {
+			return fmt.Errorf("get remote: %w", err)
+		}
+
+		remoteRepo, err = openRemoteRepository(ctx, log, secretStash, repo, remote, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	bottomHash, err := repo.PeelToCommit(ctx, bottom)
+	if err != nil {
+		return fmt.Errorf("resolve %v: %w", bottom, err)
+	}
+	topHash, err := repo.PeelToCommit(ctx, top)
+	if err != nil {
+		return fmt.Errorf("resolve %v: %w", top, err)
+	}
+
+	// Fast-forward the bottom branch to the top of the stack.
+	// The bottom branch may be checked out,
+	// so detach to the top first to keep the working tree in sync.
+	if err := repo.DetachHead(ctx, top); err != nil {
+		return fmt.Errorf("detach head: %w", err)
+	}
+	if err := repo.SetRef(ctx, git.SetRefRequest{
+		Ref:     "refs/heads/" + bottom,
+		Hash:    topHash,
+		OldHash: bottomHash,
+	}); err != nil {
+		return fmt.Errorf("update %v: %w", bottom, err)
+	}
+	if err := repo.Checkout(ctx, bottom); err != nil {
+		return fmt.Errorf("checkout %v: %w", bottom, err)
+	}
+
+	// Branches outside the stack that are based on folded branches
+	// are moved onto the bottom branch.
+	// Their base hashes are left alone so they can be restacked.
+	var upserts []state.UpsertRequest
+	for _, name := range folded {
+		aboves, err := svc.ListAbove(ctx, name)
+		if err != nil {
+			return fmt.Errorf("list branches above %v: %w", name, err)
+		}
+
+		for _, above := range aboves {
+			if slices.Contains(stack, above) {
+				continue
+			}
+			upserts = append(upserts, state.UpsertRequest{
+				Name: above,
+				Base: bottom,
+			})
+		}
+	}
+
+	if err := store.UpdateBranch(ctx, &state.UpdateRequest{
+		Upserts: upserts,
+		Deletes: folded,
+		Message: fmt.Sprintf("fold stack into %v", bottom),
+	}); err != nil {
+		return fmt.Errorf("update state: %w", err)
+	}
+
+	for _, name := range folded {
+		if err := repo.DeleteBranch(ctx, name, git.BranchDeleteOptions{
+			Force: true, // we know it's merged
+		}); err != nil {
+			return fmt.Errorf("delete branch %v: %w", name, err)
+		}
+	}
+	log.Infof("Folded %d branch(es) into %v", len(folded), bottom)
+
+	for _, c := range changes {
+		if !closeChanges {
+			log.Warnf("%v: CR %v was left open", c.Branch, c.ID)
+			continue
+		}
+
+		change, err := remoteRepo.FindChangeByID(ctx, c.ID)
+		if err != nil {
+			log.Warn("Could not look up CR", "change", c.ID, "error", err)
+			continue
+		}
+		if change.State != forge.ChangeOpen {
+			continue
+		}
+
+		if err := remoteRepo.CloseChange(ctx, c.ID); err != nil {
+			log.Warn("Could not close CR", "change", c.ID, "error", err)
+			continue
+		}
+		log.Infof("%v: closed CR %v", c.Branch, c.ID)
+	}
+
+	return nil
+}
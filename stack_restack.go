@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
@@ -11,16 +12,24 @@ import (
 	"go.abhg.dev/gs/internal/text"
 )
 
-type stackRestackCmd struct{}
+type stackRestackCmd struct {
+	UpstackOnly   bool `name:"upstack-only" xor:"scope" help:"Only restack the current branch and the branches above it"`
+	DownstackOnly bool `name:"downstack-only" xor:"scope" help:"Only restack the current branch and the branches below it"`
+}
 
 func (*stackRestackCmd) Help() string {
 	return text.Dedent(`
 		All branches in the current stack are rebased on top of their
 		respective bases, ensuring a linear history.
+
+		Use --upstack-only to restack only the current branch
+		and the branches above it,
+		or --downstack-only to restack only the current branch
+		and the branches below it.
 	`)
 }
 
-func (*stackRestackCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
+func (cmd *stackRestackCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
 	repo, store, svc, err := openRepo(ctx, log, opts)
 	if err != nil {
 		return err
@@ -36,14 +45,57 @@ func (*stackRestackCmd) Run(ctx context.Context, log *log.Logger, opts *globalOp
 		return fmt.Errorf("list stack: %w", err)
 	}
 
+	// To restack in a direction only,
+	// filter the stack to preserve its ordering.
+	var (
+		scope   []string
+		command = []string{"stack", "restack"}
+	)
+	switch {
+	case cmd.UpstackOnly:
+		scope, err = svc.ListUpstack(ctx, currentBranch)
+		if err != nil {
+			return fmt.Errorf("list upstack: %w", err)
+		}
+		command = append(command, "--upstack-only")
+
+	case cmd.DownstackOnly:
+		scope, err = svc.ListDownstack(ctx, currentBranch)
+		if err != nil {
+			return fmt.Errorf("list downstack: %w", err)
+		}
+		command = append(command, "--downstack-only")
+	}
+	if cmd.UpstackOnly || cmd.DownstackOnly {
+		inScope := make(map[string]struct{}, len(scope))
+		for _, branch := range scope {
+			inScope[branch] = struct{}{}
+		}
+
+		stack = slices.DeleteFunc(stack, func(branch string) bool {
+			_, ok := inScope[branch]
+			return !ok
+		})
+	}
+
+	// Branches in the list that have already been restacked
+	// as part of a chain with a branch below them.
+	restacked := make(map[string]struct{}, len(stack))
+	inList := func(branch string) bool {
+		return slices.Contains(stack, branch)
+	}
+
 loop:
 	for _, branch := range stack {
 		// Trunk never needs to be restacked.
 		if branch == store.Trunk() {
 			continue loop
 		}
+		if _, ok := restacked[branch]; ok {
+			continue loop
+		}
 
-		res, err := svc.Restack(ctx, branch)
+		res, err := svc.RestackChain(ctx, branch, inList)
 		if err != nil {
 			var rebaseErr *git.RebaseInterruptError
 			switch {
@@ -52,7 +104,7 @@ loop:
 				// we'll resume by re-running this command.
 				return svc.RebaseRescue(ctx, spice.RebaseRescueRequest{
 					Err:     rebaseErr,
-					Command: []string{"stack", "restack"},
+					Command: command,
 					Branch:  currentBranch,
 					Message: fmt.Sprintf("interrupted: restack stack for %s", branch),
 				})
@@ -68,7 +120,12 @@ loop:
 			}
 		}
 
-		log.Infof("%v: restacked on %v", branch, res.Base)
+		base := res.Base
+		for _, name := range res.Branches {
+			restacked[name] = struct{}{}
+			log.Infof("%v: restacked on %v", name, base)
+			base = name
+		}
 	}
 
 	// On success, check out the original branch.
@@ -1,22 +1,47 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"sync"
 
 	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
 	"go.abhg.dev/gs/internal/text"
 )
 
 type stackSubmitCmd struct {
 	submitOptions
+
+	Atomic      bool `negatable:"" default:"true" help:"Push all branches in the stack at once, updating all or none of them"`
+	Concurrency int  `placeholder:"N" help:"Submit up to N branches at the same time after an atomic push. Defaults to 'git config spice.forge.concurrency' or 4."`
 }
 
 func (*stackSubmitCmd) Help() string {
 	return text.Dedent(`
 		Change Requests are created or updated
 		for all branches in the current stack.
+
+		Branches that need to be pushed are pushed together
+		in a single atomic push before Change Requests are updated.
+		Either all of them are updated on the remote, or none are.
+		Use --no-atomic to push each branch separately.
+		With --verify-ci-green, branches are always pushed separately
+		so that each push can be checked first.
+
+		After an atomic push, Change Requests are created and updated
+		for up to 4 branches at the same time
+		if no prompts are needed (e.g. with --fill).
+		Use --concurrency to change this,
+		or --concurrency=1 to submit branches one at a time.
 	`) + "\n" + _submitHelp
 }
 
@@ -44,31 +69,278 @@ func (cmd *stackSubmitCmd) Run(
 	// TODO: generalize into a service-level method
 	// TODO: separate preparation of the stack from submission
 
+	if cmd.Concurrency < 0 {
+		return errors.New("--concurrency must not be negative")
+	}
+
 	var session submitSession
-	for _, branch := range stack {
-		if branch == store.Trunk() {
-			continue
-		}
+	if cmd.Concurrency > 0 {
+		// --concurrency also bounds the forge calls
+		// made for stack comments.
+		_, _ = session.forgeLimit.Get(func() (*forgeLimiter, error) {
+			return newForgeLimiter(cmd.Concurrency), nil
+		})
+	}
+	limit, err := session.limiter(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	branches := slices.DeleteFunc(slices.Clone(stack), func(name string) bool {
+		return name == store.Trunk()
+	})
 
-		err := (&branchSubmitCmd{
-			submitOptions: cmd.submitOptions,
-			Branch:        branch,
-		}).run(ctx, &session, repo, store, svc, secretStash, log, opts)
+	// Uncommitted changes stop the whole submit,
+	// so check for them before pushing anything.
+	if err := cmd.verifyWorktree(ctx, repo, log, branches); err != nil {
+		return err
+	}
+
+	// --verify-ci-green may refuse to push any one branch,
+	// so the branches can't be pushed all at once.
+	var pushed bool // whether all branches are already on the remote
+	if cmd.Atomic && !cmd.VerifyCIGreen && !cmd.DryRun && !cmd.UpdateBaseOnly {
+		pushed, err = cmd.pushAtomic(ctx, &session, repo, store, svc, secretStash, log, opts, stack)
 		if err != nil {
-			return fmt.Errorf("submit %v: %w", branch, err)
+			return err
 		}
 	}
 
-	if cmd.DryRun {
-		return nil
+	// Branches can be submitted concurrently
+	// only if they don't need to be pushed in order
+	// and we won't prompt for information about them.
+	if pushed && limit.Size() > 1 && len(branches) > 1 && (cmd.Fill || !opts.Prompt) {
+		err = cmd.submitConcurrently(ctx, &session, limit, repo, store, svc, secretStash, log, opts, branches)
+	} else {
+		for _, branch := range branches {
+			if err = cmd.submitBranch(ctx, &session, repo, store, svc, secretStash, log, opts, branch); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	commentOpts, err := cmd.stackCommentOptions(ctx, repo)
+	if err != nil {
+		return err
 	}
 
-	return syncStackComments(
+	if err := syncStackComments(
 		ctx,
 		store,
 		svc,
 		session.remoteRepo.Require(),
+		limit,
 		log,
 		session.branches,
+		commentOpts,
+	); err != nil {
+		return err
+	}
+
+	return cmd.checkPending(&session)
+}
+
+// submitBranch submits a single branch in the stack.
+func (cmd *stackSubmitCmd) submitBranch(
+	ctx context.Context,
+	session *submitSession,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+	branch string,
+) error {
+	err := cmd.branchCmd(branch).run(ctx, session, repo, store, svc, secretStash, log, opts)
+	if err != nil {
+		return fmt.Errorf("submit %v: %w", branch, err)
+	}
+	return nil
+}
+
+// branchCmd returns the branch submit command
+// used to submit the given branch in the stack.
+func (cmd *stackSubmitCmd) branchCmd(branch string) *branchSubmitCmd {
+	return &branchSubmitCmd{
+		submitOptions: cmd.submitOptions,
+		Branch:        branch,
+	}
+}
+
+// submitConcurrently submits the given branches,
+// up to limit.Size() of them at a time.
+//
+// All branches must already be pushed to the remote
+// so that every CR's base exists when the CR is created.
+// Failure to submit one branch does not stop the others.
+func (cmd *stackSubmitCmd) submitConcurrently(
+	ctx context.Context,
+	session *submitSession,
+	limit *forgeLimiter,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+	branches []string,
+) error {
+	// Open the remote repository before starting
+	// so that a failure to do so is reported only once.
+	_, err := session.remoteRepo.Get(func() (forge.Repository, error) {
+		return openRemoteRepository(ctx, log, secretStash, repo, session.remote.Require(), cmd.Forge)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Submitting %d branches, up to %d at a time", len(branches), limit.Size())
+	session.publishQueue = newPublishQueue(branches)
+	defer func() { session.publishQueue = nil }()
+
+	branchc := make(chan int)
+	errs := make([]error, len(branches))
+	var wg sync.WaitGroup
+	for range min(limit.Size(), len(branches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for idx := range branchc {
+				errs[idx] = limit.Do(ctx, func() error {
+					// Branches that don't create a CR,
+					// or fail before doing so,
+					// must not hold up the ones after them.
+					defer session.publishQueue.Done(branches[idx])
+
+					return cmd.submitBranch(ctx, session, repo, store, svc, secretStash, log, opts, branches[idx])
+				})
+			}
+		}()
+	}
+	for idx := range branches {
+		branchc <- idx
+	}
+	close(branchc)
+	wg.Wait()
+
+	// Keep the submitted branches in stack order
+	// regardless of the order in which they finished.
+	slices.SortStableFunc(session.branches, func(a, b string) int {
+		return cmp.Compare(slices.Index(branches, a), slices.Index(branches, b))
+	})
+
+	return errors.Join(errs...)
+}
+
+// _discardLog is used for checks whose failures
+// will be reported again later.
+var _discardLog = log.New(io.Discard)
+
+// pushAtomic pushes all out-of-date branches in the stack
+// with a single atomic push.
+// It reports whether all branches in the stack
+// are up-to-date on the remote afterwards.
+//
+// Branches pushed here are no-ops for the push step of branch submit.
+// If any branch in the stack can't be pushed as part of the submit,
+// or fails a check that branch submit runs before pushing,
+// nothing is pushed here,
+// leaving it to branch submit to report the problem.
+func (cmd *stackSubmitCmd) pushAtomic(
+	ctx context.Context,
+	session *submitSession,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	secretStash secret.Stash,
+	log *log.Logger,
+	opts *globalOptions,
+	stack []string,
+) (bool, error) {
+	var (
+		refspecs []git.Refspec
+		leases   []string
+		heads    = make(map[string]git.Hash)
+		oldHeads = make(map[string]git.Hash)
 	)
+
+	remote, remoteRepo, err := cmd.branchCmd("").remoteRepository(ctx, session, repo, store, secretStash, log, opts)
+	if err != nil {
+		return false, err
+	}
+	if _, _, err := cmd.branchCmd("").verifyRemote(ctx, session, remoteRepo); err != nil {
+		log.Debugf("Not pushing atomically: %v", err)
+		return false, nil
+	}
+
+	for _, name := range stack {
+		if name == store.Trunk() {
+			continue
+		}
+
+		if !cmd.Force {
+			if err := svc.VerifyRestacked(ctx, name); err != nil {
+				return false, nil
+			}
+		}
+
+		branch, err := svc.LookupBranch(ctx, name)
+		if err != nil {
+			return false, nil
+		}
+
+		if err := cmd.branchCmd(name).verifyCommits(ctx, _discardLog, repo, branch); err != nil {
+			log.Debugf("Not pushing atomically: %v: %v", name, err)
+			return false, nil
+		}
+
+		upstreamBranch := name
+		if branch.UpstreamBranch != "" {
+			upstreamBranch = branch.UpstreamBranch
+		}
+
+		existingHash, err := repo.PeelToCommit(ctx, remote+"/"+upstreamBranch)
+		if err == nil && existingHash == branch.Head {
+			continue // already up-to-date
+		}
+
+		heads[name] = branch.Head
+		refspecs = append(refspecs, git.Refspec(branch.Head.String()+":refs/heads/"+upstreamBranch))
+		if err == nil {
+			oldHeads[name] = existingHash
+			if !cmd.Force {
+				leases = append(leases, upstreamBranch+":"+existingHash.String())
+			}
+		}
+	}
+
+	// A single branch will be pushed by branch submit.
+	switch len(refspecs) {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	}
+
+	_, err = repo.Push(ctx, git.PushOptions{
+		Remote:          remote,
+		Refspecs:        refspecs,
+		ForceWithLeases: leases,
+		Force:           cmd.Force,
+		Atomic:          true,
+	})
+	if err != nil {
+		log.Error("Push failed. No branches were updated. Branches may have been updated by someone else. Try with --force.")
+		return false, fmt.Errorf("push branches: %w", err)
+	}
+
+	log.Debugf("Pushed %d branches", len(refspecs))
+	session.pushed = heads
+	session.pushedFrom = oldHeads
+	return true, nil
 }
@@ -3,12 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"strings"
 	"sync"
 
 	"github.com/charmbracelet/log"
+	"github.com/rogpeppe/go-internal/diff"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
@@ -21,36 +22,192 @@ import (
 //
 // The zero value of this type is a valid empty session.
 type submitSession struct {
+	// mu guards the fields below that are modified
+	// while branches are submitted concurrently:
+	// branches and pending.
+	// It's also held while writing to the Git configuration,
+	// which can't be done concurrently.
+	mu sync.Mutex
+
 	// Branches that have been submitted (created or updated)
-	// in this session.
+	// in this session,
+	// or that would have been if this is a dry run.
 	branches []string
 
+	// Branches that would have been submitted
+	// if this wasn't a dry run.
+	pending []string
+
+	// Branches that were already pushed in this session,
+	// mapped to the commit that was pushed for them.
+	pushed map[string]git.Hash
+
+	// For branches in pushed,
+	// the commit that the branch was at on the remote
+	// before it was pushed, if it existed.
+	pushedFrom map[string]git.Hash
+
+	// If set, new CRs are created in the order of this queue.
+	// This is used when branches are submitted concurrently.
+	publishQueue *publishQueue
+
 	// Values that are memoized across multiple branch submits.
 	remote     memoizedValue[string]
 	remoteRepo memoizedValue[forge.Repository]
+	forgeLimit memoizedValue[*forgeLimiter]
+
+	// User authenticated against the forge.
+	// Use currentUser to access this.
+	user memoizedValue[forge.User]
+
+	// Change templates of the remote repository.
+	// Use changeTemplates to access these.
+	templates memoizedValue[[]*forge.ChangeTemplate]
+}
+
+// addBranch records that a branch was submitted in this session.
+func (s *submitSession) addBranch(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.branches = append(s.branches, name)
+}
+
+// addPending records that a branch would have been submitted
+// if this wasn't a dry run.
+func (s *submitSession) addPending(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, name)
+}
+
+// publishQueue makes branches submitted concurrently
+// create their CRs in a fixed order,
+// so that CR numbers increase up the stack
+// as they would if the branches were submitted one at a time.
+//
+// A nil publishQueue imposes no order.
+type publishQueue struct {
+	idx  map[string]int
+	done []chan struct{}
+	once []sync.Once
+}
+
+func newPublishQueue(branches []string) *publishQueue {
+	q := &publishQueue{
+		idx:  make(map[string]int, len(branches)),
+		done: make([]chan struct{}, len(branches)),
+		once: make([]sync.Once, len(branches)),
+	}
+	for i, name := range branches {
+		q.idx[name] = i
+		q.done[i] = make(chan struct{})
+	}
+	return q
+}
+
+// Wait blocks until all branches before the given branch
+// have created their CRs or no longer need to.
+func (q *publishQueue) Wait(ctx context.Context, branch string) error {
+	if q == nil {
+		return nil
+	}
+
+	i, ok := q.idx[branch]
+	if !ok {
+		return nil
+	}
+
+	for _, done := range q.done[:i] {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Done reports that the given branch has created its CR
+// or no longer needs to.
+// It's safe to call Done multiple times for the same branch.
+func (q *publishQueue) Done(branch string) {
+	if q == nil {
+		return
+	}
+
+	if i, ok := q.idx[branch]; ok {
+		q.once[i].Do(func() { close(q.done[i]) })
+	}
+}
+
+// currentUser returns the user authenticated against the forge.
+// The user is looked up only once per session.
+func (s *submitSession) currentUser(ctx context.Context, remoteRepo forge.Repository) (forge.User, error) {
+	return s.user.Get(func() (forge.User, error) {
+		return remoteRepo.CurrentUser(ctx)
+	})
+}
+
+// changeTemplates returns the change templates of the remote repository.
+// All branches in a session are submitted to the same repository,
+// so the templates are listed only once per session.
+// Concurrent callers wait for the same listing.
+func (s *submitSession) changeTemplates(
+	ctx context.Context,
+	svc *spice.Service,
+	remoteRepo forge.Repository,
+) ([]*forge.ChangeTemplate, error) {
+	return s.templates.Get(func() ([]*forge.ChangeTemplate, error) {
+		return svc.ListChangeTemplates(ctx, remoteRepo)
+	})
+}
+
+// limiter returns the forgeLimiter shared by
+// all concurrent forge operations in this session.
+func (s *submitSession) limiter(ctx context.Context, repo *git.Repository) (*forgeLimiter, error) {
+	return s.forgeLimit.Get(func() (*forgeLimiter, error) {
+		return loadForgeLimiter(ctx, repo)
+	})
 }
 
+// memoizedValue holds a value that is computed once
+// and shared by all callers.
+//
+// Only successful results are remembered.
+// If the computation fails, the error is returned to that caller
+// and the next call to Get tries again.
+//
 // This whole type is a bit of a hack.
 // We should have better plumbing and retention of information
 // between the submits.
 // Maybe newSubmitSession should handle opening remote repo.
 type memoizedValue[A any] struct {
-	once  sync.Once
+	mu    sync.Mutex
 	done  bool
 	value A
 }
 
 func (m *memoizedValue[A]) Require() A {
-	must.Bef(m.done, "memoized value not set: Require called without Get")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	must.Bef(m.done, "memoized value not set: Require called without a successful Get")
 	return m.value
 }
 
-func (m *memoizedValue[A]) Get(f func() (A, error)) (_ A, err error) {
-	m.once.Do(func() {
-		m.value, err = f()
-		m.done = true
-	})
-	return m.value, err
+func (m *memoizedValue[A]) Get(f func() (A, error)) (A, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.done {
+		value, err := f()
+		if err != nil {
+			var zero A
+			return zero, err
+		}
+		m.value, m.done = value, true
+	}
+	return m.value, nil
 }
 
 // For each branch in the list of submitted branches,
@@ -65,15 +222,28 @@ func (m *memoizedValue[A]) Get(f func() (A, error)) (_ A, err error) {
 //	<sub>Change managed by [git-spice](https://abhinav.github.io/git-spice/).</sub>
 //
 // Where the arrow indicates the current branch.
+// If collapse is set, the list is placed inside a collapsible
+// <details> block with the header as its summary.
 // For cases where this is the first time we're posting the comment,
 // we'll need to also update the store to record the comment ID for later.
+//
+// If the target is not "all", new comments are posted only on
+// the top-most or bottom-most CRs of the stack.
+// CRs that already have a comment continue to get it updated
+// so that it doesn't go stale if the target changes between runs.
+//
+// In dry-run mode, nothing is posted or updated.
+// Instead, the comments that would change are logged
+// with a diff of their contents.
 func syncStackComments(
 	ctx context.Context,
 	store *state.Store,
 	svc *spice.Service,
 	remoteRepo forge.Repository,
+	limit *forgeLimiter,
 	log *log.Logger,
 	submittedBranches []string,
+	opts stackCommentOptions,
 ) error {
 	// Look up branch graph once, and share between all syncs.
 	trackedBranches, err := svc.LoadBranches(ctx)
@@ -128,6 +298,26 @@ func syncStackComments(
 		base.Aboves = append(base.Aboves, nodeIdx)
 	}
 
+	if opts.DryRun {
+		for _, branch := range submittedBranches {
+			idx, ok := idxByBranch[branch]
+			if !ok {
+				// Branches that would get a new CR
+				// aren't associated with one yet.
+				continue
+			}
+
+			if !opts.Target.includes(nodes[idx]) {
+				continue
+			}
+
+			info := infos[idx]
+			commentBody := generateStackComment(nodes, idx, opts.Collapse)
+			previewStackComment(ctx, log, remoteRepo, info.Meta, commentBody)
+		}
+		return nil
+	}
+
 	type (
 		postComment struct {
 			Branch string
@@ -151,7 +341,7 @@ func syncStackComments(
 		mu      sync.Mutex // guards upserts
 		upserts []state.UpsertRequest
 	)
-	for range min(runtime.GOMAXPROCS(0), len(submittedBranches)) {
+	for range min(limit.Size(), len(submittedBranches)) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -166,7 +356,11 @@ func syncStackComments(
 						continue
 					}
 
-					commentID, err := remoteRepo.PostChangeComment(ctx, post.Change, post.Body)
+					var commentID forge.ChangeCommentID
+					err := limit.Do(ctx, func() (err error) {
+						commentID, err = remoteRepo.PostChangeComment(ctx, post.Change, post.Body)
+						return err
+					})
 					if err != nil {
 						log.Warn("Error posting comment",
 							"change", post.Change.String(),
@@ -200,7 +394,9 @@ func syncStackComments(
 						continue
 					}
 
-					err := remoteRepo.UpdateChangeComment(ctx, update.Comment, update.Body)
+					err := limit.Do(ctx, func() error {
+						return remoteRepo.UpdateChangeComment(ctx, update.Comment, update.Body)
+					})
 					if err != nil {
 						log.Warn("Error updating comment",
 							"change", update.Change.String(),
@@ -222,8 +418,14 @@ func syncStackComments(
 			continue
 		}
 
+		// Comments on CRs that aren't targeted are left alone,
+		// even if they were posted with a different target.
+		if !opts.Target.includes(nodes[idx]) {
+			continue
+		}
+
 		info := infos[idx]
-		commentBody := generateStackComment(nodes, idx)
+		commentBody := generateStackComment(nodes, idx, opts.Collapse)
 		if info.Meta.StackCommentID() == nil {
 			postc <- &postComment{
 				Branch: branch,
@@ -264,6 +466,102 @@ func syncStackComments(
 	return nil
 }
 
+// stackCommentOptions configures the stack comments
+// posted by syncStackComments.
+// previewStackComment logs that a stack comment would be posted or updated
+// on a CR with the given body, and how the body would change.
+func previewStackComment(
+	ctx context.Context,
+	log *log.Logger,
+	remoteRepo forge.Repository,
+	meta forge.ChangeMetadata,
+	body string,
+) {
+	changeID := meta.ChangeID()
+
+	var oldBody string
+	if commentID := meta.StackCommentID(); commentID == nil {
+		log.Infof("WOULD post stack comment on %v:", changeID)
+	} else {
+		var err error
+		oldBody, err = remoteRepo.ChangeComment(ctx, commentID)
+		if err != nil {
+			log.Warn("Could not get stack comment", "change", changeID.String(), "error", err)
+			log.Infof("WOULD update stack comment on %v", changeID)
+			return
+		}
+
+		if oldBody == body {
+			log.Debugf("Stack comment on %v is up to date", changeID)
+			return
+		}
+		log.Infof("WOULD update stack comment on %v:", changeID)
+	}
+
+	d := diff.Diff("old", []byte(oldBody), "new", []byte(body))
+	for _, line := range strings.Split(strings.TrimSuffix(string(d), "\n"), "\n") {
+		// Skip the file headers.
+		if strings.HasPrefix(line, "diff ") ||
+			strings.HasPrefix(line, "--- ") ||
+			strings.HasPrefix(line, "+++ ") {
+			continue
+		}
+		log.Info(strings.TrimRight("  "+line, " "))
+	}
+}
+
+type stackCommentOptions struct {
+	// Collapse places the stack inside a collapsible block.
+	Collapse bool
+
+	// Target specifies which CRs get stack comments.
+	// Comments on other CRs are neither posted nor updated.
+	Target stackCommentTarget
+
+	// DryRun reports the comments that would be posted or updated
+	// instead of changing them.
+	DryRun bool
+}
+
+// stackCommentTarget specifies which CRs in a stack
+// get a stack comment.
+type stackCommentTarget string
+
+const (
+	// stackCommentAll posts the comment on all CRs.
+	stackCommentAll stackCommentTarget = "all"
+
+	// stackCommentTop posts the comment only on CRs
+	// that don't have other CRs above them.
+	stackCommentTop stackCommentTarget = "top"
+
+	// stackCommentBottom posts the comment only on CRs
+	// that aren't based on other CRs.
+	stackCommentBottom stackCommentTarget = "bottom"
+)
+
+// parseStackCommentTarget parses a stackCommentTarget from a string.
+func parseStackCommentTarget(s string) (stackCommentTarget, error) {
+	switch t := stackCommentTarget(s); t {
+	case stackCommentAll, stackCommentTop, stackCommentBottom:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown stack comment target %q: expected all, top, or bottom", s)
+	}
+}
+
+// includes reports whether a CR should get a stack comment.
+func (t stackCommentTarget) includes(c *stackedChange) bool {
+	switch t {
+	case stackCommentTop:
+		return len(c.Aboves) == 0
+	case stackCommentBottom:
+		return c.Base == -1
+	default:
+		return true
+	}
+}
+
 type stackedChange struct {
 	Change forge.ChangeID
 
@@ -274,14 +572,22 @@ type stackedChange struct {
 const (
 	_commentHeader = "This change is part of the following stack:\n\n"
 	_commentFooter = "\n<sub>Change managed by [git-spice](https://abhinav.github.io/git-spice/).</sub>\n"
+
+	_collapsedCommentHeader = "<details>\n<summary>This change is part of the following stack:</summary>\n\n"
+	_collapsedCommentFooter = "\n</details>\n"
 )
 
 func generateStackComment(
 	nodes []*stackedChange,
 	current int,
+	collapse bool,
 ) string {
 	var sb strings.Builder
-	sb.WriteString(_commentHeader)
+	if collapse {
+		sb.WriteString(_collapsedCommentHeader)
+	} else {
+		sb.WriteString(_commentHeader)
+	}
 	write := func(nodeIdx, indent int) {
 		node := nodes[nodeIdx]
 		for range indent {
@@ -339,6 +645,9 @@ func generateStackComment(
 
 	// Current branch and its upstacks.
 	visit(current, indent)
+	if collapse {
+		sb.WriteString(_collapsedCommentFooter)
+	}
 	sb.WriteString(_commentFooter)
 	return sb.String()
 }
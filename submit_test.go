@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/logtest"
 )
 
 func TestGenerateStackComment(t *testing.T) {
 	tests := []struct {
-		name    string
-		graph   []*stackedChange
-		current int
-		want    string
+		name     string
+		graph    []*stackedChange
+		current  int
+		collapse bool
+		want     string
 	}{
 		{
 			name: "Single",
@@ -88,6 +95,19 @@ func TestGenerateStackComment(t *testing.T) {
 				"        - #125",
 			),
 		},
+		{
+			name: "Collapse",
+			graph: []*stackedChange{
+				{Change: _changeID("123"), Base: -1},
+				{Change: _changeID("124"), Base: 0},
+			},
+			current:  1,
+			collapse: true,
+			want: joinLines(
+				"- #123",
+				"    - #124 ◀",
+			),
+		},
 	}
 
 	for _, tt := range tests {
@@ -102,7 +122,10 @@ func TestGenerateStackComment(t *testing.T) {
 			}
 
 			want := _commentHeader + tt.want + _commentFooter
-			got := generateStackComment(tt.graph, tt.current)
+			if tt.collapse {
+				want = _collapsedCommentHeader + tt.want + _collapsedCommentFooter + _commentFooter
+			}
+			got := generateStackComment(tt.graph, tt.current, tt.collapse)
 			assert.Equal(t, want, got)
 		})
 	}
@@ -117,3 +140,226 @@ func (s _changeID) String() string {
 func joinLines(lines ...string) string {
 	return strings.Join(lines, "\n") + "\n"
 }
+
+func TestHasChangeRef(t *testing.T) {
+	tests := []struct {
+		msg  string
+		ref  string
+		want bool
+	}{
+		{"Add feature", "#1", false},
+		{"Add feature\n\n#1", "#1", true},
+		{"Add feature (#1)", "#1", true},
+		{"Add feature\n\n#12", "#1", false},
+		{"Add feature\n\nFixes #12, #1.", "#1", true},
+		{"Add feature\n\nabc#1", "#1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.Equal(t, tt.want, hasChangeRef(tt.msg, tt.ref))
+		})
+	}
+}
+
+func TestStackCommentTarget(t *testing.T) {
+	// #1 <- #2 <- #3
+	//       ^
+	//       `--- #4
+	graph := []*stackedChange{
+		{Base: -1, Aboves: []int{1}},
+		{Base: 0, Aboves: []int{2, 3}},
+		{Base: 1},
+		{Base: 1},
+	}
+
+	tests := []struct {
+		give string
+		want []bool // whether graph[i] is included
+	}{
+		{give: "all", want: []bool{true, true, true, true}},
+		{give: "top", want: []bool{false, false, true, true}},
+		{give: "bottom", want: []bool{true, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			target, err := parseStackCommentTarget(tt.give)
+			require.NoError(t, err)
+
+			got := make([]bool, len(graph))
+			for i, c := range graph {
+				got[i] = target.includes(c)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := parseStackCommentTarget("middle")
+		assert.ErrorContains(t, err, `unknown stack comment target "middle"`)
+	})
+}
+
+func TestAugmentBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		prepend  string
+		appendix string
+		want     string
+	}{
+		{
+			name: "Unchanged",
+			body: "Body\n",
+			want: "Body\n",
+		},
+		{
+			name:    "Prepend",
+			body:    "Body\n",
+			prepend: "Header",
+			want:    "Header\n\nBody",
+		},
+		{
+			name:     "Append",
+			body:     "Body\n",
+			appendix: "Footer",
+			want:     "Body\n\nFooter",
+		},
+		{
+			name:     "Both",
+			body:     "Body",
+			prepend:  "Header",
+			appendix: "Footer",
+			want:     "Header\n\nBody\n\nFooter",
+		},
+		{
+			name:     "EmptyBody",
+			prepend:  "Header",
+			appendix: "Footer",
+			want:     "Header\n\nFooter",
+		},
+		{
+			name:     "AlreadyPresent",
+			body:     "Header\n\nBody\n\nFooter\n",
+			prepend:  "Header",
+			appendix: "Footer",
+			want:     "Header\n\nBody\n\nFooter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, augmentBody(tt.body, tt.prepend, tt.appendix))
+		})
+	}
+}
+
+func TestPublishQueue(t *testing.T) {
+	branches := []string{"a", "b", "c", "d"}
+	q := newPublishQueue(branches)
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	// Start in reverse order so that
+	// only the queue can put them in order.
+	for i := len(branches) - 1; i >= 0; i-- {
+		name := branches[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer q.Done(name)
+
+			// "c" doesn't publish anything,
+			// but must not hold up "d".
+			if name == "c" {
+				return
+			}
+
+			assert.NoError(t, q.Wait(context.Background(), name))
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"a", "b", "d"}, order)
+}
+
+func TestPublishQueue_canceled(t *testing.T) {
+	q := newPublishQueue([]string{"a", "b"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, q.Wait(ctx, "b"), context.Canceled)
+
+	// Unknown branches and the first branch never wait.
+	assert.NoError(t, q.Wait(ctx, "a"))
+	assert.NoError(t, q.Wait(ctx, "x"))
+}
+
+func TestPublishQueue_nil(t *testing.T) {
+	var q *publishQueue
+	assert.NoError(t, q.Wait(context.Background(), "a"))
+	q.Done("a")
+}
+
+func TestMemoizedValue(t *testing.T) {
+	var (
+		m     memoizedValue[string]
+		calls int
+	)
+
+	_, err := m.Get(func() (string, error) {
+		calls++
+		return "", assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	// Failures aren't remembered.
+	_, err = m.Get(func() (string, error) {
+		calls++
+		return "", assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	got, err := m.Get(func() (string, error) {
+		calls++
+		return "alice", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got)
+
+	// Successes are.
+	got, err = m.Get(func() (string, error) {
+		calls++
+		return "bob", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got)
+	assert.Equal(t, "alice", m.Require())
+	assert.Equal(t, 3, calls)
+}
+
+func TestTemplatesWithTimeout(t *testing.T) {
+	tmpl := &forge.ChangeTemplate{Filename: "PULL_REQUEST_TEMPLATE.md"}
+
+	t.Run("Listed", func(t *testing.T) {
+		ch := make(chan []*forge.ChangeTemplate, 1)
+		ch <- []*forge.ChangeTemplate{tmpl}
+
+		got := <-templatesWithTimeout(logtest.New(t), ch, time.Minute)
+		assert.Equal(t, []*forge.ChangeTemplate{tmpl}, got)
+	})
+
+	t.Run("TimedOut", func(t *testing.T) {
+		ch := make(chan []*forge.ChangeTemplate) // never sent
+		got, ok := <-templatesWithTimeout(logtest.New(t), ch, time.Millisecond)
+		assert.False(t, ok)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateFrontmatter holds metadata declared at the top of a change template
+// between "---" lines, e.g.:
+//
+//	---
+//	title: "[feature] "
+//	labels: enhancement, needs-review
+//	---
+//	## Summary
+//
+// The keys follow the conventions of GitHub issue templates.
+type templateFrontmatter struct {
+	// Title is prefixed to the titles of change requests
+	// that use the template.
+	Title string `yaml:"title"`
+
+	// Labels are added to change requests that use the template.
+	Labels templateLabels `yaml:"labels"`
+}
+
+// templateLabels is a list of labels in template frontmatter.
+// It may be written as a YAML list or as a comma-separated string.
+type templateLabels []string
+
+func (ls *templateLabels) UnmarshalYAML(node *yaml.Node) error {
+	var items []string
+	if node.Kind == yaml.ScalarNode {
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		items = strings.Split(s, ",")
+	} else if err := node.Decode(&items); err != nil {
+		return err
+	}
+
+	*ls = (*ls)[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*ls = append(*ls, item)
+		}
+	}
+	return nil
+}
+
+// ApplyTitle adds the title prefix to the given title
+// unless it's already present.
+func (fm *templateFrontmatter) ApplyTitle(title string) string {
+	if fm.Title == "" || strings.HasPrefix(title, fm.Title) {
+		return title
+	}
+	return fm.Title + title
+}
+
+// splitTemplateFrontmatter splits a change template into its frontmatter
+// and the remaining body.
+//
+// If the template does not start with a frontmatter block,
+// it's returned unchanged with empty frontmatter.
+func splitTemplateFrontmatter(tmpl string) (templateFrontmatter, string, error) {
+	var fm templateFrontmatter
+	rest, ok := strings.CutPrefix(tmpl, "---\n")
+	if !ok {
+		return fm, tmpl, nil
+	}
+
+	var raw string
+	switch {
+	case strings.HasPrefix(rest, "---\n"):
+		// Empty frontmatter.
+		raw, rest = "", rest[len("---\n"):]
+	default:
+		idx := strings.Index(rest, "\n---\n")
+		if idx < 0 {
+			if !strings.HasSuffix(rest, "\n---") {
+				// No closing delimiter. This isn't frontmatter.
+				return fm, tmpl, nil
+			}
+			idx = len(rest) - len("\n---")
+		}
+		raw = rest[:idx]
+		rest = strings.TrimPrefix(rest[idx+len("\n---"):], "\n")
+	}
+
+	if err := yaml.Unmarshal([]byte(raw), &fm); err != nil {
+		return fm, tmpl, fmt.Errorf("parse frontmatter: %w", err)
+	}
+
+	return fm, strings.TrimLeft(rest, "\n"), nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTemplateFrontmatter(t *testing.T) {
+	tests := []struct {
+		name string
+		give string
+
+		wantFrontmatter templateFrontmatter
+		wantBody        string
+	}{
+		{
+			name:     "NoFrontmatter",
+			give:     "## Summary\n",
+			wantBody: "## Summary\n",
+		},
+		{
+			name: "CommaLabels",
+			give: "---\ntitle: \"[feat] \"\nlabels: a, b,\n---\n\n## Summary\n",
+			wantFrontmatter: templateFrontmatter{
+				Title:  "[feat] ",
+				Labels: templateLabels{"a", "b"},
+			},
+			wantBody: "## Summary\n",
+		},
+		{
+			name: "ListLabels",
+			give: "---\nlabels:\n  - a\n  - b\n---\n## Summary\n",
+			wantFrontmatter: templateFrontmatter{
+				Labels: templateLabels{"a", "b"},
+			},
+			wantBody: "## Summary\n",
+		},
+		{
+			name:     "Empty",
+			give:     "---\n---\n## Summary\n",
+			wantBody: "## Summary\n",
+		},
+		{
+			name:            "OnlyFrontmatter",
+			give:            "---\ntitle: x\n---",
+			wantFrontmatter: templateFrontmatter{Title: "x"},
+		},
+		{
+			name:     "Unterminated",
+			give:     "---\nnot frontmatter\n",
+			wantBody: "---\nnot frontmatter\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := splitTemplateFrontmatter(tt.give)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrontmatter, fm)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestSplitTemplateFrontmatter_error(t *testing.T) {
+	_, _, err := splitTemplateFrontmatter("---\ntitle: [\n---\nbody")
+	assert.ErrorContains(t, err, "parse frontmatter")
+}
+
+func TestTemplateFrontmatter_ApplyTitle(t *testing.T) {
+	fm := templateFrontmatter{Title: "[feat] "}
+	assert.Equal(t, "[feat] Add x", fm.ApplyTitle("Add x"))
+	assert.Equal(t, "[feat] Add x", fm.ApplyTitle("[feat] Add x"))
+
+	var empty templateFrontmatter
+	assert.Equal(t, "Add x", empty.ApplyTitle("Add x"))
+}
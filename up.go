@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/must"
+	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/text"
 	"go.abhg.dev/gs/internal/ui"
 	"go.abhg.dev/gs/internal/ui/widget"
@@ -37,14 +40,16 @@ func (cmd *upCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions)
 		return fmt.Errorf("get current branch: %w", err)
 	}
 
+	tree, err := svc.UpstackTree(ctx, current)
+	if err != nil {
+		return fmt.Errorf("list branches above %v: %w", current, err)
+	}
+
 	var branch string
+	node := tree
 outer:
 	for range cmd.N {
-		aboves, err := svc.ListAbove(ctx, current)
-		if err != nil {
-			return fmt.Errorf("list branches above %v: %w", current, err)
-		}
-
+		aboves := node.Children
 		switch len(aboves) {
 		case 0:
 			if branch != "" {
@@ -53,9 +58,9 @@ outer:
 				// we're done.
 				break outer
 			}
-			return fmt.Errorf("%v: no branches found upstack", current)
+			return fmt.Errorf("%v: no branches found upstack", node.Name)
 		case 1:
-			branch = aboves[0]
+			node = aboves[0]
 		default:
 			desc := "There are multiple branches above the current branch."
 			if !opts.Prompt {
@@ -66,8 +71,8 @@ outer:
 			items := make([]widget.BranchTreeItem, len(aboves))
 			for i, b := range aboves {
 				items[i] = widget.BranchTreeItem{
-					Branch: b,
-					Base:   current,
+					Branch: b.Name,
+					Base:   node.Name,
 				}
 			}
 
@@ -79,9 +84,15 @@ outer:
 			if err := ui.Run(prompt); err != nil {
 				return fmt.Errorf("a branch is required: %w", err)
 			}
+
+			idx := slices.IndexFunc(aboves, func(n *spice.BranchNode) bool {
+				return n.Name == branch
+			})
+			must.NotBeEqualf(-1, idx, "selected branch %v must be above %v", branch, node.Name)
+			node = aboves[idx]
 		}
 
-		current = branch
+		branch = node.Name
 	}
 
 	if cmd.DryRun {
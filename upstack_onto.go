@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
@@ -21,6 +22,7 @@ func (*upstackOntoCmd) Help() string {
 	return text.Dedent(`
 		The current branch and its upstack will move onto the new base.
 		Use 'gs branch onto' to leave the branch's upstack alone.
+		The new base cannot be the branch itself or a branch upstack from it.
 		Use --branch to move a different branch than the current one.
 
 		A prompt will allow selecting the new base.
@@ -68,9 +70,15 @@ func (cmd *upstackOntoCmd) Run(ctx context.Context, log *log.Logger, opts *globa
 			return fmt.Errorf("cannot proceed without a destination branch: %w", errNoPrompt)
 		}
 
+		// The branch can't be moved onto its own upstack.
+		upstacks, err := svc.ListUpstack(ctx, cmd.Branch)
+		if err != nil {
+			return fmt.Errorf("list upstack: %w", err)
+		}
+
 		cmd.Onto, err = (&branchPrompt{
 			Disabled: func(b git.LocalBranch) bool {
-				return b.Name == cmd.Branch
+				return slices.Contains(upstacks, b.Name)
 			},
 			TrackedOnly: true,
 			Default:     branch.Base,
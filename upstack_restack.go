@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
@@ -56,14 +57,24 @@ func (cmd *upstackRestackCmd) Run(ctx context.Context, log *log.Logger, opts *gl
 		}
 	}
 
+	// Branches in the list that have already been restacked
+	// as part of a chain with a branch below them.
+	restacked := make(map[string]struct{}, len(upstacks))
+	inList := func(branch string) bool {
+		return slices.Contains(upstacks, branch)
+	}
+
 loop:
 	for _, upstack := range upstacks {
 		// Trunk never needs to be restacked.
 		if upstack == store.Trunk() {
 			continue loop
 		}
+		if _, ok := restacked[upstack]; ok {
+			continue loop
+		}
 
-		res, err := svc.Restack(ctx, upstack)
+		res, err := svc.RestackChain(ctx, upstack, inList)
 		if err != nil {
 			var rebaseErr *git.RebaseInterruptError
 			switch {
@@ -88,7 +99,12 @@ loop:
 			}
 		}
 
-		log.Infof("%v: restacked on %v", upstack, res.Base)
+		base := res.Base
+		for _, name := range res.Branches {
+			restacked[name] = struct{}{}
+			log.Infof("%v: restacked on %v", name, base)
+			base = name
+		}
 	}
 
 	// On success, check out the original branch.
@@ -75,6 +75,12 @@ func (cmd *upstackSubmitCmd) Run(
 		upstacks = upstacks[1:]
 	}
 
+	// Uncommitted changes stop the whole submit,
+	// so check for them before pushing anything.
+	if err := cmd.verifyWorktree(ctx, repo, log, upstacks); err != nil {
+		return err
+	}
+
 	// TODO: generalize into a service-level method
 	// TODO: separate preparation of the stack from submission
 	var session submitSession
@@ -88,16 +94,28 @@ func (cmd *upstackSubmitCmd) Run(
 		}
 	}
 
-	if cmd.DryRun {
-		return nil
+	commentOpts, err := cmd.stackCommentOptions(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	limit, err := session.limiter(ctx, repo)
+	if err != nil {
+		return err
 	}
 
-	return syncStackComments(
+	if err := syncStackComments(
 		ctx,
 		store,
 		svc,
 		session.remoteRepo.Require(),
+		limit,
 		log,
 		session.branches,
-	)
+		commentOpts,
+	); err != nil {
+		return err
+	}
+
+	return cmd.checkPending(&session)
 }